@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// SetAway lowers the setpoint to ecoTemp (in Config.TemperatureUnit) and saves the current
+// user mode and setpoints so ClearAway can restore them.
+//
+// The eco temperature is applied as a manual override. In clock mode the program replaces
+// an override at its next switchpoint, so with untilReturn set the user mode is also
+// switched to manual, keeping the eco temperature until ClearAway. Without untilReturn,
+// clock mode resumes the schedule on its own at the next switchpoint.
+//
+// The saved state is kept in memory and, if Config.AwayStateFile is set, written to that file.
+// Calling SetAway while already away keeps the originally saved state.
+func (c *Client) SetAway(ctx context.Context, untilReturn bool, ecoTemp float64) error {
+	ctx = ensureRequestID(ctx)
+
+	state, err := c.loadAwayState()
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		status, err := c.Status(ctx, false)
+		if err != nil {
+			return fmt.Errorf("failed to get status: %w", err)
+		}
+		if status.Celsius != nil {
+			status = status.Celsius
+		}
+
+		state = &types.AwayState{
+			UserMode:         status.UserMode,
+			ManualSetpoint:   status.TempManualSetpoint,
+			TempOverride:     status.TempOverride,
+			OverrideSetpoint: status.TempOverrideTempSetpoint,
+			Since:            time.Now(),
+		}
+		if err := c.saveAwayState(state); err != nil {
+			return err
+		}
+	}
+
+	c.log(ctx).Info("entering away mode", "eco_temp", ecoTemp, "until_return", untilReturn, "previous_mode", state.UserMode)
+
+	if err := c.SetTemperature(ctx, ecoTemp); err != nil {
+		return err
+	}
+
+	if untilReturn && state.UserMode == types.UserModeClock {
+		if err := c.SetUserMode(ctx, types.UserModeManual); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClearAway restores the user mode and setpoints saved by SetAway.
+// It returns ErrNotAway if there is no saved state in memory or in Config.AwayStateFile.
+func (c *Client) ClearAway(ctx context.Context) error {
+	ctx = ensureRequestID(ctx)
+
+	state, err := c.loadAwayState()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return ErrNotAway
+	}
+
+	c.log(ctx).Info("leaving away mode", "restore_mode", state.UserMode, "away_since", state.Since)
+
+	if state.TempOverride {
+		if err := c.setTemperatureCelsius(ctx, state.OverrideSetpoint); err != nil {
+			return err
+		}
+	} else {
+		if err := c.Put(ctx, circuitURI(ctx, types.URIManualTempOverrideStatus), map[string]string{"value": "off"}); err != nil {
+			return fmt.Errorf("failed to disable manual override: %w", err)
+		}
+	}
+
+	if err := c.Put(ctx, circuitURI(ctx, types.URIManualSetpoint), map[string]interface{}{"value": state.ManualSetpoint}); err != nil {
+		return fmt.Errorf("failed to restore manual temperature: %w", err)
+	}
+
+	if state.UserMode != "" {
+		if err := c.SetUserMode(ctx, state.UserMode); err != nil {
+			return err
+		}
+	}
+
+	return c.saveAwayState(nil)
+}
+
+func (c *Client) loadAwayState() (*types.AwayState, error) {
+	c.awayMu.Lock()
+	defer c.awayMu.Unlock()
+
+	if c.away != nil || c.config.AwayStateFile == "" {
+		return c.away, nil
+	}
+
+	data, err := os.ReadFile(c.config.AwayStateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read away state: %w", err)
+	}
+
+	var state types.AwayState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid away state file: %w", err)
+	}
+	c.away = &state
+
+	return c.away, nil
+}
+
+// saveAwayState records state in memory and in Config.AwayStateFile; nil clears both.
+func (c *Client) saveAwayState(state *types.AwayState) error {
+	c.awayMu.Lock()
+	defer c.awayMu.Unlock()
+
+	c.away = state
+
+	if c.config.AwayStateFile == "" {
+		return nil
+	}
+
+	if state == nil {
+		if err := os.Remove(c.config.AwayStateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove away state: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode away state: %w", err)
+	}
+	if err := os.WriteFile(c.config.AwayStateFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write away state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func awayTestDevice(t *testing.T) (*Client, *fakeDevice) {
+	t.Helper()
+
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{
+		"UMD": "clock",
+		"MMT": "19.0",
+		"TOR": "off",
+		"TOT": "0",
+	})
+	d.setValue(types.URIUserMode, "clock")
+	d.setValue(types.URIManualSetpoint, 19.0)
+	d.setValue(types.URIManualTempOverrideStatus, "off")
+
+	return c, d
+}
+
+func deviceValue(t *testing.T, c *Client, uri string) interface{} {
+	t.Helper()
+
+	data, err := c.Get(t.Context(), uri)
+	if err != nil {
+		t.Fatalf("Get %s failed: %v", uri, err)
+	}
+	return data.(map[string]interface{})["value"]
+}
+
+func TestSetAwayClearAwayRestores(t *testing.T) {
+	c, _ := awayTestDevice(t)
+
+	if err := c.SetAway(t.Context(), true, 15); err != nil {
+		t.Fatalf("SetAway failed: %v", err)
+	}
+
+	if v := deviceValue(t, c, types.URIManualSetpoint); v != 15.0 {
+		t.Errorf("Expected eco setpoint 15, got %v", v)
+	}
+	if v := deviceValue(t, c, types.URIUserMode); v != "manual" {
+		t.Errorf("Expected manual mode while away, got %v", v)
+	}
+
+	if err := c.ClearAway(t.Context()); err != nil {
+		t.Fatalf("ClearAway failed: %v", err)
+	}
+
+	if v := deviceValue(t, c, types.URIManualSetpoint); v != 19.0 {
+		t.Errorf("Expected original setpoint 19, got %v", v)
+	}
+	if v := deviceValue(t, c, types.URIManualTempOverrideStatus); v != "off" {
+		t.Errorf("Expected override restored to off, got %v", v)
+	}
+	if v := deviceValue(t, c, types.URIUserMode); v != "clock" {
+		t.Errorf("Expected clock mode restored, got %v", v)
+	}
+
+	if err := c.ClearAway(t.Context()); !errors.Is(err, ErrNotAway) {
+		t.Errorf("Expected ErrNotAway on second ClearAway, got %v", err)
+	}
+}
+
+func TestAwayStatePersisted(t *testing.T) {
+	c, _ := awayTestDevice(t)
+	path := filepath.Join(t.TempDir(), "away.json")
+	c.config.AwayStateFile = path
+
+	if err := c.SetAway(t.Context(), false, 15); err != nil {
+		t.Fatalf("SetAway failed: %v", err)
+	}
+
+	// Simulate a restart by dropping the in-memory state.
+	c.away = nil
+
+	state, err := c.loadAwayState()
+	if err != nil || state == nil {
+		t.Fatalf("Expected persisted away state, got %v (err %v)", state, err)
+	}
+	if state.UserMode != "clock" || state.ManualSetpoint != 19 {
+		t.Errorf("Unexpected persisted state: %+v", state)
+	}
+
+	if err := c.ClearAway(t.Context()); err != nil {
+		t.Fatalf("ClearAway failed: %v", err)
+	}
+	if v := deviceValue(t, c, types.URIManualSetpoint); v != 19.0 {
+		t.Errorf("Expected original setpoint 19, got %v", v)
+	}
+}
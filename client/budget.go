@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RetryBudget limits the request attempts, including retries, of all requests made with
+// a context, so that a composite operation such as SetTemperature or a user-defined
+// sequence of Get and Put calls respects one attempt and time limit instead of each
+// sub-request retrying up to Config.MaxRetries on its own.
+//
+// A RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	mu       sync.Mutex
+	left     int
+	deadline time.Time
+}
+
+// NewRetryBudget returns a budget of the given number of attempts. If timeout is
+// positive, no attempt is started once it has elapsed and attempts are cut short
+// at that point.
+func NewRetryBudget(attempts int, timeout time.Duration) *RetryBudget {
+	b := &RetryBudget{left: attempts}
+	if timeout > 0 {
+		b.deadline = time.Now().Add(timeout)
+	}
+	return b
+}
+
+// Remaining returns the number of attempts left in the budget.
+func (b *RetryBudget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.left
+}
+
+// spend takes one attempt from the budget. A nil budget is unlimited.
+func (b *RetryBudget) spend() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.left <= 0 || (!b.deadline.IsZero() && !time.Now().Before(b.deadline)) {
+		return ErrRetryBudgetExhausted
+	}
+	b.left--
+	return nil
+}
+
+// attemptTimeout caps the per-attempt timeout to the time left in the budget.
+func (b *RetryBudget) attemptTimeout(timeout time.Duration) time.Duration {
+	if b == nil || b.deadline.IsZero() {
+		return timeout
+	}
+	if left := time.Until(b.deadline); left < timeout {
+		return left
+	}
+	return timeout
+}
+
+// budgetError combines an exhausted budget with the error of the last attempt, if any.
+func budgetError(err, lastErr error) error {
+	if lastErr == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", err, lastErr)
+}
+
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context whose requests draw their attempts from b.
+// Operations that already carry a budget share it rather than starting their own.
+func WithRetryBudget(ctx context.Context, b *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, b)
+}
+
+// RetryBudgetFromContext returns the budget set by WithRetryBudget, if any.
+func RetryBudgetFromContext(ctx context.Context) (*RetryBudget, bool) {
+	b, ok := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return b, ok && b != nil
+}
+
+func retryBudget(ctx context.Context) *RetryBudget {
+	b, _ := RetryBudgetFromContext(ctx)
+	return b
+}
+
+// ensureRetryBudget returns ctx unchanged if it carries a budget, or a child context
+// with one allowing a single attempt per request plus Config.MaxRetries retries in total.
+// Composite operations call it so their sub-requests share one retry allowance.
+func (c *Client) ensureRetryBudget(ctx context.Context, requests int) context.Context {
+	if _, ok := RetryBudgetFromContext(ctx); ok {
+		return ctx
+	}
+	return WithRetryBudget(ctx, NewRetryBudget(requests+c.config.MaxRetries, 0))
+}
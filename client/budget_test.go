@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestSetTemperatureSharesRetryBudget(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.RetryTimeout = 20 * time.Millisecond
+
+	// Each PUT times out twice before succeeding: 9 attempts without a shared budget.
+	for _, uri := range []string{types.URIManualSetpoint, types.URIManualTempOverrideStatus, types.URIManualTempOverrideTemp} {
+		d.drop(uri, 2)
+	}
+
+	err := c.SetTemperature(t.Context(), 21)
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("Expected ErrRetryBudgetExhausted, got %v", err)
+	}
+
+	budget := 3 + c.config.MaxRetries
+	if puts := d.requestsFor("PUT"); len(puts) > budget {
+		t.Errorf("Expected at most %d attempts across the three PUTs, got %d", budget, len(puts))
+	}
+}
+
+func TestRetryBudgetFromCaller(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.RetryTimeout = 20 * time.Millisecond
+	d.setValue(types.URIUserMode, "clock")
+	d.drop(types.URIUserMode, 1)
+
+	budget := NewRetryBudget(2, 0)
+	ctx := WithRetryBudget(t.Context(), budget)
+
+	if _, err := c.Get(ctx, types.URIUserMode); err != nil {
+		t.Fatalf("Get within budget failed: %v", err)
+	}
+	if budget.Remaining() != 0 {
+		t.Errorf("Expected the retry to use up the budget, %d left", budget.Remaining())
+	}
+
+	if _, err := c.Get(ctx, types.URIUserMode); !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Errorf("Expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	if gets := d.requestsFor("GET"); len(gets) != 2 {
+		t.Errorf("Expected no request once the budget is spent, got %d", len(gets))
+	}
+}
+
+func TestRetryBudgetTimeLimit(t *testing.T) {
+	budget := NewRetryBudget(10, 20*time.Millisecond)
+	if err := budget.spend(); err != nil {
+		t.Fatalf("spend before the deadline failed: %v", err)
+	}
+	if got := budget.attemptTimeout(time.Minute); got > 20*time.Millisecond {
+		t.Errorf("attemptTimeout = %v, want capped to the budget", got)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := budget.spend(); !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Errorf("Expected ErrRetryBudgetExhausted after the time limit, got %v", err)
+	}
+
+	if _, ok := RetryBudgetFromContext(context.Background()); ok {
+		t.Error("Expected no budget in a plain context")
+	}
+}
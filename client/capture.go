@@ -0,0 +1,111 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/kradalby/nefit-go/protocol"
+)
+
+// redactedSerial replaces the device serial number in captured traffic.
+const redactedSerial = "REDACTED"
+
+// CaptureEntry is one request/response pair recorded by SetCapture.
+// Captures are written as JSON lines and can be read back with ReadCapture.
+type CaptureEntry struct {
+	Method string `json:"method"`
+	URI    string `json:"uri"`
+
+	// RequestEncrypted and RequestDecrypted hold the PUT body; both are empty for GETs.
+	RequestEncrypted string `json:"request_encrypted,omitempty"`
+	RequestDecrypted string `json:"request_decrypted,omitempty"`
+
+	StatusCode  int    `json:"status_code"`
+	Status      string `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+
+	// ResponseEncrypted is the body as received. ResponseDecrypted is empty if the body is
+	// not encrypted, as with plain-text error responses.
+	ResponseEncrypted string `json:"response_encrypted,omitempty"`
+	ResponseDecrypted string `json:"response_decrypted,omitempty"`
+}
+
+type captureSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// SetCapture records every request/response pair to w as JSON lines, with both the
+// encrypted and decrypted bodies. The serial number is replaced by "REDACTED";
+// the encrypted bodies still require the access key and password to decrypt, so
+// captures are useful as test vectors for encryption compatibility.
+// Pass nil to stop capturing.
+func (c *Client) SetCapture(w io.Writer) {
+	if w == nil {
+		c.captureSink.Store(nil)
+		return
+	}
+	c.captureSink.Store(&captureSink{enc: json.NewEncoder(w)})
+}
+
+// capture records a completed exchange if capturing is enabled.
+func (c *Client) capture(method, uri, requestEncrypted, requestDecrypted string, resp *protocol.HTTPResponse) {
+	sink := c.captureSink.Load()
+	if sink == nil {
+		return
+	}
+
+	entry := CaptureEntry{
+		Method:            method,
+		URI:               uri,
+		RequestEncrypted:  requestEncrypted,
+		RequestDecrypted:  requestDecrypted,
+		StatusCode:        resp.StatusCode,
+		Status:            resp.Status,
+		ContentType:       resp.ContentType,
+		ResponseEncrypted: resp.Body,
+	}
+	if resp.Body != "" {
+		// Error bodies may be plain text; keep their decryption only if it reads as text.
+		decrypted, err := c.encryptor.DecryptAndStrip(resp.Body)
+		if err == nil && (resp.StatusCode/100 == 2 || isPrintable(decrypted)) {
+			entry.ResponseDecrypted = decrypted
+		}
+	}
+	c.redactCapture(&entry)
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if err := sink.enc.Encode(entry); err != nil {
+		c.logger.Load().Warn("failed to write capture entry", "uri", uri, "error", err)
+	}
+}
+
+func (c *Client) redactCapture(entry *CaptureEntry) {
+	serial := c.config.SerialNumber
+	for _, field := range []*string{&entry.URI, &entry.RequestDecrypted, &entry.ResponseDecrypted} {
+		*field = strings.ReplaceAll(*field, serial, redactedSerial)
+	}
+}
+
+// ReadCapture reads the JSON lines written by SetCapture.
+func ReadCapture(r io.Reader) ([]CaptureEntry, error) {
+	var entries []CaptureEntry
+
+	dec := json.NewDecoder(r)
+	for {
+		var entry CaptureEntry
+		err := dec.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid capture entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestCapturePlainTextErrorResponse(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.queue("/test/value", fakeResponse{StatusCode: 404, Status: "Not Found", Encrypted: "NotFound"})
+
+	var buf bytes.Buffer
+	c.SetCapture(&buf)
+
+	var apiErr *APIError
+	if _, err := c.Get(t.Context(), "/test/value"); !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Fatalf("Expected a 404 APIError, got %v", err)
+	}
+	c.SetCapture(nil)
+
+	entries, err := ReadCapture(&buf)
+	if err != nil {
+		t.Fatalf("ReadCapture failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 capture entry, got %d", len(entries))
+	}
+	if e := entries[0]; e.StatusCode != 404 || e.ResponseEncrypted != "NotFound" || e.ResponseDecrypted != "" {
+		t.Errorf("Expected the raw body without a decryption, got %+v", e)
+	}
+}
+
+func TestCaptureReplayRoundTrip(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set(types.URIStatus, `{"id":"/ecus/rrc/uiStatus","value":{"IHT":"20.5","serial":"123456789"}}`)
+
+	var buf bytes.Buffer
+	c.SetCapture(&buf)
+
+	original, err := c.Get(t.Context(), types.URIStatus)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := c.Put(t.Context(), types.URIUserMode, map[string]string{"value": "manual"}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	c.SetCapture(nil)
+
+	if strings.Contains(buf.String(), "123456789") {
+		t.Errorf("Capture contains the serial number: %s", buf.String())
+	}
+
+	entries, err := ReadCapture(&buf)
+	if err != nil {
+		t.Fatalf("ReadCapture failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 capture entries, got %d", len(entries))
+	}
+
+	get, put := entries[0], entries[1]
+	if get.Method != "GET" || get.URI != types.URIStatus || get.StatusCode != 200 || get.ResponseEncrypted == "" {
+		t.Errorf("Unexpected GET entry: %+v", get)
+	}
+	if !strings.Contains(get.ResponseDecrypted, `"IHT":"20.5"`) {
+		t.Errorf("Expected decrypted response in GET entry, got %q", get.ResponseDecrypted)
+	}
+	if put.Method != "PUT" || put.RequestDecrypted != `{"value":"manual"}` || put.StatusCode != 204 {
+		t.Errorf("Unexpected PUT entry: %+v", put)
+	}
+
+	replay, rd := replayCapture(t, entries)
+
+	replayed, err := replay.Get(t.Context(), types.URIStatus)
+	if err != nil {
+		t.Fatalf("Replayed Get failed: %v", err)
+	}
+	if value := replayed.(map[string]interface{})["value"].(map[string]interface{}); value["IHT"] != "20.5" {
+		t.Errorf("Replayed Get returned %v, original %v", replayed, original)
+	}
+
+	if err := replay.Put(t.Context(), types.URIUserMode, map[string]string{"value": "manual"}); err != nil {
+		t.Fatalf("Replayed Put failed: %v", err)
+	}
+
+	// ECB is deterministic, so the replayed client must produce the recorded ciphertext.
+	encrypted, err := replay.encryptor.Encrypt(put.RequestDecrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted != put.RequestEncrypted {
+		t.Errorf("Encrypted request %q does not match captured %q", encrypted, put.RequestEncrypted)
+	}
+	if puts := rd.requestsFor("PUT"); len(puts) != 1 || puts[0].Body != put.RequestDecrypted {
+		t.Errorf("Unexpected replayed PUT requests: %+v", puts)
+	}
+}
@@ -2,27 +2,49 @@ package client
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kradalby/nefit-go/crypto"
 	"github.com/kradalby/nefit-go/protocol"
+	"github.com/kradalby/nefit-go/types"
 	xmpp "github.com/xmppo/go-xmpp"
 )
 
 // EventHandler is called when unsolicited messages are received from the backend
 type EventHandler func(uri string, data interface{})
 
+// StanzaLogger receives the raw HTTP-over-XMPP body of every sent and received chat
+// message. direction is StanzaSent or StanzaReceived. Payloads are still encrypted.
+type StanzaLogger func(direction, raw string)
+
+// Stanza directions passed to a StanzaLogger.
+const (
+	StanzaSent     = "sent"
+	StanzaReceived = "received"
+)
+
 // PushNotification represents a queued push notification
 type PushNotification struct {
 	URI  string
 	Data interface{}
+
+	// Connection is set, and URI and Data are empty, on the connection markers of
+	// StreamEvents with WithConnectionMarkers.
+	Connection *ConnectionEvent
 }
 
 // Client represents an active connection to the Nefit Easy backend.
@@ -30,25 +52,68 @@ type PushNotification struct {
 type Client struct {
 	config    Config
 	encryptor *crypto.Encryptor
+	builder   protocol.MessageBuilder
 	queue     *RequestQueue
 
-	xmppClient *xmpp.Client
+	xmppClient transport
+	dial       dialFunc
+	// now is the host clock, replaceable in tests.
+	now        func() time.Time
 	connMu     sync.RWMutex
+	connCancel context.CancelFunc
+	connWg     sync.WaitGroup
+
+	// Unix nanoseconds of the current connection and of the last presence received on it.
+	connectedAt  atomic.Int64
+	lastPresence atomic.Int64
+
+	// reconnectMu serializes Connect, Reconnect and Close.
+	reconnectMu sync.Mutex
 
 	// Backend limitation: only one concurrent request allowed, so we need request/response correlation
 	pendingRequests map[string]chan *protocol.HTTPResponse
 	pendingErrors   map[string]chan error
 	pendingMu       sync.RWMutex
 
-	eventHandlers        []EventHandler
+	eventHandlers        []*EventHandler
 	eventHandlersMu      sync.RWMutex
 	pushNotificationChan chan PushNotification
-
-	logger *slog.Logger
-
-	ctx    context.Context
-	cancel context.CancelFunc
-	wg     sync.WaitGroup
+	// handlerSlots bounds concurrent handler calls when Config.HandlerConcurrency is set.
+	handlerSlots chan struct{}
+	// handlerWg tracks running handler calls so Close can wait for them, for at most
+	// handlerShutdownTimeout.
+	handlerWg              sync.WaitGroup
+	handlerShutdownTimeout time.Duration
+
+	errCh chan error
+
+	// connWatchers are told about connection losses; see watchConnectionLoss.
+	connWatchers   []*func(connectionLoss)
+	connWatchersMu sync.Mutex
+
+	away   *types.AwayState
+	awayMu sync.Mutex
+
+	// userMode caches the user mode of the default circuit for mode-dependent endpoints.
+	userMode atomic.Pointer[cachedUserMode]
+	// quickStatus caches the last QuickStatus result for a few seconds.
+	quickStatus atomic.Pointer[cachedQuickStatus]
+	// systemInfo caches the static result of SystemInfo.
+	systemInfo atomic.Pointer[types.SystemInfo]
+
+	logger       atomic.Pointer[slog.Logger]
+	stanzaLogger atomic.Pointer[StanzaLogger]
+	captureSink  atomic.Pointer[captureSink]
+	timingHook   atomic.Pointer[TimingHook]
+
+	shutdownHooks   []func() error
+	shutdownHooksMu sync.Mutex
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	pushOnce  sync.Once
 }
 
 // NewClient creates a new Nefit Easy client with the given configuration.
@@ -59,7 +124,13 @@ func NewClient(config Config) (*Client, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	encryptor, err := crypto.NewEncryptor(config.SerialNumber, config.AccessKey, config.Password)
+	var encryptor *crypto.Encryptor
+	var err error
+	if config.MagicKey != "" {
+		encryptor, err = crypto.NewEncryptorWithMagic(config.MagicKey, config.SerialNumber, config.AccessKey, config.Password)
+	} else {
+		encryptor, err = crypto.NewEncryptor(config.SerialNumber, config.AccessKey, config.Password)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create encryptor: %w", err)
 	}
@@ -67,88 +138,351 @@ func NewClient(config Config) (*Client, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	client := &Client{
-		config:               config,
-		encryptor:            encryptor,
-		queue:                NewRequestQueue(),
-		pendingRequests:      make(map[string]chan *protocol.HTTPResponse),
-		pendingErrors:        make(map[string]chan error),
-		pushNotificationChan: make(chan PushNotification, 100),
-		logger:               slog.Default(),
-		ctx:                  ctx,
-		cancel:               cancel,
+		config:    config,
+		encryptor: encryptor,
+		builder: protocol.MessageBuilder{
+			UserAgent:   config.UserAgent,
+			HTTPVersion: config.HTTPVersion,
+			Accept:      config.Accept,
+		},
+		queue:                  NewRequestQueue(),
+		pendingRequests:        make(map[string]chan *protocol.HTTPResponse),
+		pendingErrors:          make(map[string]chan error),
+		pushNotificationChan:   make(chan PushNotification, 100),
+		errCh:                  make(chan error, 10),
+		dial:                   dialXMPP,
+		now:                    time.Now,
+		ctx:                    ctx,
+		cancel:                 cancel,
+		handlerShutdownTimeout: defaultHandlerShutdownTimeout,
+	}
+	if config.HandlerConcurrency > 0 {
+		client.handlerSlots = make(chan struct{}, config.HandlerConcurrency)
 	}
+	client.SetLogger(nil)
 
 	return client, nil
 }
 
+// NewClientFromConnectString creates a client from a connect string; see ParseConnectString for the accepted forms.
+func NewClientFromConnectString(s string) (*Client, error) {
+	config, err := ParseConnectString(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(config)
+}
+
 // SetLogger configures a custom logger for the client.
-// By default, the client uses slog.Default().
+// By default, the client uses slog.Default(). It is safe to call while the client is connected.
+// Every line is tagged with the device serial (an HMAC of it if Config.RedactSerial is set) and
+// Config.LogAttrs.
 func (c *Client) SetLogger(logger *slog.Logger) {
-	c.logger = logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	attrs := make([]any, 0, len(c.config.LogAttrs)+1)
+	attrs = append(attrs, slog.String("serial", c.logSerial()))
+	for _, attr := range c.config.LogAttrs {
+		attrs = append(attrs, attr)
+	}
+	c.logger.Store(logger.With(attrs...))
+}
+
+// logSerial returns the serial number as it appears in logs: as is, or with
+// Config.RedactSerial a short HMAC-SHA256 of it keyed with the password. A plain hash
+// of the few-digit serial could be reversed by trying every number; the keyed one still
+// tells devices apart but cannot be computed without the credentials.
+func (c *Client) logSerial() string {
+	if !c.config.RedactSerial {
+		return c.config.SerialNumber
+	}
+	mac := hmac.New(sha256.New, []byte(c.config.Password))
+	mac.Write([]byte(c.config.SerialNumber))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+// logJID returns jid with the serial number replaced by its logSerial form.
+func (c *Client) logJID(jid string) string {
+	if !c.config.RedactSerial {
+		return jid
+	}
+	return strings.ReplaceAll(jid, c.config.SerialNumber, c.logSerial())
+}
+
+// SetStanzaLogger installs a tap invoked with every raw chat body sent to or received
+// from the backend, before decryption. It gives a wire-level trace without enabling
+// debug logging. Nothing is redacted, but request and response payloads are encrypted.
+// Pass nil to remove the tap.
+func (c *Client) SetStanzaLogger(fn StanzaLogger) {
+	if fn == nil {
+		c.stanzaLogger.Store(nil)
+		return
+	}
+	c.stanzaLogger.Store(&fn)
+}
+
+func (c *Client) tapStanza(direction, raw string) {
+	if fn := c.stanzaLogger.Load(); fn != nil {
+		(*fn)(direction, raw)
+	}
 }
 
 // Connect establishes the XMPP connection and starts background workers.
 // The connection uses STARTTLS (plain TCP upgraded to TLS) as required by Bosch servers.
+// If ctx is done before the connection is established, Connect returns ctx.Err()
+// and the abandoned connection is closed once the dial finishes.
+// Connect calls are serialized; once connected, further calls return ErrAlreadyConnected
+// without dialing, so concurrent callers share one connection. Use Reconnect to replace it.
 func (c *Client) Connect(ctx context.Context) error {
-	c.logger.Info("connecting to Nefit Easy backend",
-		"host", c.config.Host,
-		"jid", c.config.JID())
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
 
-	// Bosch servers require STARTTLS (plain TCP → TLS upgrade), not direct TLS
-	options := xmpp.Options{
-		Host:     fmt.Sprintf("%s:%d", c.config.Host, c.config.Port),
-		User:     c.config.JID(),
-		Password: c.config.AuthPassword(),
-		NoTLS:    true,
-		StartTLS: true,
-		TLSConfig: &tls.Config{
-			ServerName: c.config.Host,
-			MinVersion: tls.VersionTLS12,
-		},
-		InsecureAllowUnencryptedAuth: false,
+	if c.IsConnected() {
+		return ErrAlreadyConnected
+	}
+
+	if err := c.connect(ctx); err != nil {
+		return err
+	}
+
+	c.pushOnce.Do(func() {
+		c.wg.Add(1)
+		go c.pushNotificationWorker()
+	})
+
+	return nil
+}
+
+// Reconnect replaces the XMPP connection with a freshly dialed one without discarding
+// the client. Registered event handlers, the request queue and the configuration are kept,
+// and if there are handlers the new connection is re-announced to the backend (see
+// resubscribe) so pushes resume right away.
+// Requests waiting for a response on the old connection fail with ErrReconnecting.
+func (c *Client) Reconnect(ctx context.Context) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+
+	if c.ctx.Err() != nil {
+		return ErrClientClosed
+	}
+
+	c.logger.Load().Info("reconnecting to Nefit Easy backend")
+
+	c.disconnect()
+	c.notifyError(ErrReconnecting)
+
+	if err := c.connect(ctx); err != nil {
+		return err
+	}
+
+	c.resubscribe()
+	return nil
+}
+
+// resubscribe re-establishes push delivery on a new connection. The backend keeps no
+// subscription state: it forwards pushes to the contact JID while that contact has an
+// available presence, which the old connection's session took with it. Sending a presence
+// right away restores delivery without waiting up to Config.PingInterval for the next
+// keepalive. A failure is reported on the Errors channel; the next keepalive retries it.
+func (c *Client) resubscribe() {
+	c.eventHandlersMu.RLock()
+	handlers := len(c.eventHandlers)
+	c.eventHandlersMu.RUnlock()
+
+	if handlers == 0 {
+		return
+	}
+
+	if err := c.sendPing(); err != nil {
+		c.logger.Load().Warn("failed to resubscribe to push notifications", "error", err)
+		c.reportError(fmt.Errorf("resubscribe failed: %w", err))
+		return
 	}
+	c.logger.Load().Debug("resubscribed to push notifications", "handlers", handlers)
+}
 
-	xmppClient, err := options.NewClient()
+// connect dials the backend and starts the connection-scoped workers.
+// Callers must hold reconnectMu.
+func (c *Client) connect(ctx context.Context) error {
+	c.logger.Load().Info("connecting to Nefit Easy backend",
+		"host", c.config.Host,
+		"jid", c.logJID(c.config.JID()))
+
+	xmppClient, err := c.dialContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create XMPP client: %w", err)
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to create XMPP client: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to create XMPP client: %w", classifyConnectError(err))
 	}
 
+	connCtx, connCancel := context.WithCancel(c.ctx)
+
 	c.connMu.Lock()
 	c.xmppClient = xmppClient
+	c.connCancel = connCancel
 	c.connMu.Unlock()
 
-	c.logger.Info("connected to Nefit Easy backend")
+	c.connectedAt.Store(time.Now().UnixNano())
+	c.lastPresence.Store(0)
+	// Mode changes made while disconnected were not pushed to us.
+	c.forgetUserMode()
+	c.forgetQuickStatus()
 
-	c.wg.Add(3)
-	go c.pingWorker()
-	go c.receiveWorker()
-	go c.pushNotificationWorker()
+	c.logger.Load().Info("connected to Nefit Easy backend")
+
+	c.connWg.Add(2)
+	go c.pingWorker(connCtx)
+	go c.receiveWorker(connCtx)
 
 	return nil
 }
 
-// Close disconnects from the XMPP server and cleans up resources.
-// It gracefully shuts down all background workers and drains any pending push notifications.
-func (c *Client) Close() error {
-	c.logger.Info("closing Nefit Easy client")
+// dialContext runs the blocking dial in a goroutine so that ctx can abandon it;
+// go-xmpp does not take a context and may hang on a stalled TLS handshake.
+// A connection that completes after ctx is done is closed.
+func (c *Client) dialContext(ctx context.Context) (transport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type dialResult struct {
+		conn transport
+		err  error
+	}
+	done := make(chan dialResult, 1)
+
+	dial, options := c.dial, c.xmppOptions()
+	go func() {
+		conn, err := dial(options)
+		done <- dialResult{conn, err}
+	}()
 
-	c.cancel()
+	select {
+	case r := <-done:
+		return r.conn, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-done; r.conn != nil {
+				_ = r.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
 
+// disconnect closes the current connection and waits for its workers to exit.
+// Callers must hold reconnectMu.
+func (c *Client) disconnect() {
 	c.connMu.Lock()
+	if c.connCancel != nil {
+		c.connCancel()
+		c.connCancel = nil
+	}
 	if c.xmppClient != nil {
 		_ = c.xmppClient.Close()
 		c.xmppClient = nil
 	}
 	c.connMu.Unlock()
 
-	close(c.pushNotificationChan)
+	c.connWg.Wait()
+}
 
-	c.wg.Wait()
-	c.queue.Close()
+func (c *Client) xmppOptions() xmpp.Options {
+	// Bosch servers require STARTTLS (plain TCP → TLS upgrade), not direct TLS
+	return xmpp.Options{
+		Host:     fmt.Sprintf("%s:%d", c.config.Host, c.config.Port),
+		User:     c.config.JID(),
+		Password: c.config.AuthPassword(),
+		Resource: c.config.Resource,
+		NoTLS:    true,
+		StartTLS: true,
+		TLSConfig: &tls.Config{
+			ServerName: c.config.Host,
+			MinVersion: tls.VersionTLS12,
+		},
+		InsecureAllowUnencryptedAuth: false,
+	}
+}
 
-	c.logger.Info("closed Nefit Easy client")
+// Close disconnects from the XMPP server and cleans up resources.
+// It gracefully shuts down all background workers, drains any pending push notifications and
+// waits for running event handlers, giving up on them after a few seconds, then runs the
+// hooks added with RegisterShutdownHook and returns their joined errors.
+// Close is idempotent: calls after the first are no-ops and return nil.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.logger.Load().Info("closing Nefit Easy client")
 
-	return nil
+		c.cancel()
+
+		c.reconnectMu.Lock()
+		c.disconnect()
+		c.reconnectMu.Unlock()
+
+		close(c.pushNotificationChan)
+
+		c.wg.Wait()
+		c.waitForHandlers()
+		c.queue.Close()
+
+		err = c.runShutdownHooks()
+
+		c.logger.Load().Info("closed Nefit Easy client")
+	})
+
+	return err
+}
+
+// RegisterShutdownHook adds fn to the functions Close runs once all requests and handlers
+// have finished, such as flushing and closing a capture file. Hooks run in reverse order
+// of registration; a failing hook does not stop the others.
+func (c *Client) RegisterShutdownHook(fn func() error) {
+	c.shutdownHooksMu.Lock()
+	defer c.shutdownHooksMu.Unlock()
+	c.shutdownHooks = append(c.shutdownHooks, fn)
+}
+
+func (c *Client) runShutdownHooks() error {
+	c.shutdownHooksMu.Lock()
+	hooks := c.shutdownHooks
+	c.shutdownHooks = nil
+	c.shutdownHooksMu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](); err != nil {
+			c.logger.Load().Warn("shutdown hook failed", "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Errors returns a channel on which background worker failures (receive and ping errors)
+// and non-fatal warnings, such as a skipped outdoor temperature in Status, are reported.
+// It is intended for observing connection health; per-request errors are still returned by Get and Put.
+// Errors are dropped if the channel is not drained. The channel is never closed.
+func (c *Client) Errors() <-chan error {
+	return c.errCh
+}
+
+func (c *Client) reportError(err error) {
+	c.notifyConnectionLoss(err)
+
+	select {
+	case c.errCh <- err:
+	default:
+		c.logger.Load().Debug("error channel full, dropping error", "error", err)
+	}
+}
+
+// QueueDepth returns the number of requests waiting behind the one in flight; see RequestQueue.Depth.
+func (c *Client) QueueDepth() int {
+	return c.queue.Depth()
 }
 
 // IsConnected checks whether the client currently has an active XMPP connection.
@@ -158,24 +492,55 @@ func (c *Client) IsConnected() bool {
 	return c.xmppClient != nil
 }
 
-func (c *Client) pingWorker() {
-	defer c.wg.Done()
+func (c *Client) pingWorker(ctx context.Context) {
+	defer c.connWg.Done()
 
 	ticker := time.NewTicker(c.config.PingInterval)
 	defer ticker.Stop()
 
+	dead := false
 	for {
 		select {
-		case <-c.ctx.Done():
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			if err := c.sendPing(); err != nil {
-				c.logger.Error("failed to send ping", "error", err)
+				c.logger.Load().Error("failed to send ping", "error", err)
+				c.reportError(fmt.Errorf("ping failed: %w", err))
+			}
+
+			// Report a dead connection once until presence is seen again.
+			silent := c.presenceSilence(time.Now())
+			switch {
+			case c.config.PingTimeout <= 0 || silent < c.config.PingTimeout:
+				dead = false
+			case !dead:
+				dead = true
+				c.logger.Load().Warn("no presence from backend, connection considered dead", "silent_for", silent)
+				c.reportError(fmt.Errorf("%w (no presence for %s)", ErrConnectionDead, silent.Round(time.Millisecond)))
 			}
 		}
 	}
 }
 
+// LastPresence returns when the backend last sent a presence on the current connection,
+// or the zero time if none has been received since connecting.
+func (c *Client) LastPresence() time.Time {
+	if ns := c.lastPresence.Load(); ns != 0 {
+		return time.Unix(0, ns)
+	}
+	return time.Time{}
+}
+
+// presenceSilence returns how long the current connection has gone without a presence.
+func (c *Client) presenceSilence(now time.Time) time.Duration {
+	last := c.lastPresence.Load()
+	if last == 0 {
+		last = c.connectedAt.Load()
+	}
+	return now.Sub(time.Unix(0, last))
+}
+
 func (c *Client) sendPing() error {
 	c.connMu.RLock()
 	client := c.xmppClient
@@ -190,20 +555,24 @@ func (c *Client) sendPing() error {
 		return fmt.Errorf("failed to send presence: %w", err)
 	}
 
-	c.logger.Debug("sent keepalive ping")
+	c.logger.Load().Debug("sent keepalive ping")
 	return nil
 }
 
-func (c *Client) receiveWorker() {
-	defer c.wg.Done()
+func (c *Client) receiveWorker(ctx context.Context) {
+	defer c.connWg.Done()
 
 	for {
 		select {
-		case <-c.ctx.Done():
+		case <-ctx.Done():
 			return
 		default:
 			if err := c.receiveMessage(); err != nil {
-				c.logger.Error("error receiving message", "error", err)
+				if ctx.Err() != nil {
+					return
+				}
+				c.logger.Load().Error("error receiving message", "error", err)
+				c.reportError(fmt.Errorf("%w: %w", ErrReceiveFailed, err))
 				// Add a small delay to prevent tight loop on errors
 				time.Sleep(100 * time.Millisecond)
 			}
@@ -218,13 +587,13 @@ func (c *Client) pushNotificationWorker() {
 		select {
 		case <-c.ctx.Done():
 			// Context cancelled - drain remaining messages before exiting
-			c.logger.Debug("push notification worker shutting down, draining queue")
+			c.logger.Load().Debug("push notification worker shutting down, draining queue")
 			c.drainPushNotifications()
 			return
 		case notification, ok := <-c.pushNotificationChan:
 			if !ok {
 				// Channel closed - drain any remaining messages
-				c.logger.Debug("push notification channel closed")
+				c.logger.Load().Debug("push notification channel closed")
 				return
 			}
 			c.dispatchPushNotification(notification)
@@ -232,13 +601,20 @@ func (c *Client) pushNotificationWorker() {
 	}
 }
 
+// drainPushNotifications dispatches the notifications still queued at shutdown. It stops
+// after handlerShutdownTimeout, dropping the rest, so slow handlers cannot stall Close.
 func (c *Client) drainPushNotifications() {
+	deadline := time.Now().Add(c.handlerShutdownTimeout)
 	for {
 		select {
 		case notification, ok := <-c.pushNotificationChan:
 			if !ok {
 				return
 			}
+			if time.Now().After(deadline) {
+				c.logger.Load().Warn("push notification drain timed out, dropping message", "uri", notification.URI)
+				continue
+			}
 			c.dispatchPushNotification(notification)
 		default:
 			return
@@ -246,15 +622,71 @@ func (c *Client) drainPushNotifications() {
 	}
 }
 
+// defaultHandlerShutdownTimeout bounds how long Close waits for event handlers.
+const defaultHandlerShutdownTimeout = 5 * time.Second
+
+// waitForHandlers waits for running handler calls to return, for at most
+// handlerShutdownTimeout. Callers must ensure no more handlers are dispatched.
+func (c *Client) waitForHandlers() {
+	done := make(chan struct{})
+	go func() {
+		c.handlerWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(c.handlerShutdownTimeout):
+		c.logger.Load().Warn("event handlers still running after close timeout", "timeout", c.handlerShutdownTimeout)
+	}
+}
+
 func (c *Client) dispatchPushNotification(notification PushNotification) {
 	c.eventHandlersMu.RLock()
 	handlers := make([]EventHandler, len(c.eventHandlers))
-	copy(handlers, c.eventHandlers)
+	for i, handler := range c.eventHandlers {
+		handlers[i] = *handler
+	}
 	c.eventHandlersMu.RUnlock()
 
 	// Each handler runs concurrently to avoid blocking on slow handlers
 	for _, handler := range handlers {
-		go handler(notification.URI, notification.Data)
+		if c.handlerSlots == nil {
+			c.handlerWg.Add(1)
+			go func() {
+				defer c.handlerWg.Done()
+				handler(notification.URI, notification.Data)
+			}()
+			continue
+		}
+
+		if !c.acquireHandlerSlot() {
+			c.logger.Load().Warn("client closed, dropping push notification", "uri", notification.URI)
+			return
+		}
+		c.handlerWg.Add(1)
+		go func() {
+			defer c.handlerWg.Done()
+			defer func() { <-c.handlerSlots }()
+			handler(notification.URI, notification.Data)
+		}()
+	}
+}
+
+// acquireHandlerSlot waits for a free slot of the bounded handler pool. It only gives
+// up once the client is closed and no slot frees up.
+func (c *Client) acquireHandlerSlot() bool {
+	select {
+	case c.handlerSlots <- struct{}{}:
+		return true
+	default:
+	}
+
+	select {
+	case c.handlerSlots <- struct{}{}:
+		return true
+	case <-c.ctx.Done():
+		return false
 	}
 }
 
@@ -276,47 +708,55 @@ func (c *Client) receiveMessage() error {
 	case xmpp.Chat:
 		return c.handleChatMessage(v)
 	case xmpp.Presence:
-		// Ignore presence for now
+		// The backend answers keepalive pings with a presence; it marks the link as alive.
+		c.lastPresence.Store(time.Now().UnixNano())
 		return nil
 	case xmpp.IQ:
 		// Ignore IQ for now
 		return nil
 	default:
-		c.logger.Debug("unknown stanza type", "type", fmt.Sprintf("%T", v))
+		c.logger.Load().Debug("unknown stanza type", "type", fmt.Sprintf("%T", v))
 		return nil
 	}
 }
 
 func (c *Client) handleChatMessage(msg xmpp.Chat) error {
-	c.logger.Debug("received chat message", "from", msg.Remote, "type", msg.Type)
+	c.tapStanza(StanzaReceived, msg.Text)
+
+	c.logger.Load().Debug("received chat message", "from", c.logJID(msg.Remote), "type", msg.Type)
 
 	if msg.Type == "error" {
-		c.logger.Error("received error message", "from", msg.Remote, "text", msg.Text)
-		c.notifyError(fmt.Errorf("XMPP error: %s", msg.Text))
+		xe := parseErrorStanza(msg)
+		c.logger.Load().Error("received error message", "from", c.logJID(msg.Remote), "condition", xe.Condition, "method", xe.Method, "uri", xe.URI, "text", xe.Text)
+		c.notifyRequestError(xe)
 		return nil
 	}
 
-	if msg.Text != "" {
-		resp, err := protocol.ParseHTTPResponse(msg.Text)
-		if err != nil {
-			c.logger.Error("failed to parse HTTP response", "error", err, "body", msg.Text)
-			return nil
-		}
+	// The backend occasionally sends bodies that are only whitespace; there is nothing to parse.
+	if strings.TrimSpace(msg.Text) == "" {
+		c.logger.Load().Debug("ignoring empty chat message", "from", c.logJID(msg.Remote))
+		return nil
+	}
 
-		c.logger.Debug("parsed HTTP response", "status", resp.StatusCode)
+	resp, err := protocol.ParseHTTPResponse(msg.Text)
+	if err != nil {
+		c.logger.Load().Error("failed to parse HTTP response", "error", err, "body", msg.Text)
+		return nil
+	}
 
-		// Check if this is a response to a pending request or an unsolicited push notification
-		c.pendingMu.RLock()
-		hasPendingRequests := len(c.pendingRequests) > 0
-		c.pendingMu.RUnlock()
+	c.logger.Load().Debug("parsed HTTP response", "status", resp.StatusCode)
 
-		if hasPendingRequests {
-			// This is likely a response to our request
-			c.notifyResponse(resp)
-		} else {
-			// This is an unsolicited push notification from the backend
-			c.handlePushNotification(resp)
-		}
+	// Check if this is a response to a pending request or an unsolicited push notification
+	c.pendingMu.RLock()
+	hasPendingRequests := len(c.pendingRequests) > 0
+	c.pendingMu.RUnlock()
+
+	if hasPendingRequests {
+		// This is likely a response to our request
+		c.notifyResponse(resp)
+	} else {
+		// This is an unsolicited push notification from the backend
+		c.handlePushNotification(resp)
 	}
 
 	return nil
@@ -325,29 +765,55 @@ func (c *Client) handleChatMessage(msg xmpp.Chat) error {
 // Subscribe registers an event handler that will be called when the backend
 // sends unsolicited push notifications. Multiple handlers can be registered.
 func (c *Client) Subscribe(handler EventHandler) {
+	c.subscribe(handler)
+}
+
+// subscribe registers handler and returns a function that removes it again.
+func (c *Client) subscribe(handler EventHandler) (unsubscribe func()) {
+	h := &handler
+
 	c.eventHandlersMu.Lock()
 	defer c.eventHandlersMu.Unlock()
-	c.eventHandlers = append(c.eventHandlers, handler)
+	c.eventHandlers = append(c.eventHandlers, h)
+
+	return func() {
+		c.eventHandlersMu.Lock()
+		defer c.eventHandlersMu.Unlock()
+		c.eventHandlers = slices.DeleteFunc(c.eventHandlers, func(other *EventHandler) bool {
+			return other == h
+		})
+	}
+}
+
+// SubscribeFiltered registers an event handler that is only called for push notifications
+// whose URI starts with prefix, e.g. types.URIStatus. Notifications without an "id" are
+// matched against the URI inferred from their payload (see types.InferURI), which is also
+// the URI passed to the handler.
+func (c *Client) SubscribeFiltered(prefix string, handler EventHandler) {
+	c.Subscribe(func(uri string, data interface{}) {
+		if uri == "" {
+			uri, _ = types.InferURI(data)
+		}
+		if uri == "" || !strings.HasPrefix(uri, prefix) {
+			return
+		}
+		handler(uri, data)
+	})
 }
 
 func (c *Client) handlePushNotification(resp *protocol.HTTPResponse) {
-	c.logger.Debug("received push notification", "status", resp.StatusCode)
+	c.logger.Load().Debug("received push notification", "status", resp.StatusCode)
 
 	if resp.Body != "" && resp.StatusCode == 200 {
-		decrypted, err := c.encryptor.Decrypt(resp.Body)
+		decrypted, err := c.encryptor.DecryptAndStrip(resp.Body)
 		if err != nil {
-			c.logger.Error("failed to decrypt push notification", "error", err)
+			c.logger.Load().Error("failed to decrypt push notification", "error", err)
 			return
 		}
 
-		var data interface{}
-		if resp.ContentType == "application/json" {
-			if err := json.Unmarshal([]byte(decrypted), &data); err != nil {
-				c.logger.Warn("failed to parse JSON push notification", "error", err, "data", decrypted)
-				data = decrypted
-			}
-		} else {
-			data = decrypted
+		data := decodeBody(resp.ContentType, decrypted, c.config.SniffJSON)
+		if strings.Contains(resp.ContentType, "json") && !json.Valid([]byte(decrypted)) {
+			c.logger.Load().Warn("failed to parse JSON push notification", "data", decrypted)
 		}
 
 		// Extract URI from the data if possible (the response might contain an 'id' field with the URI)
@@ -358,13 +824,14 @@ func (c *Client) handlePushNotification(resp *protocol.HTTPResponse) {
 			}
 		}
 
-		c.logger.Info("push notification received", "uri", uri, "data", data)
+		c.logger.Load().Info("push notification received", "uri", uri, "data", data)
+		c.observeUserMode(uri, data)
 
 		select {
 		case c.pushNotificationChan <- PushNotification{URI: uri, Data: data}:
 		default:
 			// Channel full - log warning but don't block
-			c.logger.Warn("push notification queue full, dropping message", "uri", uri)
+			c.logger.Load().Warn("push notification queue full, dropping message", "uri", uri)
 		}
 	}
 }
@@ -393,6 +860,44 @@ func (c *Client) notifyError(err error) {
 	}
 }
 
+// notifyRequestError fails the pending request the error stanza refers to. Stanzas that
+// echo no request, or one that matches no pending key, fail all pending requests as before.
+func (c *Client) notifyRequestError(xe *XMPPError) {
+	if xe.Method != "" {
+		c.pendingMu.RLock()
+		var matched []chan error
+		for reqID, ch := range c.pendingErrors {
+			if pendingMatches(reqID, xe.Method, xe.URI) {
+				matched = append(matched, ch)
+			}
+		}
+		c.pendingMu.RUnlock()
+
+		if len(matched) > 0 {
+			for _, ch := range matched {
+				select {
+				case ch <- xe:
+				default:
+				}
+			}
+			return
+		}
+	}
+
+	c.notifyError(xe)
+}
+
+// pendingMatches reports whether the pending request key reqID ("get:<uri>:<nanos>",
+// see executeGet and executePut) is for method and the encoded uri echoed by the backend.
+func pendingMatches(reqID, method, uri string) bool {
+	prefix := strings.ToLower(method) + ":"
+	end := strings.LastIndex(reqID, ":")
+	if !strings.HasPrefix(reqID, prefix) || end < len(prefix) {
+		return false
+	}
+	return protocol.EncodeURI(reqID[len(prefix):end]) == uri
+}
+
 func (c *Client) sendMessage(msg string) error {
 	c.connMu.RLock()
 	client := c.xmppClient
@@ -410,6 +915,8 @@ func (c *Client) sendMessage(msg string) error {
 		return fmt.Errorf("failed to parse message: %w", err)
 	}
 
+	c.tapStanza(StanzaSent, msgStanza.Body)
+
 	_, err := client.Send(xmpp.Chat{
 		Remote: msgStanza.To,
 		Type:   "chat",
@@ -419,26 +926,136 @@ func (c *Client) sendMessage(msg string) error {
 }
 
 // Get performs a GET request to the specified URI and returns the decrypted response data.
-// The method automatically retries on timeout and deserializes JSON responses.
+// The method automatically retries on timeout and decodes the body by content type:
+// JSON is deserialized, text/plain yields a bool, float64 or string, and other content
+// types are returned as a *types.RawResponse. Config.SniffJSON decodes untyped JSON bodies.
+// A single 301/302/307 redirect to the URI in the Location header is followed.
 func (c *Client) Get(ctx context.Context, uri string) (interface{}, error) {
+	value, _, err := c.GetWithContentType(ctx, uri)
+	return value, err
+}
+
+// GetWithContentType is like Get but also returns the Content-Type of the response.
+func (c *Client) GetWithContentType(ctx context.Context, uri string) (interface{}, string, error) {
+	result, err := c.getRaw(ctx, uri)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return decodeBody(result.resp.ContentType, result.body, c.config.SniffJSON), result.resp.ContentType, nil
+}
+
+// GetInto performs a GET request and decodes the JSON response into target.
+// Numbers are decoded with json.Decoder.UseNumber, so decoding into interface{}
+// or json.Number fields preserves integers that do not fit a float64.
+func (c *Client) GetInto(ctx context.Context, uri string, target interface{}) error {
+	result, err := c.getRaw(ctx, uri)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(result.body))
+	dec.UseNumber()
+	if err := dec.Decode(target); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", uri, err)
+	}
+
+	return nil
+}
+
+// GetBytes performs a GET like Get but returns the decrypted body as bytes, without
+// decoding it. The response is returned for its headers.
+//
+// The device pads the plaintext with fewer than 16 null bytes to the AES block size and
+// does not send its length (Content-Length counts the base64 ciphertext), so at most 15
+// trailing null bytes are removed as padding and null bytes before them are preserved.
+// Trailing null bytes of the payload itself within that range are indistinguishable from
+// the padding and are removed too.
+func (c *Client) GetBytes(ctx context.Context, uri string) ([]byte, *protocol.HTTPResponse, error) {
+	result, err := c.getRaw(ctx, uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plaintext, err := c.encryptor.DecryptBytes(result.resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decryption failed: %w", err)
+	}
+
+	return stripPadding(plaintext), result.resp, nil
+}
+
+// stripPadding removes the null padding from decrypted data. The padding never fills a
+// whole block, so only the last aes.BlockSize-1 bytes can be padding.
+func stripPadding(plaintext []byte) []byte {
+	end := len(plaintext)
+	last := max(end-aes.BlockSize+1, 0)
+	for end > last && plaintext[end-1] == 0 {
+		end--
+	}
+	return plaintext[:end]
+}
+
+// getResult is a successful GET response with its decrypted body.
+type getResult struct {
+	resp *protocol.HTTPResponse
+	body string
+}
+
+// getRaw performs a GET with retries and redirect handling, returning the decrypted body undecoded.
+func (c *Client) getRaw(ctx context.Context, uri string) (*getResult, error) {
 	if !c.IsConnected() {
 		return nil, fmt.Errorf("not connected")
 	}
 
+	ctx = ensureRequestID(ctx)
+
+	result, err := c.getWithRetry(ctx, uri)
+
+	var redirect *redirectError
+	if errors.As(err, &redirect) {
+		if redirect.location == uri {
+			return nil, fmt.Errorf("GET %s: redirect loop to itself", uri)
+		}
+
+		c.log(ctx).Debug("following redirect", "uri", uri, "location", redirect.location, "status_code", redirect.statusCode)
+
+		result, err = c.getWithRetry(ctx, redirect.location)
+		if errors.As(err, &redirect) {
+			return nil, fmt.Errorf("GET %s: too many redirects (last to %s)", uri, redirect.location)
+		}
+	}
+
+	return result, err
+}
+
+func (c *Client) getWithRetry(ctx context.Context, uri string) (result *getResult, err error) {
+	timer := newRequestTimer("GET", uri)
+	defer func() { c.finishTimer(ctx, timer, err) }()
+
 	var lastErr error
+	budget := retryBudget(ctx)
+	attempts := 0
 	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
 		if attempt > 0 {
-			c.logger.Debug("retrying GET request", "uri", uri, "attempt", attempt)
+			c.log(ctx).Debug("retrying GET request", "uri", uri, "attempt", attempt)
+		}
+
+		if err := budget.spend(); err != nil {
+			lastErr = budgetError(err, lastErr)
+			break
 		}
+		attempts++
 
-		reqCtx, cancel := context.WithTimeout(ctx, c.config.RetryTimeout)
-		result, err := c.queue.Submit(reqCtx, func() (interface{}, error) {
+		reqCtx, cancel := context.WithTimeout(ctx, budget.attemptTimeout(c.config.RetryTimeout))
+		value, timing, err := c.queue.SubmitTimed(reqCtx, func() (interface{}, error) {
 			return c.executeGet(reqCtx, uri)
 		})
 		cancel()
+		timer.add(timing)
 
 		if err == nil {
-			return result, nil
+			return value.(*getResult), nil
 		}
 
 		lastErr = err
@@ -447,18 +1064,18 @@ func (c *Client) Get(ctx context.Context, uri string) (interface{}, error) {
 			break
 		}
 
-		if err != context.DeadlineExceeded {
+		if !c.shouldRetry("GET", err, attempts) {
 			break
 		}
 	}
 
-	return nil, fmt.Errorf("GET request failed after %d attempts: %w", c.config.MaxRetries, lastErr)
+	return nil, fmt.Errorf("GET request failed after %d attempts: %w", attempts, lastErr)
 }
 
-func (c *Client) executeGet(ctx context.Context, uri string) (interface{}, error) {
-	msg := protocol.BuildGetMessage(c.config.JID(), c.config.ResourceJID(), uri)
+func (c *Client) executeGet(ctx context.Context, uri string) (*getResult, error) {
+	msg := c.builder.BuildGetMessage(c.config.JID(), c.config.ResourceJID(), uri)
 
-	c.logger.Debug("sending GET request", "uri", uri)
+	c.log(ctx).Debug("sending GET request", "uri", uri)
 
 	responseCh := make(chan *protocol.HTTPResponse, 1)
 	errorCh := make(chan error, 1)
@@ -482,8 +1099,14 @@ func (c *Client) executeGet(ctx context.Context, uri string) (interface{}, error
 
 	select {
 	case resp := <-responseCh:
+		c.capture("GET", uri, "", "", resp)
+
+		if location := redirectLocation(resp); location != "" {
+			return nil, &redirectError{statusCode: resp.StatusCode, location: location}
+		}
+
 		if resp.StatusCode != 200 {
-			return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
+			return nil, newAPIError(c.encryptor, resp)
 		}
 
 		decrypted, err := c.encryptor.DecryptAndStrip(resp.Body)
@@ -491,31 +1114,29 @@ func (c *Client) executeGet(ctx context.Context, uri string) (interface{}, error
 			return nil, fmt.Errorf("decryption failed: %w", err)
 		}
 
-		if strings.Contains(resp.ContentType, "json") {
-			var result interface{}
-			if err := json.Unmarshal([]byte(decrypted), &result); err != nil {
-				return decrypted, nil
-			}
-			return result, nil
-		}
-
-		return decrypted, nil
+		return &getResult{resp: resp, body: decrypted}, nil
 
 	case err := <-errorCh:
 		return nil, err
 	case <-ctx.Done():
 		return nil, ctx.Err()
+	case <-c.ctx.Done():
+		return nil, ErrClientClosed
 	}
 }
 
 // Put performs a PUT request to the specified URI with the given data.
 // Data is automatically marshalled to JSON and encrypted before sending.
 // The method uses exponential backoff for retries on transient errors.
-func (c *Client) Put(ctx context.Context, uri string, data interface{}) error {
+// With Config.DryRun set, the request is only logged.
+func (c *Client) Put(ctx context.Context, uri string, data interface{}) (err error) {
 	if !c.IsConnected() {
 		return fmt.Errorf("not connected")
 	}
 
+	ctx = ensureRequestID(ctx)
+	logger := c.log(ctx)
+
 	var jsonData string
 	switch v := data.(type) {
 	case string:
@@ -528,25 +1149,43 @@ func (c *Client) Put(ctx context.Context, uri string, data interface{}) error {
 		jsonData = string(jsonBytes)
 	}
 
-	c.logger.Debug("PUT request data prepared",
+	logger.Debug("PUT request data prepared",
 		"uri", uri,
 		"json_data", jsonData,
 		"json_length", len(jsonData))
 
+	if c.config.DryRun {
+		logger.Info("dry run: PUT request not sent",
+			"uri", uri,
+			"json_data", jsonData)
+		return nil
+	}
+
 	encrypted, err := c.encryptor.Encrypt(jsonData)
 	if err != nil {
 		return fmt.Errorf("failed to encrypt data: %w", err)
 	}
+	if len(encrypted) > MaxPayloadSize {
+		return fmt.Errorf("PUT %s: %w: %d bytes encrypted, limit %d", uri, ErrPayloadTooLarge, len(encrypted), MaxPayloadSize)
+	}
 
-	c.logger.Debug("PUT request encrypted",
+	// The write may change what QuickStatus reports, whether or not it succeeds.
+	c.forgetQuickStatus()
+
+	logger.Debug("PUT request encrypted",
 		"uri", uri,
 		"encrypted_length", len(encrypted))
 
+	timer := newRequestTimer("PUT", uri)
+	defer func() { c.finishTimer(ctx, timer, err) }()
+
 	var lastErr error
+	budget := retryBudget(ctx)
+	attempts := 0
 	backoff := c.config.RetryTimeout
 	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
 		if attempt > 0 {
-			c.logger.Debug("retrying PUT request",
+			logger.Debug("retrying PUT request",
 				"uri", uri,
 				"attempt", attempt,
 				"backoff", backoff,
@@ -566,15 +1205,22 @@ func (c *Client) Put(ctx context.Context, uri string, data interface{}) error {
 			}
 		}
 
-		reqCtx, cancel := context.WithTimeout(ctx, c.config.RetryTimeout)
-		_, err := c.queue.Submit(reqCtx, func() (interface{}, error) {
+		if err := budget.spend(); err != nil {
+			lastErr = budgetError(err, lastErr)
+			break
+		}
+		attempts++
+
+		reqCtx, cancel := context.WithTimeout(ctx, budget.attemptTimeout(c.config.RetryTimeout))
+		_, timing, err := c.queue.SubmitTimed(reqCtx, func() (interface{}, error) {
 			return nil, c.executePut(reqCtx, uri, encrypted, jsonData)
 		})
 		cancel()
+		timer.add(timing)
 
 		if err == nil {
 			if attempt > 0 {
-				c.logger.Info("PUT request succeeded after retry",
+				logger.Info("PUT request succeeded after retry",
 					"uri", uri,
 					"attempts", attempt+1)
 			}
@@ -587,9 +1233,8 @@ func (c *Client) Put(ctx context.Context, uri string, data interface{}) error {
 			break
 		}
 
-		// Only retry on timeout errors - 400 Bad Request indicates invalid data
-		if err != context.DeadlineExceeded && !strings.Contains(err.Error(), "timeout") {
-			c.logger.Warn("PUT request failed with non-retryable error",
+		if !c.shouldRetry("PUT", err, attempts) {
+			logger.Warn("PUT request failed with non-retryable error",
 				"uri", uri,
 				"error", err,
 				"json_data", jsonData)
@@ -597,16 +1242,17 @@ func (c *Client) Put(ctx context.Context, uri string, data interface{}) error {
 		}
 	}
 
-	return fmt.Errorf("PUT request failed after %d attempts: %w", c.config.MaxRetries+1, lastErr)
+	return fmt.Errorf("PUT request failed after %d attempts: %w", attempts, lastErr)
 }
 
 func (c *Client) executePut(ctx context.Context, uri, encryptedData, jsonData string) error {
-	msg := protocol.BuildPutMessage(c.config.JID(), c.config.ResourceJID(), uri, encryptedData)
+	msg := c.builder.BuildPutMessage(c.config.JID(), c.config.ResourceJID(), uri, encryptedData)
+	logger := c.log(ctx)
 
-	c.logger.Debug("sending PUT request",
+	logger.Debug("sending PUT request",
 		"uri", uri,
-		"from", c.config.JID(),
-		"to", c.config.ResourceJID(),
+		"from", c.logJID(c.config.JID()),
+		"to", c.logJID(c.config.ResourceJID()),
 		"encrypted_payload_length", len(encryptedData),
 		"decrypted_json", jsonData)
 
@@ -632,15 +1278,19 @@ func (c *Client) executePut(ctx context.Context, uri, encryptedData, jsonData st
 
 	select {
 	case resp := <-responseCh:
+		c.capture("PUT", uri, encryptedData, jsonData, resp)
+
 		if resp.StatusCode >= 300 {
-			c.logger.Error("PUT request failed",
+			apiErr := newAPIError(c.encryptor, resp)
+			logger.Error("PUT request failed",
 				"uri", uri,
 				"status_code", resp.StatusCode,
 				"status", resp.Status,
+				"message", apiErr.Message,
 				"json_data", jsonData)
-			return fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
+			return apiErr
 		}
-		c.logger.Debug("PUT request successful",
+		logger.Debug("PUT request successful",
 			"uri", uri,
 			"status_code", resp.StatusCode)
 		return nil
@@ -648,5 +1298,7 @@ func (c *Client) executePut(ctx context.Context, uri, encryptedData, jsonData st
 		return err
 	case <-ctx.Done():
 		return ctx.Err()
+	case <-c.ctx.Done():
+		return ErrClientClosed
 	}
 }
@@ -0,0 +1,806 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+	xmpp "github.com/xmppo/go-xmpp"
+	"go.uber.org/goleak"
+)
+
+func newUnconnectedClient(t *testing.T) *Client {
+	t.Helper()
+
+	c, err := NewClient(Config{
+		SerialNumber: "123456789",
+		AccessKey:    "abcdefghijklmnop",
+		Password:     "secret",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	c.SetLogger(slog.New(slog.DiscardHandler))
+	return c
+}
+
+func TestCloseIdempotent(t *testing.T) {
+	c := newUnconnectedClient(t)
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("First Close returned error: %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Second Close panicked: %v", r)
+		}
+	}()
+
+	if err := c.Close(); err != nil {
+		t.Errorf("Second Close returned error: %v", err)
+	}
+}
+
+func TestErrorsReportsReceiveFailure(t *testing.T) {
+	ft := newFakeTransport()
+	c := newTestClient(t, ft)
+
+	ft.recvCh <- errors.New("connection reset")
+
+	select {
+	case err := <-c.Errors():
+		if !strings.Contains(err.Error(), "connection reset") {
+			t.Errorf("Expected receive error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for error on Errors channel")
+	}
+}
+
+func TestGetFollowsRedirect(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	d.queue("/old/location", fakeResponse{
+		StatusCode: 302,
+		Status:     "Found",
+		Headers:    map[string]string{"Location": "/new/location"},
+	})
+	d.setValue("/new/location", 42.0)
+
+	data, err := c.Get(t.Context(), "/old/location")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok || dataMap["value"] != 42.0 {
+		t.Errorf("Expected redirected payload, got %v", data)
+	}
+
+	reqs := d.requestsFor("GET")
+	if len(reqs) != 2 || reqs[1].URI != "/new/location" {
+		t.Errorf("Expected GET to original and redirected URI, got %+v", reqs)
+	}
+}
+
+func TestGetRedirectLimit(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	d.queue("/a", fakeResponse{StatusCode: 301, Status: "Moved", Headers: map[string]string{"Location": "/b"}})
+	d.queue("/b", fakeResponse{StatusCode: 301, Status: "Moved", Headers: map[string]string{"Location": "/c"}})
+
+	if _, err := c.Get(t.Context(), "/a"); err == nil || !strings.Contains(err.Error(), "too many redirects") {
+		t.Errorf("Expected too many redirects error, got %v", err)
+	}
+
+	d.queue("/self", fakeResponse{StatusCode: 307, Status: "Temporary Redirect", Headers: map[string]string{"Location": "/self"}})
+	if _, err := c.Get(t.Context(), "/self"); err == nil || !strings.Contains(err.Error(), "loop") {
+		t.Errorf("Expected redirect loop error, got %v", err)
+	}
+}
+
+func TestSetLoggerConcurrentWithWorkers(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			c.SetLogger(slog.New(slog.DiscardHandler))
+		}
+	}()
+
+	// Malformed chats make the receive worker log while the logger is swapped.
+	for i := 0; i < 50; i++ {
+		d.ft.recvCh <- xmpp.Chat{Type: "chat", Text: "garbage"}
+	}
+	d.setValue("/test", 1.0)
+	if _, err := c.Get(t.Context(), "/test"); err != nil {
+		t.Errorf("Get failed: %v", err)
+	}
+
+	<-done
+}
+
+func TestPutPayloadTooLarge(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	err := c.Put(t.Context(), "/ecus/rrc/userprogram/program1", map[string]string{"value": strings.Repeat("x", MaxPayloadSize)})
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("limit %d", MaxPayloadSize)) {
+		t.Errorf("Expected the limit in the error, got %v", err)
+	}
+	if sent := d.ft.sentChats(); len(sent) != 0 {
+		t.Errorf("Expected nothing sent for an oversized payload, got %d messages", len(sent))
+	}
+}
+
+func TestPutDryRun(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.DryRun = true
+	logs := captureLogs(c)
+
+	if err := c.Put(t.Context(), "/heatingCircuits/hc1/usermode", map[string]string{"value": "manual"}); err != nil {
+		t.Fatalf("Dry-run Put failed: %v", err)
+	}
+
+	if sent := d.ft.sentChats(); len(sent) != 0 {
+		t.Errorf("Expected no message sent in dry-run, got %d", len(sent))
+	}
+
+	found := false
+	for _, entry := range logs.entries(t) {
+		if entry["msg"] == "dry run: PUT request not sent" {
+			found = entry["uri"] == "/heatingCircuits/hc1/usermode" && entry["json_data"] == `{"value":"manual"}`
+		}
+	}
+	if !found {
+		t.Error("Expected the intended payload to be logged")
+	}
+
+	d.setValue("/test", 1.0)
+	if _, err := c.Get(t.Context(), "/test"); err != nil {
+		t.Errorf("Expected GET to go through in dry-run, got %v", err)
+	}
+}
+
+func TestGetIntoPreservesLargeIntegers(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set("/ecus/rrc/recordings/test", `{"id":"/ecus/rrc/recordings/test","value":9007199254740993}`)
+
+	var generic map[string]interface{}
+	if err := c.GetInto(t.Context(), "/ecus/rrc/recordings/test", &generic); err != nil {
+		t.Fatalf("GetInto failed: %v", err)
+	}
+	if n, ok := generic["value"].(json.Number); !ok || n.String() != "9007199254740993" {
+		t.Errorf("Expected exact json.Number, got %#v", generic["value"])
+	}
+
+	var typed struct {
+		Value int64 `json:"value"`
+	}
+	if err := c.GetInto(t.Context(), "/ecus/rrc/recordings/test", &typed); err != nil {
+		t.Fatalf("GetInto failed: %v", err)
+	}
+	if typed.Value != 9007199254740993 {
+		t.Errorf("Expected 9007199254740993, got %d", typed.Value)
+	}
+}
+
+func TestStanzaLogger(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue("/test", 1.0)
+
+	var mu sync.Mutex
+	var got []string
+	c.SetStanzaLogger(func(direction, raw string) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, direction+" "+raw)
+	})
+
+	if _, err := c.Get(t.Context(), "/test"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 tapped stanzas, got %d: %q", len(got), got)
+	}
+	if !strings.HasPrefix(got[0], StanzaSent+" GET /test HTTP/1.1") {
+		t.Errorf("Expected sent GET, got %q", got[0])
+	}
+	if !strings.HasPrefix(got[1], StanzaReceived+" HTTP/1.0 200 OK") {
+		t.Errorf("Expected received response, got %q", got[1])
+	}
+}
+
+func TestEvents(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	events := c.Events(ctx)
+
+	for i := range 3 {
+		d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: fmt.Sprintf(`{"id":"/pushed/%d","value":%d}`, i, i)})
+	}
+
+	got := map[string]bool{}
+	for range 3 {
+		select {
+		case event := <-events:
+			got[event.URI] = true
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for events, got %v", got)
+		}
+	}
+	for i := range 3 {
+		if uri := fmt.Sprintf("/pushed/%d", i); !got[uri] {
+			t.Errorf("Missing event for %s in %v", uri, got)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Expected no events after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected cancellation to close the events channel")
+	}
+
+	c.eventHandlersMu.RLock()
+	handlers := len(c.eventHandlers)
+	c.eventHandlersMu.RUnlock()
+	if handlers != 0 {
+		t.Errorf("Expected the events handler to be removed, %d handlers left", handlers)
+	}
+}
+
+func TestSubscribeFiltered(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	all := make(chan string, 3)
+	c.Subscribe(func(uri string, data interface{}) {
+		all <- uri
+	})
+	filtered := make(chan string, 3)
+	c.SubscribeFiltered(types.URIStatus, func(uri string, data interface{}) {
+		filtered <- uri
+	})
+
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"id":"/system/appliance/systemPressure","value":1.6}`})
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"value":{"IHT":"20.5","UMD":"clock"}}`})
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"id":"/ecus/rrc/uiStatus","value":{"IHT":"20.0"}}`})
+
+	for range 3 {
+		select {
+		case <-all:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for push notifications")
+		}
+	}
+
+	var got []string
+	for range 2 {
+		select {
+		case uri := <-filtered:
+			got = append(got, uri)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected both status pushes, got %v", got)
+		}
+	}
+	for _, uri := range got {
+		if uri != types.URIStatus {
+			t.Errorf("Filtered handler called with %q", uri)
+		}
+	}
+
+	select {
+	case uri := <-filtered:
+		t.Errorf("Filtered handler called for non-matching push %q", uri)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue("/test/value", "before")
+
+	pushes := make(chan string, 1)
+	c.Subscribe(func(uri string, data interface{}) {
+		pushes <- uri
+	})
+
+	old := d.ft
+	dials := 0
+	c.dial = func(xmpp.Options) (transport, error) {
+		dials++
+		ft := newFakeTransport()
+		ft.onSend = d.handle
+		d.ft = ft
+		return ft, nil
+	}
+
+	if err := c.Reconnect(t.Context()); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	if dials != 1 {
+		t.Fatalf("Expected one dial, got %d", dials)
+	}
+
+	select {
+	case <-old.closed:
+	default:
+		t.Error("Expected old transport to be closed")
+	}
+
+	data, err := c.Get(t.Context(), "/test/value")
+	if err != nil {
+		t.Fatalf("Get after reconnect failed: %v", err)
+	}
+	if v := data.(map[string]interface{})["value"]; v != "before" {
+		t.Errorf("Expected value from new connection, got %v", v)
+	}
+
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"id":"/pushed","value":1}`})
+
+	select {
+	case uri := <-pushes:
+		if uri != "/pushed" {
+			t.Errorf("Expected push for /pushed, got %q", uri)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Handler registered before reconnect was not called")
+	}
+}
+
+func TestReconnectFailsInFlightRequest(t *testing.T) {
+	ft := newFakeTransport()
+	c := newTestClient(t, ft)
+	c.dial = func(xmpp.Options) (transport, error) {
+		return newFakeTransport(), nil
+	}
+
+	// Nothing answers the GET, so it stays pending until the reconnect fails it.
+	ft.onSend = func(xmpp.Chat) {
+		go func() { _ = c.Reconnect(t.Context()) }()
+	}
+
+	_, err := c.Get(t.Context(), "/test/value")
+	if !errors.Is(err, ErrReconnecting) {
+		t.Errorf("Expected ErrReconnecting, got %v", err)
+	}
+}
+
+func TestReconnectAfterClose(t *testing.T) {
+	c := newUnconnectedClient(t)
+	_ = c.Close()
+
+	if err := c.Reconnect(t.Context()); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed, got %v", err)
+	}
+}
+
+func TestHandleChatMessageWhitespaceBody(t *testing.T) {
+	c := newUnconnectedClient(t)
+	logs := captureLogs(c)
+
+	for _, text := range []string{" ", "\n", "\r\n \t"} {
+		if err := c.handleChatMessage(xmpp.Chat{Type: "chat", Text: text}); err != nil {
+			t.Errorf("handleChatMessage(%q) returned error: %v", text, err)
+		}
+	}
+
+	ignored := 0
+	for _, entry := range logs.entries(t) {
+		if entry["level"] == "ERROR" {
+			t.Errorf("Unexpected error log for whitespace body: %v", entry)
+		}
+		if entry["msg"] == "ignoring empty chat message" {
+			ignored++
+		}
+	}
+	if ignored != 3 {
+		t.Errorf("Expected 3 debug logs for ignored messages, got %d", ignored)
+	}
+
+	if err := c.handleChatMessage(xmpp.Chat{Type: "chat", Text: "garbage"}); err != nil {
+		t.Errorf("handleChatMessage returned error: %v", err)
+	}
+	var errorLogs int
+	for _, entry := range logs.entries(t) {
+		if entry["level"] == "ERROR" {
+			errorLogs++
+		}
+	}
+	if errorLogs != 1 {
+		t.Errorf("Expected one error log for malformed body, got %d", errorLogs)
+	}
+}
+
+func TestPingTimeoutReportsDeadConnection(t *testing.T) {
+	ft := newFakeTransport()
+	c := newUnconnectedClient(t)
+	c.config.PingInterval = 10 * time.Millisecond
+	c.config.PingTimeout = 50 * time.Millisecond
+	c.dial = func(xmpp.Options) (transport, error) {
+		return ft, nil
+	}
+	if err := c.Connect(t.Context()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	if !c.LastPresence().IsZero() {
+		t.Error("Expected no presence before the backend sent one")
+	}
+
+	// Pings go unanswered, so the connection must be reported dead.
+	select {
+	case err := <-c.Errors():
+		if !errors.Is(err, ErrConnectionDead) {
+			t.Fatalf("Expected ErrConnectionDead, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Dead connection was not reported")
+	}
+
+	// Answered pings keep the connection alive.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ft.recvCh <- xmpp.Presence{}
+			}
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for c.LastPresence().IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatal("Presence was not recorded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Drain anything reported before the first presence arrived.
+	time.Sleep(20 * time.Millisecond)
+	for len(c.Errors()) > 0 {
+		<-c.Errors()
+	}
+
+	select {
+	case err := <-c.Errors():
+		t.Errorf("Unexpected error while presence is answered: %v", err)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestHandlerConcurrencyBounded(t *testing.T) {
+	const limit = 2
+
+	d := newFakeBackend(t, "123456789")
+	c, err := NewClient(Config{
+		SerialNumber:       "123456789",
+		AccessKey:          "abcdefghijklmnop",
+		Password:           "secret",
+		HandlerConcurrency: limit,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	c.SetLogger(slog.New(slog.DiscardHandler))
+	c.dial = func(xmpp.Options) (transport, error) { return d.ft, nil }
+	if err := c.Connect(t.Context()); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	var (
+		active, peak, calls atomic.Int32
+		started             = make(chan struct{}, 10)
+		release             = make(chan struct{})
+	)
+	c.Subscribe(func(uri string, data interface{}) {
+		n := active.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		started <- struct{}{}
+		<-release
+		active.Add(-1)
+		calls.Add(1)
+	})
+
+	before := runtime.NumGoroutine()
+	const pushes = 8
+	for i := range pushes {
+		d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: fmt.Sprintf(`{"id":"/push/%d","value":1}`, i)})
+	}
+
+	for range limit {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for handlers to start")
+		}
+	}
+	select {
+	case <-started:
+		t.Fatal("More handlers started than the concurrency limit")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if grown := runtime.NumGoroutine() - before; grown > limit {
+		t.Errorf("Expected at most %d extra goroutines while handlers block, got %d", limit, grown)
+	}
+
+	close(release)
+	deadline := time.After(2 * time.Second)
+	for calls.Load() < pushes {
+		select {
+		case <-deadline:
+			t.Fatalf("Expected %d handler calls, got %d", pushes, calls.Load())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if p := peak.Load(); p > limit {
+		t.Errorf("Peak concurrency %d exceeds limit %d", p, limit)
+	}
+}
+
+func TestConnectHonorsContext(t *testing.T) {
+	c := newUnconnectedClient(t)
+	t.Cleanup(func() { _ = c.Close() })
+
+	ft := newFakeTransport()
+	release := make(chan struct{})
+	c.dial = func(xmpp.Options) (transport, error) {
+		<-release // a stalled TLS handshake
+		return ft, nil
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Connect(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Connect returned after %v, expected promptly after the deadline", elapsed)
+	}
+	if c.IsConnected() {
+		t.Error("Client must not be connected after an abandoned dial")
+	}
+
+	close(release)
+	select {
+	case <-ft.closed:
+	case <-time.After(time.Second):
+		t.Error("Expected the late connection to be closed")
+	}
+}
+
+func TestShutdownHooks(t *testing.T) {
+	c, _ := newFakeDevice(t)
+
+	var order []string
+	errFlush := errors.New("flush failed")
+	c.RegisterShutdownHook(func() error {
+		order = append(order, "first")
+		return nil
+	})
+	c.RegisterShutdownHook(func() error {
+		order = append(order, "second")
+		return errFlush
+	})
+
+	if err := c.Close(); !errors.Is(err, errFlush) {
+		t.Errorf("Expected Close to return the hook error, got %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Expected a second Close to return nil, got %v", err)
+	}
+
+	if strings.Join(order, ",") != "second,first" {
+		t.Errorf("Expected each hook to run once in reverse order, got %v", order)
+	}
+}
+
+func TestReconnectResubscribes(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	var transports []*fakeTransport
+	c.dial = func(xmpp.Options) (transport, error) {
+		ft := newFakeTransport()
+		ft.onSend = d.handle
+		d.ft = ft
+		transports = append(transports, ft)
+		return ft, nil
+	}
+
+	// Without handlers there is nothing to resume.
+	if err := c.Reconnect(t.Context()); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	transports[0].mu.Lock()
+	presences := transports[0].presences
+	transports[0].mu.Unlock()
+	if presences != 0 {
+		t.Errorf("Expected no presence without handlers, got %d", presences)
+	}
+
+	c.Subscribe(func(uri string, data interface{}) {})
+	if err := c.Reconnect(t.Context()); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	transports[1].mu.Lock()
+	presences = transports[1].presences
+	transports[1].mu.Unlock()
+	if presences != 1 {
+		t.Errorf("Expected one presence on the new connection, got %d", presences)
+	}
+}
+
+func TestCloseWaitsForHandlers(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	var started, finished atomic.Int32
+	c.Subscribe(func(uri string, data interface{}) {
+		started.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		finished.Add(1)
+	})
+
+	for i := range 5 {
+		d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: fmt.Sprintf(`{"id":"/pushed/%d","value":1}`, i)})
+	}
+	// Wait for the first push to reach the handler so Close races the rest.
+	deadline := time.Now().Add(time.Second)
+	for started.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if s, f := started.Load(), finished.Load(); s == 0 || s != f {
+		t.Errorf("Expected every started handler to finish before Close returned, started %d finished %d", s, f)
+	}
+}
+
+func TestCloseHandlerTimeout(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.handlerShutdownTimeout = 50 * time.Millisecond
+
+	release := make(chan struct{})
+	defer close(release)
+	entered := make(chan struct{}, 1)
+	c.Subscribe(func(uri string, data interface{}) {
+		entered <- struct{}{}
+		<-release
+	})
+
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"id":"/pushed","value":1}`})
+	select {
+	case <-entered:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the handler")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		_ = c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on a stuck handler")
+	}
+}
+
+func TestConcurrentConnect(t *testing.T) {
+	ignore := goleak.IgnoreCurrent()
+	c := newUnconnectedClient(t)
+
+	var dials atomic.Int32
+	var transports []*fakeTransport
+	var mu sync.Mutex
+	c.dial = func(xmpp.Options) (transport, error) {
+		dials.Add(1)
+		ft := newFakeTransport()
+		mu.Lock()
+		transports = append(transports, ft)
+		mu.Unlock()
+		return ft, nil
+	}
+
+	const callers = 8
+	errs := make(chan error, callers)
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Connect(t.Context())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	connected := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			connected++
+		case !errors.Is(err, ErrAlreadyConnected):
+			t.Errorf("Unexpected Connect error: %v", err)
+		}
+	}
+	if connected != 1 {
+		t.Errorf("Expected exactly one Connect to connect, got %d", connected)
+	}
+	if n := dials.Load(); n != 1 {
+		t.Errorf("Expected one dial, got %d", n)
+	}
+
+	c.connMu.RLock()
+	current := c.xmppClient
+	c.connMu.RUnlock()
+	if current != transports[0] {
+		t.Error("Expected the client to keep the first connection")
+	}
+
+	// Workers started by a losing Connect would outlive Close.
+	_ = c.Close()
+	goleak.VerifyNone(t, ignore)
+}
+
+func TestConnectBindsConfiguredResource(t *testing.T) {
+	for _, resource := range []string{"", "nefit-go"} {
+		c := newUnconnectedClient(t)
+		t.Cleanup(func() { _ = c.Close() })
+		c.config.Resource = resource
+
+		var got xmpp.Options
+		c.dial = func(options xmpp.Options) (transport, error) {
+			got = options
+			return newFakeTransport(), nil
+		}
+
+		if err := c.Connect(t.Context()); err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		if got.Resource != resource {
+			t.Errorf("Dialled with resource %q, want %q", got.Resource, resource)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// deviceTimeLayout is the format of the device clock. It carries no zone; the
+// thermostat runs on local wall-clock time.
+const deviceTimeLayout = "2006-01-02T15:04:05"
+
+// DeviceTime retrieves the current time of the thermostat clock, interpreted in the host's local time zone.
+func (c *Client) DeviceTime(ctx context.Context) (time.Time, error) {
+	data, err := c.Get(ctx, types.URIDateTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get device time: %w", err)
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected device time response type: %T", data)
+	}
+
+	value, _ := types.LookupString(dataMap, "value")
+	return parseDeviceTime(value, time.Local)
+}
+
+func parseDeviceTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(deviceTimeLayout, value, loc); err == nil {
+		return t, nil
+	}
+	// Some firmware includes the UTC offset.
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid device time %q", value)
+	}
+	return t, nil
+}
+
+// ClockDrift returns how far the thermostat clock is ahead of the host clock
+// (negative if it is behind). Half the request round trip is taken as the
+// moment the device read its clock. The device reports whole seconds, so drift
+// below a second is not meaningful.
+func (c *Client) ClockDrift(ctx context.Context) (time.Duration, error) {
+	sent := c.now()
+	device, err := c.DeviceTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+	received := c.now()
+
+	host := sent.Add(received.Sub(sent) / 2)
+	return device.Sub(host), nil
+}
+
+// SetDeviceTime sets the thermostat clock to t, converted to the host's local time zone.
+func (c *Client) SetDeviceTime(ctx context.Context, t time.Time) error {
+	data := map[string]interface{}{
+		"value": t.In(time.Local).Format(deviceTimeLayout),
+	}
+	if err := c.Put(ctx, types.URIDateTime, data); err != nil {
+		return fmt.Errorf("failed to set device time: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestClockDrift(t *testing.T) {
+	host := time.Date(2024, time.January, 15, 7, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		device    time.Time
+		roundTrip time.Duration
+		want      time.Duration
+	}{
+		{name: "ahead", device: host.Add(90 * time.Second), want: 90 * time.Second},
+		{name: "behind", device: host.Add(-5 * time.Minute), want: -5 * time.Minute},
+		{name: "round trip", device: host.Add(90 * time.Second), roundTrip: 2 * time.Second, want: 89 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, d := newFakeDevice(t)
+			d.setValue(types.URIDateTime, tt.device.Format(deviceTimeLayout))
+
+			calls := 0
+			c.now = func() time.Time {
+				calls++
+				if calls == 1 {
+					return host
+				}
+				return host.Add(tt.roundTrip)
+			}
+
+			drift, err := c.ClockDrift(t.Context())
+			if err != nil {
+				t.Fatalf("ClockDrift failed: %v", err)
+			}
+			if drift != tt.want {
+				t.Errorf("ClockDrift = %v, want %v", drift, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDeviceTime(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+
+	got, err := parseDeviceTime("2024-01-15T07:30:00", loc)
+	if err != nil || !got.Equal(time.Date(2024, time.January, 15, 6, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected local wall-clock time, got %v (err %v)", got, err)
+	}
+
+	got, err = parseDeviceTime("2024-01-15T07:30:00+02:00", loc)
+	if err != nil || !got.Equal(time.Date(2024, time.January, 15, 5, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the explicit offset to be used, got %v (err %v)", got, err)
+	}
+
+	if _, err := parseDeviceTime("not a time", loc); err == nil {
+		t.Error("Expected error for an invalid time")
+	}
+}
+
+func TestSetDeviceTime(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	at := time.Date(2024, time.March, 1, 12, 0, 5, 0, time.Local)
+	if err := c.SetDeviceTime(t.Context(), at); err != nil {
+		t.Fatalf("SetDeviceTime failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIDateTime || puts[0].Body != `{"value":"2024-03-01T12:00:05"}` {
+		t.Errorf("Unexpected PUT: %+v", puts)
+	}
+}
@@ -2,14 +2,27 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
 
 	"github.com/kradalby/nefit-go/types"
 )
 
 // Status retrieves the complete system status including temperatures, modes, and boiler state.
 // If includeOutdoorTemp is true, an additional request is made to fetch outdoor temperature data.
+// That request gets its own timeout carved from what is left of the context deadline, so a
+// slow status read cannot make it overrun the caller; if it times out, Status still returns
+// the main data with OutdoorTempSkipped set and a warning is sent on the Errors channel.
+// Temperatures are converted to Config.TemperatureUnit; the device values remain in Status.Celsius.
+// uiStatus describes the default heating circuit; for another circuit selected with WithCircuit,
+// UserMode and TempManualSetpoint are read from that circuit.
 func (c *Client) Status(ctx context.Context, includeOutdoorTemp bool) (*types.Status, error) {
+	ctx = ensureRequestID(ctx)
+
 	statusData, err := c.Get(ctx, types.URIStatus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
@@ -25,49 +38,139 @@ func (c *Client) Status(ctx context.Context, includeOutdoorTemp bool) (*types.St
 		return nil, fmt.Errorf("status response missing 'value' field")
 	}
 
-	status := &types.Status{
-		UserMode:                 getString(valueMap, "UMD"),
-		ClockProgram:             getString(valueMap, "CPM"),
-		InHouseStatus:            getString(valueMap, "IHS"),
-		InHouseTemp:              getFloat(valueMap, "IHT"),
-		HotWaterActive:           parseBoolean(getString(valueMap, "DHW")),
-		BoilerIndicator:          parseBoilerIndicator(getString(valueMap, "BAI")),
-		Control:                  getString(valueMap, "CTR"),
-		TempOverrideDuration:     getInt(valueMap, "TOD"),
-		CurrentSwitchpoint:       getInt(valueMap, "CSP"),
-		PSActive:                 parseBoolean(getString(valueMap, "ESI")),
-		PowersaveMode:            parseBoolean(getString(valueMap, "ESI")),
-		FPActive:                 parseBoolean(getString(valueMap, "FPA")),
-		FireplaceMode:            parseBoolean(getString(valueMap, "FPA")),
-		TempOverride:             parseBoolean(getString(valueMap, "TOR")),
-		HolidayMode:              parseBoolean(getString(valueMap, "HMD")),
-		BoilerBlock:              parseBoolean(getString(valueMap, "BBE")),
-		BoilerLock:               parseBoolean(getString(valueMap, "BLE")),
-		BoilerMaintenance:        parseBoolean(getString(valueMap, "BMR")),
-		TempSetpoint:             getFloat(valueMap, "TSP"),
-		TempOverrideTempSetpoint: getFloat(valueMap, "TOT"),
-		TempManualSetpoint:       getFloat(valueMap, "MMT"),
-		HEDEnabled:               parseBoolean(getString(valueMap, "HED_EN")),
-		HEDDeviceAtHome:          parseBoolean(getString(valueMap, "HED_DEV")),
+	status := types.ParseStatus(valueMap)
+	for _, w := range status.ParseWarnings {
+		c.log(ctx).Warn("malformed status field", "warning", w)
+	}
+
+	if CircuitFromContext(ctx) != types.DefaultCircuit {
+		if err := c.applyCircuitStatus(ctx, status); err != nil {
+			return nil, err
+		}
+	} else {
+		c.rememberUserMode(status.UserMode)
 	}
 
 	if includeOutdoorTemp {
-		outdoorData, err := c.Get(ctx, types.URIOutdoorTemp)
-		if err == nil {
-			if outdoorMap, ok := outdoorData.(map[string]interface{}); ok {
-				status.OutdoorTemp = getFloat(outdoorMap, "value")
-				status.OutdoorSourceType = getString(outdoorMap, "srcType")
-			}
+		c.fetchOutdoorTemp(ctx, status)
+	}
+
+	return status.InUnit(c.config.TemperatureUnit), nil
+}
+
+// RawStatus retrieves uiStatus without interpreting it. Keys the library does not know
+// are kept in UIStatus.Unknown, which helps to identify fields added by new firmware.
+func (c *Client) RawStatus(ctx context.Context) (*types.UIStatus, error) {
+	var doc struct {
+		Value *types.UIStatus `json:"value"`
+	}
+	if err := c.GetInto(ctx, types.URIStatus, &doc); err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	if doc.Value == nil {
+		return nil, fmt.Errorf("status response missing 'value' field")
+	}
+	return doc.Value, nil
+}
+
+// applyCircuitStatus replaces the fields uiStatus reports for the default circuit
+// (user mode and manual setpoint) with those of the circuit selected in ctx.
+func (c *Client) applyCircuitStatus(ctx context.Context, status *types.Status) error {
+	circuit := CircuitFromContext(ctx)
+
+	data, err := c.Get(ctx, circuitURI(ctx, types.URIUserMode))
+	if err != nil {
+		return fmt.Errorf("failed to get user mode of %s: %w", circuit, err)
+	}
+	if dataMap, ok := data.(map[string]interface{}); ok {
+		mode, _ := types.LookupString(dataMap, "value")
+		status.UserMode = types.UserMode(mode)
+	}
+
+	setpoint, err := c.getFloatValue(ctx, circuitURI(ctx, types.URIManualSetpoint))
+	if err != nil {
+		return fmt.Errorf("failed to get manual setpoint of %s: %w", circuit, err)
+	}
+	status.TempManualSetpoint = setpoint
+
+	return nil
+}
+
+// outdoorBudgetShare is the share of the remaining context budget given to the outdoor
+// temperature fetch in Status; the rest is left for returning to the caller.
+const outdoorBudgetShare = 0.75
+
+// fetchOutdoorTemp fills the outdoor fields of status. Failures are not fatal.
+func (c *Client) fetchOutdoorTemp(ctx context.Context, status *types.Status) {
+	timeout := c.config.RetryTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if budget := time.Duration(float64(time.Until(deadline)) * outdoorBudgetShare); budget < timeout {
+			timeout = budget
+		}
+	}
+
+	if timeout <= 0 {
+		status.OutdoorTempSkipped = true
+		c.reportError(fmt.Errorf("status: outdoor temperature skipped: no time left before deadline"))
+		return
+	}
+
+	outdoorCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	outdoorData, err := c.Get(outdoorCtx, types.URIOutdoorTemp)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			status.OutdoorTempSkipped = true
+			c.log(ctx).Warn("outdoor temperature skipped", "timeout", timeout)
+			c.reportError(fmt.Errorf("status: outdoor temperature skipped after %s: %w", timeout, err))
+			return
 		}
+		c.log(ctx).Debug("failed to get outdoor temperature", "error", err)
+		return
 	}
 
-	return status, nil
+	if outdoorMap, ok := outdoorData.(map[string]interface{}); ok {
+		status.OutdoorTemp, _ = types.LookupFloat(outdoorMap, "value")
+		status.OutdoorSourceType, _ = types.LookupString(outdoorMap, "srcType")
+	}
 }
 
 // Pressure retrieves the system pressure reading in bar.
 // Low pressure may indicate a leak or the need to refill the system.
 func (c *Client) Pressure(ctx context.Context) (*types.Pressure, error) {
-	data, err := c.Get(ctx, types.URIPressure)
+	return c.readPressure(ctx, types.URIPressure)
+}
+
+// Pressures retrieves every pressure the appliance reports, keyed by the names in
+// types.PressureURIs. Pressures the appliance does not expose are left out; it returns
+// ErrUnsupported if there are none at all.
+// Retries of the sub-requests share one RetryBudget of Config.MaxRetries, unless ctx already carries one.
+func (c *Client) Pressures(ctx context.Context) (map[string]types.Pressure, error) {
+	ctx = c.ensureRetryBudget(ensureRequestID(ctx), len(types.PressureURIs))
+
+	names := slices.Sorted(maps.Keys(types.PressureURIs))
+	pressures := make(map[string]types.Pressure, len(names))
+	for _, name := range names {
+		pressure, err := c.readPressure(ctx, types.PressureURIs[name])
+		if err != nil {
+			if isUnsupported(err) {
+				c.log(ctx).Debug("pressure not available", "name", name, "error", err)
+				continue
+			}
+			return nil, err
+		}
+		pressures[name] = *pressure
+	}
+
+	if len(pressures) == 0 {
+		return nil, fmt.Errorf("pressures: %w", ErrUnsupported)
+	}
+	return pressures, nil
+}
+
+func (c *Client) readPressure(ctx context.Context, uri string) (*types.Pressure, error) {
+	data, err := c.Get(ctx, uri)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pressure: %w", err)
 	}
@@ -77,95 +180,267 @@ func (c *Client) Pressure(ctx context.Context) (*types.Pressure, error) {
 		return nil, fmt.Errorf("unexpected pressure response type: %T", data)
 	}
 
-	pressure := &types.Pressure{
-		Pressure: getFloat(dataMap, "value"),
-		Unit:     getString(dataMap, "unitOfMeasure"),
-		MinValue: getFloat(dataMap, "minValue"),
-		MaxValue: getFloat(dataMap, "maxValue"),
+	return types.ParsePressure(dataMap), nil
+}
+
+// SetTemperature sets the manual temperature setpoint and enables manual override mode.
+// This requires three separate API calls to fully configure the temperature override. If the
+// device is in clock mode with the override already on, which is read fresh first, only the
+// override temperature is written; pass WithForce to always write all three.
+// The temperature is interpreted in Config.TemperatureUnit and converted to Celsius before sending.
+// The value is rounded to the nearest Config.TemperatureStep, or rejected if Config.StrictStep is set.
+// Pass WithConfirm to verify the device reports the new setpoint afterwards.
+// Retries of the sub-requests share one RetryBudget of Config.MaxRetries, unless ctx already carries one.
+func (c *Client) SetTemperature(ctx context.Context, temperature float64, opts ...WriteOption) error {
+	celsius, err := c.snapToStep(c.config.TemperatureUnit.ToCelsius(temperature))
+	if err != nil {
+		return err
+	}
+	return c.setTemperatureCelsius(ctx, celsius, opts...)
+}
+
+// snapToStep rounds a Celsius setpoint to the nearest Config.TemperatureStep.
+// With Config.StrictStep, values that are not already on a step are rejected; the error
+// gives the value and the step in Config.TemperatureUnit.
+func (c *Client) snapToStep(celsius float64) (float64, error) {
+	step := c.config.TemperatureStep
+	if step <= 0 {
+		return celsius, nil
+	}
+
+	snapped := math.Round(celsius/step) * step
+	// Tolerate float noise, e.g. from unit conversion.
+	if math.Abs(snapped-celsius) < 1e-6 {
+		return snapped, nil
 	}
 
-	return pressure, nil
+	if c.config.StrictStep {
+		unit := c.config.TemperatureUnit
+		if unit == "" {
+			unit = types.Celsius
+		}
+		return 0, fmt.Errorf("temperature %g°%s is not a multiple of the %g°%s step",
+			unit.FromCelsius(celsius), unit, unit.DeltaFromCelsius(step), unit)
+	}
+	return snapped, nil
 }
 
-// SetTemperature sets the manual temperature setpoint and enables manual override mode.
-// This requires three separate API calls to fully configure the temperature override.
-func (c *Client) SetTemperature(ctx context.Context, temperature float64) error {
+func (c *Client) setTemperatureCelsius(ctx context.Context, celsius float64, opts ...WriteOption) error {
+	ctx = ensureRequestID(ctx)
+	options := applyWriteOptions(opts)
+
+	requests := 4
+	if options.confirm {
+		requests++
+	}
+	ctx = c.ensureRetryBudget(ctx, requests)
+
 	data := map[string]interface{}{
-		"value": temperature,
+		"value": celsius,
+	}
+
+	if !options.force && c.overrideActive(ctx) {
+		// The override is on, so its temperature is the setpoint in effect; enabling
+		// it again and updating the manual setpoint is not needed.
+		c.log(ctx).Debug("override already active, writing override temperature only")
+		if err := c.Put(ctx, circuitURI(ctx, types.URIManualTempOverrideTemp), data); err != nil {
+			return fmt.Errorf("failed to set override temperature: %w", err)
+		}
+
+		if options.confirm {
+			if err := c.confirmValue(ctx, circuitURI(ctx, types.URIManualTempOverrideTemp), celsius, options.tolerance); err != nil {
+				return fmt.Errorf("failed to confirm temperature: %w", err)
+			}
+		}
+		return nil
 	}
 
-	if err := c.Put(ctx, types.URIManualSetpoint, data); err != nil {
+	if err := c.Put(ctx, circuitURI(ctx, types.URIManualSetpoint), data); err != nil {
 		return fmt.Errorf("failed to set manual temperature: %w", err)
 	}
 
 	overrideData := map[string]string{
 		"value": "on",
 	}
-	if err := c.Put(ctx, types.URIManualTempOverrideStatus, overrideData); err != nil {
+	if err := c.Put(ctx, circuitURI(ctx, types.URIManualTempOverrideStatus), overrideData); err != nil {
 		return fmt.Errorf("failed to enable manual override: %w", err)
 	}
 
-	if err := c.Put(ctx, types.URIManualTempOverrideTemp, data); err != nil {
+	if err := c.Put(ctx, circuitURI(ctx, types.URIManualTempOverrideTemp), data); err != nil {
 		return fmt.Errorf("failed to set override temperature: %w", err)
 	}
 
+	if options.confirm {
+		if err := c.confirmValue(ctx, circuitURI(ctx, types.URIManualSetpoint), celsius, options.tolerance); err != nil {
+			return fmt.Errorf("failed to confirm temperature: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// SetUserMode switches between "manual" and "clock" (scheduled) heating modes.
+// overrideActive reports whether the circuit in ctx follows its program with the
+// manual temperature override on, so that the override temperature is the setpoint in
+// effect. The state is read fresh, not from the QuickStatus cache: an override that
+// has ended since would make the override temperature write a no-op. In manual mode
+// the manual setpoint is in effect, so this reports false. Read errors count as not
+// active, so the caller falls back to the full write sequence.
+func (c *Client) overrideActive(ctx context.Context) bool {
+	if CircuitFromContext(ctx) == types.DefaultCircuit {
+		quick, err := c.fetchQuickStatus(ctx)
+		if err != nil {
+			c.log(ctx).Debug("cannot read override state", "error", err)
+			return false
+		}
+		return quick.UserMode == types.UserModeClock && quick.TempOverride
+	}
+
+	mode, err := c.getStringValue(ctx, circuitURI(ctx, types.URIUserMode))
+	if err != nil || types.UserMode(mode) != types.UserModeClock {
+		if err != nil {
+			c.log(ctx).Debug("cannot read user mode", "error", err)
+		}
+		return false
+	}
+	status, err := c.getStringValue(ctx, circuitURI(ctx, types.URIManualTempOverrideStatus))
+	if err != nil {
+		c.log(ctx).Debug("cannot read override state", "error", err)
+		return false
+	}
+	return status == "on"
+}
+
+// CancelTemperatureOverride turns the manual temperature override off, returning the
+// device to its program. Pass WithConfirm to verify the device reports the override as off afterwards.
+func (c *Client) CancelTemperatureOverride(ctx context.Context, opts ...WriteOption) error {
+	ctx = ensureRequestID(ctx)
+	options := applyWriteOptions(opts)
+
+	uri := circuitURI(ctx, types.URIManualTempOverrideStatus)
+	if err := c.Put(ctx, uri, map[string]string{"value": "off"}); err != nil {
+		return fmt.Errorf("failed to disable manual override: %w", err)
+	}
+
+	if options.confirm {
+		if err := c.confirmValue(ctx, uri, "off", options.tolerance); err != nil {
+			return fmt.Errorf("failed to confirm override cancellation: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetOverrideDuration limits how long a temperature override set with SetTemperature
+// lasts. The duration is rounded up to whole minutes and must be at least a minute.
+func (c *Client) SetOverrideDuration(ctx context.Context, duration time.Duration) error {
+	minutes, err := types.DurationMinutes(duration)
+	if err != nil {
+		return fmt.Errorf("invalid override duration: %w", err)
+	}
+
+	if err := c.Put(ctx, circuitURI(ctx, types.URIManualTempOverrideDuration), map[string]interface{}{"value": minutes}); err != nil {
+		return fmt.Errorf("failed to set override duration: %w", err)
+	}
+	return nil
+}
+
+// ResetBoilerFault clears a boiler lockout, equivalent to pressing the reset button on the appliance.
+//
+// Only a lockout (Status.BoilerLock) is resettable remotely. A blocking error (Status.BoilerBlock)
+// clears itself once its cause is gone, and a maintenance request (Status.BoilerMaintenance)
+// must be reset by a technician. The status is checked first and ErrNoResettableFault is
+// returned without writing if no lockout is active.
+func (c *Client) ResetBoilerFault(ctx context.Context) error {
+	ctx = ensureRequestID(ctx)
+
+	status, err := c.Status(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+
+	if !status.BoilerLock {
+		return ErrNoResettableFault
+	}
+
+	c.log(ctx).Info("resetting boiler lockout")
+
+	if err := c.Put(ctx, types.URIBoilerReset, map[string]string{"value": "on"}); err != nil {
+		return fmt.Errorf("failed to reset boiler fault: %w", err)
+	}
+
+	return nil
+}
+
+// SetUserMode switches between manual and clock (scheduled) heating modes.
 //
 // Valid mode values:
-//   - "manual": Manual heating mode - user controls temperature directly
-//   - "clock": Clock/scheduled mode - follows programmed heating schedule
+//   - types.UserModeManual: Manual heating mode - user controls temperature directly
+//   - types.UserModeClock: Clock/scheduled mode - follows programmed heating schedule
 //
 // Note: The API does NOT accept "off" as a mode value. To turn off heating,
 // use manual mode and set a low temperature, or disable hot water supply.
-func (c *Client) SetUserMode(ctx context.Context, mode string) error {
-	validModes := []string{"manual", "clock"}
-
-	// Validate mode
-	isValid := false
-	for _, valid := range validModes {
-		if mode == valid {
-			isValid = true
-			break
-		}
-	}
+//
+// The current mode is read first and the write is skipped if it already matches;
+// pass WithForce to always write.
+func (c *Client) SetUserMode(ctx context.Context, mode types.UserMode, opts ...WriteOption) error {
+	ctx = ensureRequestID(ctx)
 
-	if !isValid {
+	if !mode.Valid() {
 		return fmt.Errorf("invalid mode: %q (valid values are: 'manual', 'clock'). Note: 'off' is not a valid mode", mode)
 	}
 
+	uri := circuitURI(ctx, types.URIUserMode)
+
+	if !applyWriteOptions(opts).force && types.UserMode(c.currentValue(ctx, uri)) == mode {
+		c.log(ctx).Debug("user mode already set, skipping write", "mode", mode)
+		return nil
+	}
+
 	data := map[string]string{
-		"value": mode,
+		"value": string(mode),
 	}
 
-	c.logger.Debug("setting user mode",
+	c.log(ctx).Debug("setting user mode",
 		"mode", mode,
-		"uri", types.URIUserMode)
+		"uri", uri)
 
-	if err := c.Put(ctx, types.URIUserMode, data); err != nil {
-		c.logger.Error("failed to set user mode",
+	if err := c.Put(ctx, uri, data); err != nil {
+		c.log(ctx).Error("failed to set user mode",
 			"mode", mode,
 			"error", err)
+		// A timed-out write may still have been applied.
+		c.forgetUserMode()
 		return err
 	}
 
-	c.logger.Info("user mode set successfully", "mode", mode)
+	if CircuitFromContext(ctx) == types.DefaultCircuit && !c.config.DryRun {
+		c.rememberUserMode(mode)
+	}
+
+	c.log(ctx).Info("user mode set successfully", "mode", mode)
 	return nil
 }
 
-// SetHotWaterSupply enables or disables hot water supply.
-// The API endpoint used depends on the current user mode (manual vs clock).
-func (c *Client) SetHotWaterSupply(ctx context.Context, enabled bool) error {
-	status, err := c.Status(ctx, false)
+// SetUserModeString is SetUserMode for a mode given as text, parsed with types.ParseUserMode.
+func (c *Client) SetUserModeString(ctx context.Context, mode string, opts ...WriteOption) error {
+	m, err := types.ParseUserMode(mode)
 	if err != nil {
-		return fmt.Errorf("failed to get status: %w", err)
+		return err
 	}
+	return c.SetUserMode(ctx, m, opts...)
+}
 
-	endpoint := types.URIHotWaterManualMode
-	if status.UserMode == "clock" {
-		endpoint = types.URIHotWaterClockMode
+// SetHotWaterSupply enables or disables hot water supply.
+// The API endpoint used depends on the current user mode (manual vs clock), which is taken
+// from the mode last seen by Status, SetUserMode or a push notification when that is recent,
+// and read from the device otherwise.
+// The write is skipped if the supply is already in the requested state; pass WithForce to always write.
+func (c *Client) SetHotWaterSupply(ctx context.Context, enabled bool, opts ...WriteOption) error {
+	ctx = ensureRequestID(ctx)
+
+	endpoint, err := c.hotWaterEndpoint(ctx)
+	if err != nil {
+		return err
 	}
 
 	value := "off"
@@ -173,6 +448,11 @@ func (c *Client) SetHotWaterSupply(ctx context.Context, enabled bool) error {
 		value = "on"
 	}
 
+	if !applyWriteOptions(opts).force && c.currentValue(ctx, endpoint) == value {
+		c.log(ctx).Debug("hot water supply already set, skipping write", "value", value)
+		return nil
+	}
+
 	data := map[string]string{
 		"value": value,
 	}
@@ -180,17 +460,30 @@ func (c *Client) SetHotWaterSupply(ctx context.Context, enabled bool) error {
 	return c.Put(ctx, endpoint, data)
 }
 
-// HotWaterSupply retrieves the current hot water supply status (on/off).
-// The API endpoint used depends on the current user mode (manual vs clock).
-func (c *Client) HotWaterSupply(ctx context.Context) (bool, error) {
-	status, err := c.Status(ctx, false)
+// currentValue returns the string "value" of uri, or "" if it cannot be read.
+func (c *Client) currentValue(ctx context.Context, uri string) string {
+	data, err := c.Get(ctx, uri)
 	if err != nil {
-		return false, fmt.Errorf("failed to get status: %w", err)
+		c.log(ctx).Debug("failed to read current value", "uri", uri, "error", err)
+		return ""
 	}
 
-	endpoint := types.URIHotWaterManualMode
-	if status.UserMode == "clock" {
-		endpoint = types.URIHotWaterClockMode
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := types.LookupString(dataMap, "value")
+	return value
+}
+
+// HotWaterSupply retrieves the current hot water supply status (on/off).
+// The endpoint is chosen by user mode as in SetHotWaterSupply.
+func (c *Client) HotWaterSupply(ctx context.Context) (bool, error) {
+	ctx = ensureRequestID(ctx)
+
+	endpoint, err := c.hotWaterEndpoint(ctx)
+	if err != nil {
+		return false, err
 	}
 
 	data, err := c.Get(ctx, endpoint)
@@ -203,72 +496,6 @@ func (c *Client) HotWaterSupply(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("unexpected response type: %T", data)
 	}
 
-	value := getString(dataMap, "value")
+	value, _ := types.LookupString(dataMap, "value")
 	return value == "on", nil
 }
-
-func getString(m map[string]interface{}, key string) string {
-	if val, ok := m[key]; ok {
-		if str, ok := val.(string); ok {
-			return str
-		}
-	}
-	return ""
-}
-
-func getFloat(m map[string]interface{}, key string) float64 {
-	if val, ok := m[key]; ok {
-		switch v := val.(type) {
-		case float64:
-			return v
-		case float32:
-			return float64(v)
-		case int:
-			return float64(v)
-		case int64:
-			return float64(v)
-		case string:
-			var f float64
-			_, _ = fmt.Sscanf(v, "%f", &f)
-			return f
-		}
-	}
-	return 0
-}
-
-func getInt(m map[string]interface{}, key string) int {
-	if val, ok := m[key]; ok {
-		switch v := val.(type) {
-		case int:
-			return v
-		case int64:
-			return int(v)
-		case float64:
-			return int(v)
-		case float32:
-			return int(v)
-		case string:
-			var i int
-			_, _ = fmt.Sscanf(v, "%d", &i)
-			return i
-		}
-	}
-	return 0
-}
-
-func parseBoolean(val string) bool {
-	return val == "on"
-}
-
-func parseBoilerIndicator(val string) string {
-	switch val {
-	case "CH":
-		return "central heating"
-	case "HW":
-		return "hot water"
-	case "No":
-		return "off"
-	default:
-		return val
-	}
-}
@@ -0,0 +1,497 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestSetUserModeSkipsMatchingMode(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIUserMode, "clock")
+
+	if err := c.SetUserMode(t.Context(), "clock"); err != nil {
+		t.Fatalf("SetUserMode failed: %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 0 {
+		t.Errorf("Expected no PUT when mode already matches, got %+v", puts)
+	}
+
+	if err := c.SetUserMode(t.Context(), "clock", WithForce()); err != nil {
+		t.Fatalf("SetUserMode with force failed: %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 1 {
+		t.Errorf("Expected one forced PUT, got %+v", puts)
+	}
+
+	if err := c.SetUserMode(t.Context(), "manual"); err != nil {
+		t.Fatalf("SetUserMode failed: %v", err)
+	}
+	puts := d.requestsFor("PUT")
+	if len(puts) != 2 || puts[1].URI != types.URIUserMode || puts[1].Body != `{"value":"manual"}` {
+		t.Errorf("Expected PUT switching to manual, got %+v", puts)
+	}
+}
+
+func TestSetTemperatureConfirm(t *testing.T) {
+	c, _ := newFakeDevice(t)
+
+	if err := c.SetTemperature(t.Context(), 21.5, WithConfirm(0.1)); err != nil {
+		t.Fatalf("Confirmed SetTemperature failed: %v", err)
+	}
+}
+
+func TestSetTemperatureConfirmMismatch(t *testing.T) {
+	defer func(d time.Duration) { confirmRetryDelay = d }(confirmRetryDelay)
+	confirmRetryDelay = time.Millisecond
+
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIManualSetpoint, 20.0)
+	d.ignorePuts(types.URIManualSetpoint)
+
+	err := c.SetTemperature(t.Context(), 21.5, WithConfirm(0.1))
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected MismatchError, got %v", err)
+	}
+	if mismatch.Want != 21.5 || mismatch.Got != 20.0 {
+		t.Errorf("Unexpected mismatch values: %+v", mismatch)
+	}
+
+	if err := c.SetTemperature(t.Context(), 20.4, WithConfirm(0.5)); err != nil {
+		t.Errorf("Expected value within tolerance to confirm, got %v", err)
+	}
+}
+
+func TestCancelTemperatureOverride(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIManualTempOverrideStatus, "on")
+
+	if err := c.CancelTemperatureOverride(t.Context(), WithConfirm(0)); err != nil {
+		t.Fatalf("CancelTemperatureOverride failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIManualTempOverrideStatus || puts[0].Body != `{"value":"off"}` {
+		t.Fatalf("Expected a single PUT of off to the override status, got %+v", puts)
+	}
+	if gets := d.requestsFor("GET"); len(gets) != 1 || gets[0].URI != types.URIManualTempOverrideStatus {
+		t.Errorf("Expected one read-back of the override status, got %+v", gets)
+	}
+}
+
+func TestResetBoilerFault(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  map[string]interface{}
+		wantPut bool
+	}{
+		{name: "lockout", status: map[string]interface{}{"BLE": "on"}, wantPut: true},
+		{name: "maintenance only", status: map[string]interface{}{"BLE": "off", "BMR": "on", "BBE": "on"}},
+		{name: "no fault", status: map[string]interface{}{"BLE": "off"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, d := newFakeDevice(t)
+			d.setValue(types.URIStatus, tt.status)
+
+			err := c.ResetBoilerFault(t.Context())
+			puts := d.requestsFor("PUT")
+
+			if tt.wantPut {
+				if err != nil {
+					t.Fatalf("ResetBoilerFault failed: %v", err)
+				}
+				if len(puts) != 1 || puts[0].URI != types.URIBoilerReset {
+					t.Errorf("Expected a single reset PUT, got %+v", puts)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrNoResettableFault) {
+				t.Errorf("Expected ErrNoResettableFault, got %v", err)
+			}
+			if len(puts) != 0 {
+				t.Errorf("Expected no PUT, got %+v", puts)
+			}
+		})
+	}
+}
+
+func TestStatusSkipsSlowOutdoorTemp(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock", "IHT": "20.5"})
+	d.hang(types.URIOutdoorTemp)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 400*time.Millisecond)
+	defer cancel()
+
+	status, err := c.Status(ctx, true)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("Status returned after the caller's deadline")
+	}
+
+	if status.UserMode != "clock" || status.InHouseTemp != 20.5 {
+		t.Errorf("Expected main status data, got %+v", status)
+	}
+	if !status.OutdoorTempSkipped {
+		t.Error("Expected OutdoorTempSkipped to be set")
+	}
+
+	select {
+	case err := <-c.Errors():
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline warning, got %v", err)
+		}
+	default:
+		t.Error("Expected a warning on the Errors channel")
+	}
+}
+
+func TestStatusIncludesOutdoorTemp(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock"})
+	d.set(types.URIOutdoorTemp, `{"id":"/system/sensors/temperatures/outdoor_t1","value":7.5,"srcType":"virtual"}`)
+
+	status, err := c.Status(t.Context(), true)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.OutdoorTemp != 7.5 || status.OutdoorSourceType != "virtual" || status.OutdoorTempSkipped {
+		t.Errorf("Unexpected outdoor fields: %+v", status)
+	}
+}
+
+func TestSetTemperatureRoundsToStep(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	if err := c.SetTemperature(t.Context(), 21.37); err != nil {
+		t.Fatalf("SetTemperature failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) == 0 || puts[0].Body != `{"value":21.5}` {
+		t.Fatalf("Expected setpoint rounded to 21.5, got %+v", puts)
+	}
+}
+
+func TestSetTemperatureStrictStep(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.StrictStep = true
+
+	if err := c.SetTemperature(t.Context(), 21.37); err == nil {
+		t.Fatal("Expected strict step to reject 21.37")
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 0 {
+		t.Errorf("Expected no PUT for rejected temperature, got %+v", puts)
+	}
+
+	if err := c.SetTemperature(t.Context(), 21.5); err != nil {
+		t.Errorf("Expected on-step temperature to be accepted, got %v", err)
+	}
+
+	// The error speaks the configured unit: 70.3°F is 21.3°C, off the 0.5°C (0.9°F) step.
+	c.config.TemperatureUnit = types.Fahrenheit
+	err := c.SetTemperature(t.Context(), 70.3)
+	if err == nil || err.Error() != "temperature 70.3°F is not a multiple of the 0.9°F step" {
+		t.Errorf("Expected the error in Fahrenheit, got %v", err)
+	}
+}
+
+func TestStatusReportsMalformedFields(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{
+		"UMD": "clock",
+		"IHT": "n/a",
+		"TSP": "21.5",
+		"TOD": "",
+	})
+
+	status, err := c.Status(t.Context(), false)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+
+	if status.TempSetpoint != 21.5 {
+		t.Errorf("Expected valid TSP to parse, got %v", status.TempSetpoint)
+	}
+	if len(status.ParseWarnings) != 1 || !strings.Contains(status.ParseWarnings[0], "IHT") {
+		t.Errorf("Expected a single warning for IHT, got %v", status.ParseWarnings)
+	}
+}
+
+func TestHotWaterSupplyUsesCachedUserMode(t *testing.T) {
+	c, d := newFakeDevice(t)
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock"})
+	d.setValue(types.URIHotWaterClockMode, "off")
+	d.setValue(types.URIHotWaterManualMode, "off")
+
+	statusGets := func() int {
+		n := 0
+		for _, r := range d.requestsFor("GET") {
+			if r.URI == types.URIStatus {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Cold cache: the mode is read from the device.
+	if _, err := c.HotWaterSupply(t.Context()); err != nil {
+		t.Fatalf("HotWaterSupply failed: %v", err)
+	}
+	if n := statusGets(); n != 1 {
+		t.Fatalf("Expected one status GET on a cold cache, got %d", n)
+	}
+
+	// Fresh cache: no status GET, and the clock-mode endpoint is written.
+	if err := c.SetHotWaterSupply(t.Context(), true); err != nil {
+		t.Fatalf("SetHotWaterSupply failed: %v", err)
+	}
+	if n := statusGets(); n != 1 {
+		t.Errorf("Expected no status GET with a fresh cache, got %d in total", n)
+	}
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIHotWaterClockMode {
+		t.Fatalf("Expected a PUT to the clock-mode endpoint, got %+v", puts)
+	}
+
+	// SetUserMode updates the cache.
+	if err := c.SetUserMode(t.Context(), "manual"); err != nil {
+		t.Fatalf("SetUserMode failed: %v", err)
+	}
+	if err := c.SetHotWaterSupply(t.Context(), true); err != nil {
+		t.Fatalf("SetHotWaterSupply failed: %v", err)
+	}
+	puts = d.requestsFor("PUT")
+	if last := puts[len(puts)-1]; last.URI != types.URIHotWaterManualMode {
+		t.Errorf("Expected a PUT to the manual-mode endpoint after SetUserMode, got %+v", last)
+	}
+	if n := statusGets(); n != 1 {
+		t.Errorf("Expected no status GET after SetUserMode, got %d in total", n)
+	}
+
+	// An expired cache falls back to a status read.
+	now = now.Add(userModeTTL)
+	if _, err := c.HotWaterSupply(t.Context()); err != nil {
+		t.Fatalf("HotWaterSupply failed: %v", err)
+	}
+	if n := statusGets(); n != 2 {
+		t.Errorf("Expected a status GET once the cache expired, got %d in total", n)
+	}
+}
+
+func TestUserModeCacheFromPush(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"id":"/heatingCircuits/hc1/usermode","value":"clock"}`})
+	deadline := time.Now().Add(time.Second)
+	for {
+		if mode, ok := c.cachedMode(); ok && mode == "clock" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the pushed user mode to be cached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A partial status push without UMD keeps the cached mode.
+	c.observeUserMode(types.URIStatus, map[string]interface{}{"value": map[string]interface{}{"IHT": "20.0"}})
+	if mode, ok := c.cachedMode(); !ok || mode != "clock" {
+		t.Errorf("Expected cached mode clock to survive a partial push, got %q (%v)", mode, ok)
+	}
+
+	c.forgetUserMode()
+	if _, ok := c.cachedMode(); ok {
+		t.Error("Expected no cached mode after invalidation")
+	}
+}
+
+func TestQuickStatusCache(t *testing.T) {
+	c, d := newFakeDevice(t)
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "manual", "IHT": "20.5", "TSP": "21", "TOR": "on", "BAI": "CH"})
+
+	statusGets := func() int {
+		n := 0
+		for _, r := range d.requestsFor("GET") {
+			if r.URI == types.URIStatus {
+				n++
+			}
+		}
+		return n
+	}
+
+	quick, err := c.QuickStatus(t.Context())
+	if err != nil {
+		t.Fatalf("QuickStatus failed: %v", err)
+	}
+	want := types.QuickStatus{UserMode: types.UserModeManual, InHouseTemp: 20.5, TempSetpoint: 21, TempOverride: true}
+	if *quick != want {
+		t.Errorf("QuickStatus = %+v, want %+v", *quick, want)
+	}
+
+	now = now.Add(quickStatusTTL / 2)
+	if _, err := c.QuickStatus(t.Context()); err != nil {
+		t.Fatalf("QuickStatus failed: %v", err)
+	}
+	if n := statusGets(); n != 1 {
+		t.Errorf("Expected a cached result within the TTL, got %d status GETs", n)
+	}
+
+	// A write drops the cache.
+	if err := c.Put(t.Context(), types.URIManualSetpoint, map[string]interface{}{"value": 19}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := c.QuickStatus(t.Context()); err != nil {
+		t.Fatalf("QuickStatus failed: %v", err)
+	}
+	if n := statusGets(); n != 2 {
+		t.Errorf("Expected a status GET after a PUT, got %d in total", n)
+	}
+
+	now = now.Add(quickStatusTTL)
+	if _, err := c.QuickStatus(t.Context()); err != nil {
+		t.Fatalf("QuickStatus failed: %v", err)
+	}
+	if n := statusGets(); n != 3 {
+		t.Errorf("Expected a status GET once the TTL expired, got %d in total", n)
+	}
+}
+
+func TestRawStatus(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set(types.URIStatus, `{"id":"/ecus/rrc/uiStatus","value":{"UMD":"clock","IHT":"20.85","NEW":"x"}}`)
+
+	status, err := c.RawStatus(t.Context())
+	if err != nil {
+		t.Fatalf("RawStatus failed: %v", err)
+	}
+	if status.UMD != "clock" || status.IHT != "20.85" {
+		t.Errorf("Unexpected known fields: %+v", status)
+	}
+	if string(status.Unknown["NEW"]) != `"x"` || len(status.Unknown) != 1 {
+		t.Errorf("Expected NEW in Unknown, got %s", status.Unknown)
+	}
+}
+
+func TestSetOverrideDuration(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	if err := c.SetOverrideDuration(t.Context(), 30*time.Second); err == nil {
+		t.Error("Expected a sub-minute duration to be rejected")
+	}
+	if err := c.SetOverrideDuration(t.Context(), 90*time.Minute+time.Second); err != nil {
+		t.Fatalf("SetOverrideDuration failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIManualTempOverrideDuration || puts[0].Body != `{"value":91}` {
+		t.Errorf("Expected one PUT of 91 minutes, got %+v", puts)
+	}
+}
+
+func TestSetTemperatureSkipsActiveOverride(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock", "TOR": "on", "TSP": "20.0"})
+
+	if err := c.SetTemperature(t.Context(), 21.5); err != nil {
+		t.Fatalf("SetTemperature failed: %v", err)
+	}
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIManualTempOverrideTemp || puts[0].Body != `{"value":21.5}` {
+		t.Fatalf("Expected only the override temperature PUT, got %+v", puts)
+	}
+
+	if err := c.SetTemperature(t.Context(), 22, WithForce()); err != nil {
+		t.Fatalf("Forced SetTemperature failed: %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 4 {
+		t.Errorf("Expected WithForce to issue all three writes, got %+v", puts[1:])
+	}
+}
+
+func TestSetTemperatureRereadsOverride(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		status map[string]interface{}
+	}{
+		{"override ended", map[string]interface{}{"UMD": "clock", "TOR": "off", "TSP": "20.0"}},
+		{"manual mode", map[string]interface{}{"UMD": "manual", "TOR": "on", "TSP": "20.0"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, d := newFakeDevice(t)
+			d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock", "TOR": "on", "TSP": "20.0"})
+			if _, err := c.QuickStatus(t.Context()); err != nil {
+				t.Fatalf("QuickStatus failed: %v", err)
+			}
+
+			// The cached status still reports the override as on.
+			d.setValue(types.URIStatus, tt.status)
+			if err := c.SetTemperature(t.Context(), 21.5); err != nil {
+				t.Fatalf("SetTemperature failed: %v", err)
+			}
+			puts := d.requestsFor("PUT")
+			if len(puts) != 3 || puts[0].URI != types.URIManualSetpoint {
+				t.Errorf("Expected the full write sequence, got %+v", puts)
+			}
+		})
+	}
+}
+
+func TestPressures(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set(types.URIPressure, `{"id":"/system/appliance/systemPressure","value":1.6,"unitOfMeasure":"bar","minValue":0,"maxValue":25}`)
+	d.set(types.URIHotWaterPressure, `{"id":"/dhwCircuits/dhwA/waterPressure","value":2.4,"unitOfMeasure":"bar"}`)
+
+	got, err := c.Pressures(t.Context())
+	if err != nil {
+		t.Fatalf("Pressures failed: %v", err)
+	}
+	want := map[string]types.Pressure{
+		types.PressureSystem:   {Pressure: 1.6, Unit: "bar", MaxValue: 25},
+		types.PressureHotWater: {Pressure: 2.4, Unit: "bar"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Pressures = %+v, want %+v", got, want)
+	}
+
+	primary, err := c.Pressure(t.Context())
+	if err != nil || *primary != want[types.PressureSystem] {
+		t.Errorf("Pressure = %+v, %v; want the system pressure", primary, err)
+	}
+}
+
+func TestPressuresSingleSensor(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set(types.URIPressure, `{"value":1.2,"unitOfMeasure":"bar"}`)
+
+	got, err := c.Pressures(t.Context())
+	if err != nil {
+		t.Fatalf("Pressures failed: %v", err)
+	}
+	if len(got) != 1 || got[types.PressureSystem].Pressure != 1.2 {
+		t.Errorf("Pressures = %+v, want only the system pressure", got)
+	}
+
+	c, _ = newFakeDevice(t)
+	if _, err := c.Pressures(t.Context()); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Pressures without sensors: got %v, want ErrUnsupported", err)
+	}
+}
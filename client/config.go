@@ -2,22 +2,38 @@ package client
 
 import (
 	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/kradalby/nefit-go/protocol"
+	"github.com/kradalby/nefit-go/types"
 )
 
 const (
 	DefaultHost = "wa2-mz36-qrmzh6.bosch.de"
 	DefaultPort = 5222
 
-	// AccessKeyPrefix is prepended to the access key for authentication
+	// AccessKeyPrefix is the default prefix prepended to the access key for authentication
 	AccessKeyPrefix = "Ct7ZR03b_"
 
+	// RRCContactPrefix and RRCGatewayPrefix are the default client and backend JID prefixes.
 	RRCContactPrefix = "rrccontact_"
 	RRCGatewayPrefix = "rrcgateway_"
 
 	DefaultPingInterval = 30 * time.Second
 	DefaultMaxRetries   = 3 // Reduced from 15 - we now use exponential backoff
 	DefaultRetryTimeout = 2 * time.Second
+
+	// DefaultTemperatureStep is the setpoint granularity accepted by Nefit Easy thermostats.
+	DefaultTemperatureStep = 0.5
+
+	// AccessKeyLength is the length of the access key printed on the Nefit Easy gateway.
+	AccessKeyLength = 16
+	// MinSerialLength is the minimum number of digits of a gateway serial number.
+	MinSerialLength = 9
 )
 
 // Config holds the configuration for a Nefit Easy client.
@@ -26,11 +42,88 @@ type Config struct {
 	AccessKey    string
 	Password     string
 
+	// SkipCredentialFormatCheck makes Validate accept serial numbers and access keys that do
+	// not match the Nefit Easy formats, e.g. for other brands using the same backend.
+	SkipCredentialFormatCheck bool
+
 	Host         string
 	Port         int
 	PingInterval time.Duration
 	MaxRetries   int
 	RetryTimeout time.Duration
+
+	// RetryPolicy decides whether a failed Get or Put attempt is retried, given its error
+	// and the number of attempts made so far (1 after the first failure). API errors are
+	// *APIError, so a policy can e.g. also retry 503s. Nil retries Get attempts that timed
+	// out and Put attempts according to DefaultRetryPolicy.
+	RetryPolicy func(err error, attempt int) bool
+
+	// AccessKeyPrefix, ContactPrefix and GatewayPrefix override the Nefit-branded
+	// authentication and JID prefixes (defaults AccessKeyPrefix, RRCContactPrefix and
+	// RRCGatewayPrefix) for other Bosch brands that speak the same protocol.
+	AccessKeyPrefix string
+	ContactPrefix   string
+	GatewayPrefix   string
+
+	// PingTimeout is how long the connection may go without a presence from the backend
+	// (the answer to keepalive pings) before it is considered dead and ErrConnectionDead
+	// is reported on the Errors channel. Zero disables the check.
+	PingTimeout time.Duration
+
+	// HandlerConcurrency bounds how many event handler calls run at once. When all slots
+	// are busy, dispatch waits, so slow handlers apply backpressure (pushes arriving
+	// meanwhile are queued and eventually dropped) instead of spawning unbounded goroutines.
+	// Zero keeps the default of one goroutine per handler per notification.
+	HandlerConcurrency int
+
+	// DryRun makes write operations log the URI and JSON they would send and return
+	// success without contacting the device. Reads are still performed.
+	DryRun bool
+
+	// LogAttrs are added to every log line of the client, e.g. a device name, next to
+	// the "serial" attribute it always carries.
+	LogAttrs []slog.Attr
+
+	// RedactSerial logs a short keyed hash (HMAC with the password) of the serial number
+	// instead of the number itself.
+	RedactSerial bool
+
+	// AwayStateFile, if set, is where SetAway persists the state to restore,
+	// so ClearAway works across process restarts.
+	AwayStateFile string
+
+	// MagicKey overrides the hex-encoded magic key mixed into the encryption key, for Bosch
+	// backends other than Nefit Easy. Empty uses the Nefit Easy key.
+	MagicKey string
+
+	// Resource, if set, is the XMPP resource bound to the client JID, for backends that
+	// route pushes to a fixed resource. Empty lets the server assign one.
+	Resource string
+
+	// UserAgent is sent in the User-Agent header of every request (default "NefitEasy").
+	UserAgent string
+
+	// HTTPVersion overrides the version in the HTTP-over-XMPP request line (default "HTTP/1.1").
+	HTTPVersion string
+
+	// Accept, if set, is sent as the Accept header of every request (e.g. "application/json").
+	Accept string
+
+	// SniffJSON makes responses without a Content-Type, or labelled application/octet-stream,
+	// decode as JSON when the body parses as JSON, for firmware that omits the header.
+	// Bodies that do not parse are returned as before.
+	SniffJSON bool
+
+	// TemperatureStep is the setpoint granularity of the device in Celsius (default 0.5).
+	// SetTemperature rounds to the nearest step, or rejects off-step values if StrictStep is set.
+	TemperatureStep float64
+
+	// StrictStep makes SetTemperature return an error instead of rounding off-step values.
+	StrictStep bool
+
+	// TemperatureUnit controls the unit of temperatures passed to and returned from the client
+	// (default Celsius). Conversion only affects Go-facing values; the device always uses Celsius.
+	TemperatureUnit types.TemperatureUnit
 }
 
 // Validate ensures all required credentials are present.
@@ -44,9 +137,54 @@ func (c *Config) Validate() error {
 	if c.Password == "" {
 		return fmt.Errorf("password is required")
 	}
+	if !c.SkipCredentialFormatCheck {
+		if err := validateCredentialFormat(c.SerialNumber, c.AccessKey); err != nil {
+			return err
+		}
+	}
+	if c.HandlerConcurrency < 0 {
+		return fmt.Errorf("handler concurrency must not be negative")
+	}
+	if c.TemperatureStep < 0 {
+		return fmt.Errorf("temperature step must not be negative")
+	}
+	if c.TemperatureUnit != "" && !c.TemperatureUnit.Valid() {
+		return fmt.Errorf("invalid temperature unit %q", c.TemperatureUnit)
+	}
+	return nil
+}
+
+// validateCredentialFormat checks the serial number and access key against the formats
+// printed on the gateway, so typos fail here instead of as an opaque SASL error on connect.
+// The serial is numeric with at least MinSerialLength digits; the access key has exactly
+// AccessKeyLength letters or digits (keys consisting only of digits are valid).
+func validateCredentialFormat(serial, accessKey string) error {
+	if !isDigits(serial) {
+		return fmt.Errorf("serial number must be numeric, got %q", serial)
+	}
+	if len(serial) < MinSerialLength {
+		return fmt.Errorf("serial number must have at least %d digits, got %d", MinSerialLength, len(serial))
+	}
+	if len(accessKey) != AccessKeyLength {
+		return fmt.Errorf("access key must be %d characters, got %d", AccessKeyLength, len(accessKey))
+	}
+	for _, r := range accessKey {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return fmt.Errorf("access key must only contain letters and digits, got %q", r)
+		}
+	}
 	return nil
 }
 
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
 // WithDefaults returns a copy of the config with unset fields populated from defaults.
 func (c Config) WithDefaults() Config {
 	if c.Host == "" {
@@ -64,22 +202,103 @@ func (c Config) WithDefaults() Config {
 	if c.RetryTimeout == 0 {
 		c.RetryTimeout = DefaultRetryTimeout
 	}
+	if c.AccessKeyPrefix == "" {
+		c.AccessKeyPrefix = AccessKeyPrefix
+	}
+	if c.ContactPrefix == "" {
+		c.ContactPrefix = RRCContactPrefix
+	}
+	if c.GatewayPrefix == "" {
+		c.GatewayPrefix = RRCGatewayPrefix
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = protocol.DefaultUserAgent
+	}
+	if c.TemperatureUnit == "" {
+		c.TemperatureUnit = types.Celsius
+	}
+	if c.TemperatureStep == 0 {
+		c.TemperatureStep = DefaultTemperatureStep
+	}
 	return c
 }
 
-// JID returns the client JID used as the "from" address in XMPP messages.
-// Format: rrccontact_SERIAL@HOST
+// JID returns the client JID used as the "from" address in XMPP messages:
+// ContactPrefix, serial number and host, e.g. rrccontact_SERIAL@HOST. An empty
+// ContactPrefix means RRCContactPrefix, so configs built without WithDefaults work too.
 func (c *Config) JID() string {
-	return fmt.Sprintf("%s%s@%s", RRCContactPrefix, c.SerialNumber, c.Host)
+	return fmt.Sprintf("%s%s@%s", orDefault(c.ContactPrefix, RRCContactPrefix), c.SerialNumber, c.Host)
 }
 
-// ResourceJID returns the backend JID used as the "to" address in XMPP messages.
-// Format: rrcgateway_SERIAL@HOST
+// ResourceJID returns the backend JID used as the "to" address in XMPP messages:
+// GatewayPrefix, serial number and host, e.g. rrcgateway_SERIAL@HOST. An empty
+// GatewayPrefix means RRCGatewayPrefix.
 func (c *Config) ResourceJID() string {
-	return fmt.Sprintf("%s%s@%s", RRCGatewayPrefix, c.SerialNumber, c.Host)
+	return fmt.Sprintf("%s%s@%s", orDefault(c.GatewayPrefix, RRCGatewayPrefix), c.SerialNumber, c.Host)
 }
 
-// AuthPassword returns the authentication password by prepending the required prefix to the access key.
+// AuthPassword returns the authentication password by prepending AccessKeyPrefix (the
+// AccessKeyPrefix constant if empty) to the access key.
 func (c *Config) AuthPassword() string {
-	return AccessKeyPrefix + c.AccessKey
+	return orDefault(c.AccessKeyPrefix, AccessKeyPrefix) + c.AccessKey
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// ParseConnectString parses credentials in the form "serial:accesskey:password"
+// or "nefit://serial:accesskey:password@host:port" (host and port optional) into a Config.
+// The password may itself contain colons.
+func ParseConnectString(s string) (Config, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Config{}, fmt.Errorf("connect string is empty")
+	}
+
+	if !strings.Contains(s, "://") {
+		parts := strings.SplitN(s, ":", 3)
+		if len(parts) != 3 {
+			return Config{}, fmt.Errorf("invalid connect string: expected serial:accesskey:password")
+		}
+		config := Config{SerialNumber: parts[0], AccessKey: parts[1], Password: parts[2]}
+		return config, config.Validate()
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid connect string: %w", err)
+	}
+	if u.Scheme != "nefit" {
+		return Config{}, fmt.Errorf("invalid connect string: unsupported scheme %q (expected nefit://)", u.Scheme)
+	}
+	if u.User == nil {
+		return Config{}, fmt.Errorf("invalid connect string: missing credentials before '@'")
+	}
+
+	rest, _ := u.User.Password()
+	accessKey, password, ok := strings.Cut(rest, ":")
+	if !ok {
+		return Config{}, fmt.Errorf("invalid connect string: expected nefit://serial:accesskey:password@host")
+	}
+
+	config := Config{
+		SerialNumber: u.User.Username(),
+		AccessKey:    accessKey,
+		Password:     password,
+		Host:         u.Hostname(),
+	}
+
+	if p := u.Port(); p != "" {
+		port, err := strconv.Atoi(p)
+		if err != nil || port <= 0 || port > 65535 {
+			return Config{}, fmt.Errorf("invalid connect string: invalid port %q", p)
+		}
+		config.Port = port
+	}
+
+	return config, config.Validate()
 }
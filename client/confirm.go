@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// confirmReadAttempts and confirmRetryDelay control how often a confirmed write
+// reads the value back, since some firmware applies writes with a short delay.
+var (
+	confirmReadAttempts = 2
+	confirmRetryDelay   = 500 * time.Millisecond
+)
+
+// confirmValue reads uri back until its "value" matches want (within tolerance for numbers).
+func (c *Client) confirmValue(ctx context.Context, uri string, want interface{}, tolerance float64) error {
+	var got interface{}
+
+	for attempt := 0; attempt < confirmReadAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(confirmRetryDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		data, err := c.Get(ctx, uri)
+		if err != nil {
+			return fmt.Errorf("failed to read back %s: %w", uri, err)
+		}
+
+		dataMap, ok := data.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected read-back response type: %T", data)
+		}
+
+		got = dataMap["value"]
+		if valuesMatch(want, got, tolerance) {
+			return nil
+		}
+
+		c.log(ctx).Debug("read-back value does not match yet", "uri", uri, "want", want, "got", got, "attempt", attempt+1)
+	}
+
+	return &MismatchError{URI: uri, Want: want, Got: got}
+}
+
+func valuesMatch(want, got interface{}, tolerance float64) bool {
+	wantF, wantNum := toFloat(want)
+	gotF, gotNum := toFloat(got)
+	if wantNum && gotNum {
+		return math.Abs(wantF-gotF) <= tolerance
+	}
+	return fmt.Sprint(want) == fmt.Sprint(got)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"encoding/json"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// decodeBody decodes a decrypted response body according to its content type.
+// JSON documents are unmarshalled (falling back to the string if they are malformed),
+// text/plain bodies become a bool, float64 or string, and a missing content type
+// leaves the body as a string. Any other content type, such as
+// application/octet-stream, is returned as a *types.RawResponse carrying it.
+//
+// With sniffJSON, bodies without a content type or labelled application/octet-stream
+// are unmarshalled if they parse as JSON (see Config.SniffJSON).
+func decodeBody(contentType, body string, sniffJSON bool) interface{} {
+	mediaType := strings.ToLower(strings.TrimSpace(contentType))
+	if parsed, _, err := mime.ParseMediaType(contentType); err == nil {
+		mediaType = parsed
+	}
+
+	if sniffJSON && (mediaType == "" || mediaType == "application/octet-stream") {
+		var value interface{}
+		if err := json.Unmarshal([]byte(body), &value); err == nil {
+			return value
+		}
+	}
+
+	switch {
+	case mediaType == "":
+		return body
+	case strings.Contains(mediaType, "json"):
+		var value interface{}
+		if err := json.Unmarshal([]byte(body), &value); err != nil {
+			return body
+		}
+		return value
+	case mediaType == "text/plain":
+		return parsePlainText(body)
+	default:
+		return &types.RawResponse{Value: body, ContentType: contentType}
+	}
+}
+
+// parsePlainText decodes a text/plain body holding a boolean or a number.
+func parsePlainText(body string) interface{} {
+	text := strings.TrimSpace(body)
+	switch text {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+	return body
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        interface{}
+	}{
+		{name: "json", contentType: "application/json", body: `{"value":21.5}`, want: map[string]interface{}{"value": 21.5}},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", body: `[1]`, want: []interface{}{1.0}},
+		{name: "malformed json", contentType: "application/json", body: `{"value":`, want: `{"value":`},
+		{name: "text true", contentType: "text/plain", body: "true", want: true},
+		{name: "text false", contentType: "text/plain; charset=utf-8", body: "false\n", want: false},
+		{name: "text number", contentType: "text/plain", body: " 1.5 ", want: 1.5},
+		{name: "text string", contentType: "text/plain", body: "on", want: "on"},
+		{name: "no content type", contentType: "", body: "raw", want: "raw"},
+		{
+			name:        "octet stream",
+			contentType: "application/octet-stream",
+			body:        "\x01\x02",
+			want:        &types.RawResponse{Value: "\x01\x02", ContentType: "application/octet-stream"},
+		},
+		{
+			name:        "unknown",
+			contentType: "application/x-nefit",
+			body:        "blob",
+			want:        &types.RawResponse{Value: "blob", ContentType: "application/x-nefit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeBody(tt.contentType, tt.body, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeBody(%q, %q) = %#v, want %#v", tt.contentType, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBodySniffJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        interface{}
+	}{
+		{name: "no content type", contentType: "", body: `{"value":21.5}`, want: map[string]interface{}{"value": 21.5}},
+		{name: "octet stream", contentType: "application/octet-stream", body: `[1]`, want: []interface{}{1.0}},
+		{name: "not json", contentType: "", body: "raw", want: "raw"},
+		{name: "text plain untouched", contentType: "text/plain", body: `{"a":1}`, want: `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeBody(tt.contentType, tt.body, true); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeBody(%q, %q) = %#v, want %#v", tt.contentType, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSniffsJSONWithoutContentType(t *testing.T) {
+	c, d := newFakeDevice(t)
+	noType := fakeResponse{StatusCode: 200, Status: "OK", Headers: map[string]string{"Content-Type": ""}, Body: `{"value":"on"}`}
+
+	d.queue("/untyped", noType)
+	if value, err := c.Get(t.Context(), "/untyped"); err != nil || value != `{"value":"on"}` {
+		t.Fatalf("Expected the raw string by default, got %#v (err %v)", value, err)
+	}
+
+	c.config.SniffJSON = true
+	d.queue("/untyped", noType)
+	value, err := c.Get(t.Context(), "/untyped")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !reflect.DeepEqual(value, map[string]interface{}{"value": "on"}) {
+		t.Errorf("Expected the JSON body decoded, got %#v", value)
+	}
+}
+
+func TestGetWithContentType(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.queue("/plain", fakeResponse{StatusCode: 200, Status: "OK", Headers: map[string]string{"Content-Type": "text/plain"}, Body: "true"})
+
+	value, contentType, err := c.GetWithContentType(t.Context(), "/plain")
+	if err != nil {
+		t.Fatalf("GetWithContentType failed: %v", err)
+	}
+	if value != true || contentType != "text/plain" {
+		t.Errorf("Expected true with text/plain, got %#v with %q", value, contentType)
+	}
+}
+
+func TestGetBytesPreservesNulls(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	// A full block ending in nulls, followed by one more that the padding fills up to a
+	// block of nulls. The normal path strips every trailing null; padding never fills a
+	// whole block, so GetBytes keeps the first null of the final block.
+	payload := "\x01\x00\x02binary\x00\x00\x00\x00\x00\x00\x00" + "\x00"
+	encrypted, err := d.enc.Encrypt(payload)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	// As on the device, Content-Length is the length of the base64 ciphertext.
+	headers := map[string]string{"Content-Type": "application/octet-stream", "Content-Length": strconv.Itoa(len(encrypted))}
+	d.queue("/binary", fakeResponse{StatusCode: 200, Status: "OK", Headers: headers, Encrypted: encrypted})
+	d.queue("/binary", fakeResponse{StatusCode: 200, Status: "OK", Headers: headers, Encrypted: encrypted})
+
+	data, err := c.Get(t.Context(), "/binary")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if raw, ok := data.(*types.RawResponse); !ok || raw.Value != "\x01\x00\x02binary" {
+		t.Fatalf("Expected Get to lose the trailing nulls, got %#v", data)
+	}
+
+	body, resp, err := c.GetBytes(t.Context(), "/binary")
+	if err != nil {
+		t.Fatalf("GetBytes failed: %v", err)
+	}
+	if string(body) != payload {
+		t.Errorf("GetBytes = %q, want %q", body, payload)
+	}
+	if resp.ContentType != "application/octet-stream" {
+		t.Errorf("Expected the response to be returned, got content type %q", resp.ContentType)
+	}
+}
+
+func TestGetBytesShortPayload(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set("/text", "a\x00b")
+
+	body, _, err := c.GetBytes(t.Context(), "/text")
+	if err != nil {
+		t.Fatalf("GetBytes failed: %v", err)
+	}
+	if string(body) != "a\x00b" {
+		t.Errorf("GetBytes = %q, want embedded null kept and padding removed", body)
+	}
+}
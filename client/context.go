@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying a correlation ID that is attached as
+// "request_id" to every log line emitted for requests made with it.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the correlation ID set by WithRequestID, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ensureRequestID returns ctx unchanged if it carries a request ID, or a child context with a new one.
+// Top-level operations call it so that all their sub-requests share one ID.
+func ensureRequestID(ctx context.Context) context.Context {
+	if _, ok := RequestIDFromContext(ctx); ok {
+		return ctx
+	}
+	return WithRequestID(ctx, newRequestID())
+}
+
+type circuitKey struct{}
+
+// WithCircuit returns a context that directs heating circuit operations (SetTemperature,
+// SetUserMode, presets, supply temperatures, away mode) made with it to the given circuit,
+// e.g. "hc2". Without it they use types.DefaultCircuit.
+func WithCircuit(ctx context.Context, circuit string) context.Context {
+	return context.WithValue(ctx, circuitKey{}, circuit)
+}
+
+// CircuitFromContext returns the heating circuit set by WithCircuit, or types.DefaultCircuit.
+func CircuitFromContext(ctx context.Context) string {
+	if circuit, ok := ctx.Value(circuitKey{}).(string); ok && circuit != "" {
+		return circuit
+	}
+	return types.DefaultCircuit
+}
+
+// circuitURI returns a heating circuit URI constant for the circuit selected in ctx.
+func circuitURI(ctx context.Context, uri string) string {
+	return types.CircuitURI(uri, CircuitFromContext(ctx))
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// log returns the client logger tagged with the request ID from ctx, if present.
+func (c *Client) log(ctx context.Context) *slog.Logger {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return c.logger.Load().With("request_id", id)
+	}
+	return c.logger.Load()
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent log writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// entries decodes the JSON log lines written so far.
+func (b *syncBuffer) entries(t *testing.T) []map[string]interface{} {
+	t.Helper()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(b.buf.Bytes()))
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", scanner.Text(), err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func captureLogs(c *Client) *syncBuffer {
+	buf := &syncBuffer{}
+	c.SetLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	return buf
+}
+
+func TestSetTemperatureSharesRequestID(t *testing.T) {
+	c, _ := newFakeDevice(t)
+	logs := captureLogs(c)
+
+	if err := c.SetTemperature(t.Context(), 21.5); err != nil {
+		t.Fatalf("SetTemperature failed: %v", err)
+	}
+
+	ids := map[interface{}]int{}
+	sends := 0
+	for _, entry := range logs.entries(t) {
+		if entry["msg"] != "sending PUT request" {
+			continue
+		}
+		sends++
+		ids[entry["request_id"]]++
+	}
+
+	if sends != 3 {
+		t.Fatalf("Expected 3 PUT log entries, got %d", sends)
+	}
+	if len(ids) != 1 {
+		t.Errorf("Expected a single request ID across PUTs, got %v", ids)
+	}
+	for id := range ids {
+		if id == nil || id == "" {
+			t.Error("Expected a generated request ID")
+		}
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	c, _ := newFakeDevice(t)
+	logs := captureLogs(c)
+
+	ctx := WithRequestID(t.Context(), "trace-123")
+	if err := c.Put(ctx, "/test", map[string]int{"value": 1}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	found := false
+	for _, entry := range logs.entries(t) {
+		if entry["msg"] == "sending PUT request" {
+			found = true
+			if entry["request_id"] != "trace-123" {
+				t.Errorf("Expected caller request ID, got %v", entry["request_id"])
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a PUT log entry")
+	}
+}
+
+func TestWithCircuit(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock", "MMT": "20.0"})
+	d.setValue("/heatingCircuits/hc2/usermode", "manual")
+	d.setValue("/heatingCircuits/hc2/temperatureRoomManual", 18.5)
+
+	if got := CircuitFromContext(t.Context()); got != types.DefaultCircuit {
+		t.Errorf("Expected default circuit, got %q", got)
+	}
+
+	ctx := WithCircuit(t.Context(), "hc2")
+	if err := c.SetTemperature(ctx, 19); err != nil {
+		t.Fatalf("SetTemperature failed: %v", err)
+	}
+	if err := c.SetUserMode(ctx, "clock"); err != nil {
+		t.Fatalf("SetUserMode failed: %v", err)
+	}
+
+	var uris []string
+	for _, r := range d.requestsFor("PUT") {
+		uris = append(uris, r.URI)
+	}
+	want := []string{
+		"/heatingCircuits/hc2/temperatureRoomManual",
+		"/heatingCircuits/hc2/manualTempOverride/status",
+		"/heatingCircuits/hc2/manualTempOverride/temperature",
+		"/heatingCircuits/hc2/usermode",
+	}
+	if strings.Join(uris, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected PUTs to %v, got %v", want, uris)
+	}
+
+	status, err := c.Status(ctx, false)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.UserMode != "clock" || status.TempManualSetpoint != 19 {
+		t.Errorf("Expected hc2 mode and setpoint, got %q / %v", status.UserMode, status.TempManualSetpoint)
+	}
+}
+
+func TestLogsCarryDeviceIdentity(t *testing.T) {
+	for _, redact := range []bool{false, true} {
+		c, d := newFakeDevice(t)
+		c.config.RedactSerial = redact
+		c.config.LogAttrs = []slog.Attr{slog.String("device", "living room")}
+		logs := captureLogs(c)
+
+		d.setValue("/test", 1.0)
+		if _, err := c.Get(t.Context(), "/test"); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+
+		wantSerial := "123456789"
+		if redact {
+			wantSerial = c.logSerial()
+			if !strings.HasPrefix(wantSerial, "hmac:") || strings.Contains(wantSerial, "123456789") {
+				t.Fatalf("Expected a keyed hash of the serial, got %q", wantSerial)
+			}
+			// Without the password, hashing the serial does not reproduce the logged value.
+			sum := sha256.Sum256([]byte("123456789"))
+			if strings.Contains(wantSerial, hex.EncodeToString(sum[:6])) {
+				t.Fatalf("Redacted serial %q is an unkeyed hash", wantSerial)
+			}
+		}
+
+		entries := logs.entries(t)
+		if len(entries) == 0 {
+			t.Fatal("Expected log entries")
+		}
+		for _, entry := range entries {
+			if entry["serial"] != wantSerial || entry["device"] != "living room" {
+				t.Errorf("redact=%v: entry %q lacks the identity: serial=%v device=%v",
+					redact, entry["msg"], entry["serial"], entry["device"])
+			}
+			if line, _ := json.Marshal(entry); redact && strings.Contains(string(line), "123456789") {
+				t.Errorf("Redacted entry leaks the serial: %s", line)
+			}
+		}
+	}
+}
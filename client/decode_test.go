@@ -0,0 +1,138 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/internal/fixtures"
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestDecodeStatusFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    types.Status
+	}{
+		{
+			fixture: "uistatus_clock",
+			want: types.Status{
+				UserMode:                 "clock",
+				ClockProgram:             "auto",
+				InHouseStatus:            "ok",
+				InHouseTemp:              20.85,
+				HotWaterActive:           true,
+				BoilerIndicator:          "central heating",
+				Control:                  "room",
+				CurrentSwitchpoint:       32,
+				TempSetpoint:             21.0,
+				TempOverrideTempSetpoint: 21.0,
+				TempManualSetpoint:       19.5,
+			},
+		},
+		{
+			fixture: "uistatus_manual",
+			want: types.Status{
+				UserMode:                 "manual",
+				ClockProgram:             "auto",
+				InHouseStatus:            "ok",
+				InHouseTemp:              17.4,
+				BoilerIndicator:          "hot water",
+				Control:                  "room",
+				TempOverrideDuration:     120,
+				CurrentSwitchpoint:       45,
+				PSActive:                 true,
+				PowersaveMode:            true,
+				FPActive:                 true,
+				FireplaceMode:            true,
+				TempOverride:             true,
+				HolidayMode:              true,
+				BoilerBlock:              true,
+				BoilerLock:               true,
+				BoilerMaintenance:        true,
+				TempSetpoint:             18.0,
+				TempOverrideTempSetpoint: 18.0,
+				TempManualSetpoint:       18.0,
+				HEDEnabled:               true,
+				HEDDeviceAtHome:          true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			value, err := fixtures.Value(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := types.ParseStatus(value)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseStatus(%s) =\n%+v\nwant\n%+v", tt.fixture, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePressureFixture(t *testing.T) {
+	doc, err := fixtures.Load("pressure")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := types.Pressure{Pressure: 1.6, Unit: "bar", MinValue: 0, MaxValue: 25}
+	if got := types.ParsePressure(doc); *got != want {
+		t.Errorf("ParsePressure = %+v, want %+v", *got, want)
+	}
+}
+
+func TestDecodeProgramFixture(t *testing.T) {
+	doc, err := fixtures.Load("program1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decodeProgram(doc, true)
+	if err != nil {
+		t.Fatalf("decodeProgram failed: %v", err)
+	}
+
+	want := &types.Program{
+		Active: true,
+		Switchpoints: []types.ProgramSwitchpoint{
+			{DayOfWeek: 1, Time: "06:30", Temperature: 21},
+			{DayOfWeek: 1, Time: "22:30", Temperature: 16},
+			{DayOfWeek: 6, Time: "08:00", Temperature: 21.5},
+			{DayOfWeek: 0, Time: "00:00", Temperature: 15},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeProgram =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDecodeGasUsageFixture(t *testing.T) {
+	doc, err := fixtures.Load("gasusage_page1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := types.ParseRecordingPage(doc)
+	if err != nil {
+		t.Fatalf("ParseRecordingPage failed: %v", err)
+	}
+
+	want := []types.Recording{
+		{
+			Date:   time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC),
+			Values: map[string]float64{"hw": 0.8, "ch": 12.4, "T": 3.5},
+		},
+		{
+			Date:   time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC),
+			Values: map[string]float64{"hw": 1.2, "ch": 14.1, "T": -0.5},
+		},
+	}
+	if !reflect.DeepEqual(page.Entries, want) {
+		t.Errorf("ParseRecordingPage entries =\n%+v\nwant\n%+v", page.Entries, want)
+	}
+}
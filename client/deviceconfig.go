@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// FieldError is a setting of a DeviceConfig that ImportConfig failed to write.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// ImportError is returned by ImportConfig when some settings could not be written.
+// The settings not listed were written.
+type ImportError struct {
+	Failures []FieldError
+}
+
+func (e *ImportError) Error() string {
+	parts := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		parts = append(parts, fmt.Sprintf("%s: %v", f.Field, f.Err))
+	}
+	return fmt.Sprintf("failed to import %d settings: %s", len(e.Failures), strings.Join(parts, "; "))
+}
+
+func (e *ImportError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
+
+// ExportConfig reads the writable settings of the device: user mode, hot water supply,
+// outdoor temperature source, presets, both programs and which is active, display and
+// location. Settings the appliance does not provide are left empty; any other read
+// failure aborts the export. User mode and presets are those of the circuit in ctx.
+func (c *Client) ExportConfig(ctx context.Context) (*types.DeviceConfig, error) {
+	ctx = ensureRequestID(ctx)
+	cfg := &types.DeviceConfig{}
+
+	// optional runs read and drops its error if the setting is not provided.
+	optional := func(name string, read func() error) error {
+		if err := read(); err != nil {
+			if isUnsupported(err) {
+				c.log(ctx).Debug("setting not available, not exported", "setting", name, "error", err)
+				return nil
+			}
+			return fmt.Errorf("failed to export %s: %w", name, err)
+		}
+		return nil
+	}
+
+	steps := []struct {
+		name string
+		read func() error
+	}{
+		{"user_mode", func() error {
+			mode, err := c.getStringValue(ctx, circuitURI(ctx, types.URIUserMode))
+			cfg.UserMode = types.UserMode(mode)
+			return err
+		}},
+		{"hot_water_supply", func() error {
+			on, err := c.HotWaterSupply(ctx)
+			if err == nil {
+				cfg.HotWaterSupply = &on
+			}
+			return err
+		}},
+		{"outdoor_source", func() error {
+			source, err := c.getStringValue(ctx, types.URIOutdoorSource)
+			cfg.OutdoorSource = source
+			return err
+		}},
+		{"presets", func() error {
+			var presets types.Presets
+			for _, p := range []struct {
+				uri    string
+				target *float64
+			}{
+				{types.URIPresetComfort, &presets.Comfort},
+				{types.URIPresetEco, &presets.Eco},
+				{types.URIManualSetpoint, &presets.Manual},
+			} {
+				v, err := c.getFloatValue(ctx, circuitURI(ctx, p.uri))
+				if err != nil {
+					return err
+				}
+				*p.target = v
+			}
+			cfg.Presets = &presets
+			return nil
+		}},
+		{"programs", func() error {
+			active, err := c.ActiveProgram(ctx)
+			if err != nil {
+				return err
+			}
+			cfg.ActiveProgram = active
+			for program, target := range []**types.Program{&cfg.Program1, &cfg.Program2} {
+				p, err := c.readProgram(ctx, program+1, active == program+1)
+				if err != nil {
+					return err
+				}
+				*target = p
+			}
+			return nil
+		}},
+		{"display", func() error {
+			display, err := c.GetDisplaySettings(ctx)
+			cfg.Display = display
+			return err
+		}},
+		{"location", func() error {
+			lat, err := c.getFloatValue(ctx, types.URILocationLatitude)
+			if err != nil {
+				return err
+			}
+			lon, err := c.getFloatValue(ctx, types.URILocationLongitude)
+			if err != nil {
+				return err
+			}
+			cfg.Location = &types.Location{Latitude: lat, Longitude: lon}
+			return nil
+		}},
+	}
+
+	for _, step := range steps {
+		if err := optional(step.name, step.read); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// ImportConfig writes the settings of cfg back to the device, skipping empty fields and
+// read-only ones such as Program.Active. Settings are written so that no invalid state
+// is passed through: the programs and presets before the program is activated and the
+// user mode switched, and the hot water supply last because its endpoint depends on the
+// user mode. A failed setting does not stop the others, except that a program whose
+// upload failed is not activated; failures are returned as an *ImportError.
+func (c *Client) ImportConfig(ctx context.Context, cfg *types.DeviceConfig) error {
+	ctx = ensureRequestID(ctx)
+
+	var failures []FieldError
+	write := func(field string, fn func() error) bool {
+		if err := fn(); err != nil {
+			c.log(ctx).Warn("failed to import setting", "setting", field, "error", err)
+			failures = append(failures, FieldError{Field: field, Err: err})
+			return false
+		}
+		return true
+	}
+
+	if cfg.Location != nil {
+		write("location.latitude", func() error {
+			return c.Put(ctx, types.URILocationLatitude, map[string]interface{}{"value": cfg.Location.Latitude})
+		})
+		write("location.longitude", func() error {
+			return c.Put(ctx, types.URILocationLongitude, map[string]interface{}{"value": cfg.Location.Longitude})
+		})
+	}
+	if cfg.Display != nil {
+		write("display.brightness", func() error { return c.SetDisplayBrightness(ctx, cfg.Display.Brightness) })
+		write("display.standby", func() error { return c.SetDisplayStandby(ctx, cfg.Display.Standby) })
+	}
+	if cfg.OutdoorSource != "" {
+		write("outdoor_source", func() error { return c.SetOutdoorSource(ctx, cfg.OutdoorSource) })
+	}
+	if cfg.Presets != nil {
+		for _, p := range []struct {
+			name  string
+			value float64
+		}{
+			{types.PresetComfort, cfg.Presets.Comfort},
+			{types.PresetEco, cfg.Presets.Eco},
+			{types.PresetManual, cfg.Presets.Manual},
+		} {
+			write("presets."+p.name, func() error { return c.setPresetCelsius(ctx, p.name, p.value) })
+		}
+	}
+
+	uploaded := map[int]bool{}
+	for i, p := range []*types.Program{cfg.Program1, cfg.Program2} {
+		if p == nil {
+			continue
+		}
+		program := i + 1
+		uploaded[program] = write(fmt.Sprintf("program%d", program), func() error { return c.setProgramCelsius(ctx, program, p) })
+	}
+	if cfg.ActiveProgram != 0 {
+		if ok, tried := uploaded[cfg.ActiveProgram]; tried && !ok {
+			failures = append(failures, FieldError{
+				Field: "active_program",
+				Err:   fmt.Errorf("not activated because program %d failed to upload", cfg.ActiveProgram),
+			})
+		} else {
+			write("active_program", func() error { return c.ActivateProgram(ctx, cfg.ActiveProgram) })
+		}
+	}
+
+	if cfg.UserMode != "" {
+		write("user_mode", func() error { return c.SetUserMode(ctx, cfg.UserMode) })
+	}
+	if cfg.HotWaterSupply != nil {
+		write("hot_water_supply", func() error { return c.SetHotWaterSupply(ctx, *cfg.HotWaterSupply) })
+	}
+
+	if len(failures) > 0 {
+		return &ImportError{Failures: failures}
+	}
+	return nil
+}
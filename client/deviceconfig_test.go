@@ -0,0 +1,156 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// seedDeviceConfig stores a complete set of settings on d.
+func seedDeviceConfig(d *fakeDevice) {
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock"})
+	d.setValue(types.URIUserMode, "clock")
+	d.setValue(types.URIHotWaterClockMode, "on")
+	d.setValue(types.URIHotWaterManualMode, "off")
+	d.setValue(types.URIOutdoorSource, types.OutdoorSourceVirtual)
+	d.setValue(types.URIPresetComfort, 21.0)
+	d.setValue(types.URIPresetEco, 16.5)
+	d.setValue(types.URIManualSetpoint, 19.0)
+	d.setValue(types.URIActiveProgram, 2)
+	d.setValue(types.URIProgram1, []interface{}{
+		map[string]interface{}{"d": "Mo", "t": 390, "T": 21},
+		map[string]interface{}{"d": "Mo", "t": 1350, "T": 16},
+	})
+	d.setValue(types.URIProgram2, []interface{}{
+		map[string]interface{}{"d": "Sa", "t": 480, "T": 21.5},
+	})
+	d.setValue(types.URIDisplayBrightness, 3)
+	d.setValue(types.URIDisplayStandby, "on")
+	d.setValue(types.URILocationLatitude, 52.1)
+	d.setValue(types.URILocationLongitude, 5.2)
+}
+
+func TestExportImportConfigRoundTrip(t *testing.T) {
+	c, d := newFakeDevice(t)
+	seedDeviceConfig(d)
+
+	exported, err := c.ExportConfig(t.Context())
+	if err != nil {
+		t.Fatalf("ExportConfig failed: %v", err)
+	}
+	if exported.ActiveProgram != 2 || !exported.Program2.Active || exported.Program1.Active {
+		t.Errorf("Expected program 2 to be exported as active, got %+v", exported)
+	}
+	if exported.Presets.Eco != 16.5 || exported.Location.Latitude != 52.1 || !*exported.HotWaterSupply {
+		t.Errorf("Unexpected export %+v", exported)
+	}
+
+	// Restore onto a device whose settings all differ.
+	c2, d2 := newFakeDevice(t)
+	d2.setValue(types.URIStatus, map[string]interface{}{"UMD": "manual"})
+	d2.setValue(types.URIUserMode, "manual")
+	d2.setValue(types.URIHotWaterClockMode, "off")
+	d2.setValue(types.URIHotWaterManualMode, "off")
+	d2.setValue(types.URIOutdoorSource, types.OutdoorSourcePhysical)
+	d2.setValue(types.URIPresetComfort, 20.0)
+	d2.setValue(types.URIPresetEco, 15.0)
+	d2.setValue(types.URIManualSetpoint, 22.0)
+	d2.setValue(types.URIActiveProgram, 1)
+	d2.setValue(types.URIProgram1, []interface{}{})
+	d2.setValue(types.URIProgram2, []interface{}{})
+	d2.setValue(types.URIDisplayBrightness, 1)
+	d2.setValue(types.URIDisplayStandby, "off")
+	d2.setValue(types.URILocationLatitude, 0.0)
+	d2.setValue(types.URILocationLongitude, 0.0)
+
+	if err := c2.ImportConfig(t.Context(), exported); err != nil {
+		t.Fatalf("ImportConfig failed: %v", err)
+	}
+
+	// The user mode is switched after the programs are in place, and hot water follows it.
+	var order []string
+	for _, r := range d2.requestsFor("PUT") {
+		order = append(order, r.URI)
+	}
+	index := func(uri string) int {
+		for i, u := range order {
+			if u == uri {
+				return i
+			}
+		}
+		t.Fatalf("No PUT to %s in %v", uri, order)
+		return -1
+	}
+	if !(index(types.URIProgram2) < index(types.URIActiveProgram) &&
+		index(types.URIActiveProgram) < index(types.URIUserMode) &&
+		index(types.URIUserMode) < index(types.URIHotWaterClockMode)) {
+		t.Errorf("Unexpected write order %v", order)
+	}
+
+	restored, err := c2.ExportConfig(t.Context())
+	if err != nil {
+		t.Fatalf("ExportConfig after import failed: %v", err)
+	}
+	if !reflect.DeepEqual(restored, exported) {
+		t.Errorf("Round trip mismatch:\n%+v\nwant\n%+v", restored, exported)
+	}
+}
+
+func TestExportConfigSkipsUnsupported(t *testing.T) {
+	c, d := newFakeDevice(t)
+	seedDeviceConfig(d)
+	d.queue(types.URILocationLatitude, fakeResponse{StatusCode: 404, Status: "Not Found"})
+
+	cfg, err := c.ExportConfig(t.Context())
+	if err != nil {
+		t.Fatalf("ExportConfig failed: %v", err)
+	}
+	if cfg.Location != nil {
+		t.Errorf("Expected no location from an appliance without one, got %+v", cfg.Location)
+	}
+	if cfg.Display == nil {
+		t.Error("Expected the other settings to be exported")
+	}
+}
+
+func TestImportConfigReportsFieldFailures(t *testing.T) {
+	c, d := newFakeDevice(t)
+	seedDeviceConfig(d)
+
+	cfg, err := c.ExportConfig(t.Context())
+	if err != nil {
+		t.Fatalf("ExportConfig failed: %v", err)
+	}
+
+	d.queue(types.URIDisplayBrightness, fakeResponse{StatusCode: 400, Status: "Bad Request"})
+	d.queue(types.URIProgram2, fakeResponse{StatusCode: 400, Status: "Bad Request"})
+	cfg.Display.Brightness = 4
+
+	err = c.ImportConfig(t.Context(), cfg)
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("Expected an ImportError, got %v", err)
+	}
+
+	var fields []string
+	for _, f := range importErr.Failures {
+		fields = append(fields, f.Field)
+	}
+	want := []string{"display.brightness", "program2", "active_program"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Failed fields = %v, want %v", fields, want)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Errorf("Expected the API error to be reachable with errors.As, got %v", err)
+	}
+
+	for _, r := range d.requestsFor("PUT") {
+		if r.URI == types.URIActiveProgram {
+			t.Error("Program 2 was activated although its upload failed")
+		}
+	}
+}
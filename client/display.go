@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// GetDisplaySettings retrieves the display brightness and standby behaviour.
+func (c *Client) GetDisplaySettings(ctx context.Context) (*types.DisplaySettings, error) {
+	ctx = ensureRequestID(ctx)
+
+	brightness, err := c.getFloatValue(ctx, types.URIDisplayBrightness)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get display brightness: %w", err)
+	}
+
+	data, err := c.Get(ctx, types.URIDisplayStandby)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get display standby: %w", err)
+	}
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected display standby response type: %T", data)
+	}
+
+	standby, _ := types.LookupString(dataMap, "value")
+	return &types.DisplaySettings{
+		Brightness: int(brightness),
+		Standby:    standby == "on",
+	}, nil
+}
+
+// SetDisplayBrightness sets the display brightness, which must lie within
+// types.MinBrightness and types.MaxBrightness.
+func (c *Client) SetDisplayBrightness(ctx context.Context, level int) error {
+	if level < types.MinBrightness || level > types.MaxBrightness {
+		return fmt.Errorf("brightness %d out of range %d-%d", level, types.MinBrightness, types.MaxBrightness)
+	}
+
+	if err := c.Put(ctx, types.URIDisplayBrightness, map[string]interface{}{"value": level}); err != nil {
+		return fmt.Errorf("failed to set display brightness: %w", err)
+	}
+
+	return nil
+}
+
+// SetDisplayStandby enables or disables dimming the display when idle.
+func (c *Client) SetDisplayStandby(ctx context.Context, enabled bool) error {
+	value := "off"
+	if enabled {
+		value = "on"
+	}
+
+	if err := c.Put(ctx, types.URIDisplayStandby, map[string]string{"value": value}); err != nil {
+		return fmt.Errorf("failed to set display standby: %w", err)
+	}
+
+	return nil
+}
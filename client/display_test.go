@@ -0,0 +1,51 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestSetDisplayBrightnessRange(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	for _, level := range []int{types.MinBrightness - 1, types.MaxBrightness + 1, -5} {
+		if err := c.SetDisplayBrightness(t.Context(), level); err == nil {
+			t.Errorf("Expected error for brightness %d", level)
+		}
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 0 {
+		t.Fatalf("Expected no PUT for out-of-range levels, got %+v", puts)
+	}
+
+	for _, level := range []int{types.MinBrightness, types.MaxBrightness} {
+		if err := c.SetDisplayBrightness(t.Context(), level); err != nil {
+			t.Errorf("SetDisplayBrightness(%d) failed: %v", level, err)
+		}
+	}
+	puts := d.requestsFor("PUT")
+	if len(puts) != 2 || puts[0].URI != types.URIDisplayBrightness || puts[1].Body != `{"value":10}` {
+		t.Errorf("Unexpected PUTs: %+v", puts)
+	}
+}
+
+func TestDisplaySettings(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIDisplayBrightness, 7.0)
+	d.setValue(types.URIDisplayStandby, "off")
+
+	if err := c.SetDisplayStandby(t.Context(), true); err != nil {
+		t.Fatalf("SetDisplayStandby failed: %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 1 || puts[0].URI != types.URIDisplayStandby || puts[0].Body != `{"value":"on"}` {
+		t.Errorf("Unexpected PUT: %+v", puts)
+	}
+
+	settings, err := c.GetDisplaySettings(t.Context())
+	if err != nil {
+		t.Fatalf("GetDisplaySettings failed: %v", err)
+	}
+	if settings.Brightness != 7 || !settings.Standby {
+		t.Errorf("Unexpected display settings: %+v", settings)
+	}
+}
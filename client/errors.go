@@ -0,0 +1,293 @@
+package client
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	xmpp "github.com/xmppo/go-xmpp"
+
+	"github.com/kradalby/nefit-go/crypto"
+	"github.com/kradalby/nefit-go/protocol"
+)
+
+// ErrNotAvailable is returned when the device reports a reading as not available,
+// e.g. a disconnected sensor or a value the appliance does not provide.
+var ErrNotAvailable = errors.New("value not available")
+
+// ErrNoResettableFault is returned by ResetBoilerFault when no resettable fault is active.
+var ErrNoResettableFault = errors.New("no resettable boiler fault active")
+
+// ErrUnsupported is returned when the appliance does not provide the requested resource.
+var ErrUnsupported = errors.New("not supported by this appliance")
+
+// ErrNotAway is returned by ClearAway when no saved away state exists.
+var ErrNotAway = errors.New("not in away mode")
+
+// ErrNotClockMode is returned by AdjustCurrentSwitchpoint when the heating is not in clock mode.
+var ErrNotClockMode = errors.New("not in clock mode")
+
+// ErrReconnecting is returned to requests that were waiting for a response when Reconnect replaced the connection.
+var ErrReconnecting = errors.New("connection replaced by reconnect")
+
+// ErrAlreadyConnected is returned by Connect when the client already has a connection.
+var ErrAlreadyConnected = errors.New("already connected")
+
+// ErrPayloadTooLarge is returned (wrapped, with the sizes) by Put when the encrypted body
+// exceeds MaxPayloadSize. The protocol has no way to split a body across messages.
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// MaxPayloadSize is the largest encrypted (base64) PUT body Put sends, in bytes. It stays
+// below the 64 KiB stanza limit XMPP servers commonly enforce, leaving room for the
+// HTTP-over-XMPP envelope; larger stanzas are dropped by the server without a reply.
+const MaxPayloadSize = 60 * 1024
+
+// ErrClientClosed is returned when a closed client is reconnected, or to requests interrupted by Close.
+var ErrClientClosed = errors.New("client closed")
+
+// ErrRetryBudgetExhausted is returned when a request is not attempted (again) because
+// the RetryBudget of its context is used up.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// ErrReceiveFailed is reported (wrapped) on the Errors channel when reading from the
+// connection fails, e.g. because the backend closed it. Callers typically respond with Reconnect.
+var ErrReceiveFailed = errors.New("receive failed")
+
+// ErrConnectionDead is reported on the Errors channel when no presence has been received
+// from the backend for Config.PingTimeout. Callers typically respond with Reconnect.
+var ErrConnectionDead = errors.New("connection dead: keepalive pings not answered")
+
+// Connection failure classes returned (wrapped) by Connect. ErrAuthFailed and
+// ErrServerUnavailable also classify XMPP error stanzas answering a request; see XMPPError.
+var (
+	// ErrAuthFailed means the backend rejected the credentials; check the access key and password.
+	ErrAuthFailed = errors.New("authentication failed")
+	// ErrNetwork means the backend could not be reached or the TLS handshake failed.
+	ErrNetwork = errors.New("network error")
+	// ErrServerUnavailable means the backend was reached but refused or dropped the session.
+	ErrServerUnavailable = errors.New("server unavailable")
+	// ErrRemoteTimeout means the backend gave up waiting for the appliance to answer a request.
+	ErrRemoteTimeout = errors.New("remote server timeout")
+)
+
+// XMPPError is returned when the backend answers a request with an XMPP error stanza
+// instead of an HTTP response. It unwraps to ErrServerUnavailable, ErrAuthFailed or
+// ErrRemoteTimeout for the service-unavailable, not-authorized and remote-server-timeout
+// conditions, so callers can test with errors.Is.
+type XMPPError struct {
+	// Condition is the defined condition of the stanza, e.g. "service-unavailable".
+	Condition string
+	// Text is the optional human-readable text of the stanza.
+	Text string
+	// Method and URI identify the request echoed by the backend, when present.
+	Method string
+	URI    string
+}
+
+func (e *XMPPError) Error() string {
+	msg := "XMPP error"
+	if e.Condition != "" {
+		msg += " " + e.Condition
+	}
+	if e.Method != "" {
+		msg += fmt.Sprintf(" for %s %s", e.Method, e.URI)
+	}
+	if e.Text != "" {
+		msg += ": " + e.Text
+	}
+	return msg
+}
+
+func (e *XMPPError) Unwrap() error {
+	switch e.Condition {
+	case "service-unavailable":
+		return ErrServerUnavailable
+	case "not-authorized":
+		return ErrAuthFailed
+	case "remote-server-timeout":
+		return ErrRemoteTimeout
+	}
+	return nil
+}
+
+// stanzaNS is the namespace of the defined conditions and text of an XMPP error stanza.
+const stanzaNS = "urn:ietf:params:xml:ns:xmpp-stanzas"
+
+// parseErrorStanza builds an XMPPError from an error chat. The backend echoes the
+// original request as the message body, which identifies the request that failed.
+func parseErrorStanza(msg xmpp.Chat) *XMPPError {
+	xe := &XMPPError{}
+
+	for _, elem := range msg.OtherElem {
+		if elem.XMLName.Local != "error" {
+			continue
+		}
+		var stanza struct {
+			Children []struct {
+				XMLName xml.Name
+				Text    string `xml:",chardata"`
+			} `xml:",any"`
+		}
+		if err := xml.Unmarshal([]byte("<error>"+elem.InnerXML+"</error>"), &stanza); err != nil {
+			continue
+		}
+		for _, child := range stanza.Children {
+			if child.XMLName.Space != "" && child.XMLName.Space != stanzaNS {
+				continue
+			}
+			if child.XMLName.Local == "text" {
+				xe.Text = strings.TrimSpace(child.Text)
+			} else if xe.Condition == "" {
+				xe.Condition = child.XMLName.Local
+			}
+		}
+	}
+
+	line, _, _ := strings.Cut(strings.TrimLeft(msg.Text, "\r\n "), "\r")
+	line, _, _ = strings.Cut(line, "\n")
+	if fields := strings.Fields(line); len(fields) >= 2 && strings.HasPrefix(strings.ToUpper(fields[len(fields)-1]), "HTTP/") {
+		xe.Method = strings.ToUpper(fields[0])
+		xe.URI = fields[1]
+	} else if xe.Text == "" {
+		xe.Text = strings.TrimSpace(msg.Text)
+	}
+
+	return xe
+}
+
+// APIError is returned when the backend answers a request with a non-success HTTP status.
+type APIError struct {
+	StatusCode int
+	Status     string
+	// Message is the response body explaining the failure, decrypted when possible.
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("HTTP error %d: %s: %s", e.StatusCode, e.Status, e.Message)
+	}
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Status)
+}
+
+// isUnsupported reports whether err means the appliance does not provide a reading:
+// the value is not available, or the endpoint answers 404 as on older appliances.
+func isUnsupported(err error) bool {
+	var apiErr *APIError
+	return errors.Is(err, ErrNotAvailable) || (errors.As(err, &apiErr) && apiErr.StatusCode == 404)
+}
+
+// newAPIError builds an APIError from a failed response. Error bodies are usually
+// encrypted like regular payloads; bodies that do not decrypt to text are kept raw.
+func newAPIError(enc *crypto.Encryptor, resp *protocol.HTTPResponse) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+
+	body := strings.TrimSpace(resp.Body)
+	if body == "" {
+		return apiErr
+	}
+
+	apiErr.Message = body
+	if decrypted, err := enc.DecryptAndStrip(body); err == nil && isPrintable(decrypted) {
+		apiErr.Message = strings.TrimSpace(decrypted)
+	}
+
+	return apiErr
+}
+
+func isPrintable(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// MismatchError is returned by confirmed writes when the value read back differs from the value written.
+type MismatchError struct {
+	URI  string
+	Want interface{}
+	Got  interface{}
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s: device reports %v after writing %v", e.URI, e.Got, e.Want)
+}
+
+// redirectError signals a redirect response so Get can re-issue the request.
+type redirectError struct {
+	statusCode int
+	location   string
+}
+
+func (e *redirectError) Error() string {
+	return fmt.Sprintf("HTTP redirect %d to %s", e.statusCode, e.location)
+}
+
+// redirectLocation returns the target URI of a 301/302/307 response, or "" if resp is not a redirect.
+// Absolute Location URLs are reduced to their path and query.
+func redirectLocation(resp *protocol.HTTPResponse) string {
+	switch resp.StatusCode {
+	case 301, 302, 307:
+	default:
+		return ""
+	}
+
+	location := resp.Headers["Location"]
+	if location == "" || strings.HasPrefix(location, "/") {
+		return location
+	}
+
+	u, err := url.Parse(location)
+	if err != nil || u.Path == "" {
+		return ""
+	}
+	return u.RequestURI()
+}
+
+// classifyConnectError wraps a go-xmpp connection error with ErrAuthFailed, ErrNetwork or
+// ErrServerUnavailable. go-xmpp mostly returns plain string errors, so besides net.Error
+// the classification matches on the messages it produces. Unknown errors are returned as is.
+func classifyConnectError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	switch {
+	case containsAny(msg, "auth failure", "not-authorized", "scram", "no viable authentication", "unsupported auth mechanism"):
+		return fmt.Errorf("%w: %w", ErrAuthFailed, err)
+	case containsAny(msg, "stream error", "server closed stream", "service-unavailable",
+		"system-shutdown", "remote-server-not-found", "expected <stream>", "eof"):
+		return fmt.Errorf("%w: %w", ErrServerUnavailable, err)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || containsAny(msg, "dial tcp", "no such host", "connection refused",
+		"network is unreachable", "i/o timeout", "tls:", "x509:", "starttls handshake") {
+		return fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+
+	return err
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/kradalby/nefit-go/protocol"
+	"github.com/kradalby/nefit-go/types"
+	xmpp "github.com/xmppo/go-xmpp"
+)
+
+func TestPutErrorIncludesServerMessage(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	d.queue(types.URIManualSetpoint, fakeResponse{
+		StatusCode: 400,
+		Status:     "Bad Request",
+		Body:       `{"error":"value out of range"}`,
+	})
+
+	err := c.Put(t.Context(), types.URIManualSetpoint, map[string]interface{}{"value": 99})
+	if err == nil {
+		t.Fatal("Expected error for 400 response")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != 400 {
+		t.Errorf("Expected status 400, got %d", apiErr.StatusCode)
+	}
+	if !strings.Contains(apiErr.Message, "value out of range") {
+		t.Errorf("Expected decrypted server message, got %q", apiErr.Message)
+	}
+	if !strings.Contains(err.Error(), "value out of range") {
+		t.Errorf("Expected server message in error string, got %q", err.Error())
+	}
+}
+
+func TestAPIErrorFallsBackToRawBody(t *testing.T) {
+	c := newUnconnectedClient(t)
+	defer c.Close() //nolint:errcheck
+
+	apiErr := newAPIError(c.encryptor, &protocol.HTTPResponse{
+		StatusCode: 500,
+		Status:     "Internal Server Error",
+		Body:       "plain failure",
+	})
+
+	if apiErr.Message != "plain failure" {
+		t.Errorf("Expected raw body fallback, got %q", apiErr.Message)
+	}
+}
+
+func TestAPIErrorUnencryptedBase64Body(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	// Plain-text bodies that are also valid base64 but not whole AES blocks.
+	for _, body := range []string{"Unauthorized", "NotFound", "abcd"} {
+		d.queue("/test/value", fakeResponse{StatusCode: 401, Status: "Unauthorized", Encrypted: body})
+
+		_, err := c.Get(t.Context(), "/test/value")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Body %q: expected APIError, got %T: %v", body, err, err)
+		}
+		if apiErr.StatusCode != 401 || apiErr.Message != body {
+			t.Errorf("Body %q: got status %d message %q, want 401 with the raw body", body, apiErr.StatusCode, apiErr.Message)
+		}
+	}
+}
+
+func TestClassifyConnectError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"sasl failure", errors.New("auth failure: not-authorized"), ErrAuthFailed},
+		{"no mechanism", errors.New("no viable authentication method available: [PLAIN]"), ErrAuthFailed},
+		{"dial", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}, ErrNetwork},
+		{"dns", errors.New("dial tcp: lookup wa2-mz36-qrmzh6.bosch.de: no such host"), ErrNetwork},
+		{"tls", errors.New("starttls handshake: x509: certificate signed by unknown authority"), ErrNetwork},
+		{"stream error", errors.New("stream error: system-shutdown"), ErrServerUnavailable},
+		{"closed stream", errors.New("server closed stream"), ErrServerUnavailable},
+		{"eof", io.EOF, ErrServerUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyConnectError(tt.err)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected original error to be wrapped, got %v", err)
+			}
+		})
+	}
+
+	unknown := errors.New("something else")
+	if err := classifyConnectError(unknown); err != unknown {
+		t.Errorf("Expected unknown error unchanged, got %v", err)
+	}
+}
+
+func TestConnectClassifiesDialError(t *testing.T) {
+	c := newUnconnectedClient(t)
+	c.dial = func(options xmpp.Options) (transport, error) {
+		return nil, errors.New("auth failure: not-authorized")
+	}
+
+	err := c.Connect(t.Context())
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Expected ErrAuthFailed from Connect, got %v", err)
+	}
+}
+
+// errorStanza returns the error chat the backend sends for a failed request, echoing its body.
+func errorStanza(body, condition, text string) xmpp.Chat {
+	inner := `<` + condition + ` xmlns="urn:ietf:params:xml:ns:xmpp-stanzas"/>`
+	if text != "" {
+		inner += `<text xmlns="urn:ietf:params:xml:ns:xmpp-stanzas">` + text + `</text>`
+	}
+	return xmpp.Chat{
+		Type: "error",
+		Text: body,
+		OtherElem: []xmpp.XMLElement{{
+			XMLName:  xml.Name{Local: "error"},
+			Attr:     []xml.Attr{{Name: xml.Name{Local: "type"}, Value: "cancel"}},
+			InnerXML: inner,
+		}},
+	}
+}
+
+func TestParseErrorStanza(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  xmpp.Chat
+		want XMPPError
+		is   error
+	}{
+		{
+			name: "service unavailable",
+			msg:  errorStanza("GET /ecus/rrc/uiStatus HTTP/1.1\rUser-Agent: NefitEasy\r\r", "service-unavailable", ""),
+			want: XMPPError{Condition: "service-unavailable", Method: "GET", URI: "/ecus/rrc/uiStatus"},
+			is:   ErrServerUnavailable,
+		},
+		{
+			name: "not authorized with text",
+			msg:  errorStanza("PUT /heatingCircuits/hc1/temperatureRoomManual HTTP/1.1\r\r", "not-authorized", "bad key"),
+			want: XMPPError{Condition: "not-authorized", Text: "bad key", Method: "PUT", URI: "/heatingCircuits/hc1/temperatureRoomManual"},
+			is:   ErrAuthFailed,
+		},
+		{
+			name: "remote timeout",
+			msg:  errorStanza("GET /system/sensors/temperatures/outdoor_t1 HTTP/1.1\r\r", "remote-server-timeout", ""),
+			want: XMPPError{Condition: "remote-server-timeout", Method: "GET", URI: "/system/sensors/temperatures/outdoor_t1"},
+			is:   ErrRemoteTimeout,
+		},
+		{
+			name: "no echoed request",
+			msg:  errorStanza("", "internal-server-error", ""),
+			want: XMPPError{Condition: "internal-server-error"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := parseErrorStanza(tt.msg)
+		if *got != tt.want {
+			t.Errorf("%s: parseErrorStanza = %+v, want %+v", tt.name, *got, tt.want)
+		}
+		if tt.is != nil && !errors.Is(got, tt.is) {
+			t.Errorf("%s: errors.Is(%v, %v) = false", tt.name, got, tt.is)
+		}
+	}
+}
+
+func TestErrorStanzaFailsMatchingRequest(t *testing.T) {
+	ft := newFakeTransport()
+	c := newTestClient(t, ft)
+
+	// A request for another URI waiting at the same time must not be failed.
+	otherCh := make(chan error, 1)
+	c.pendingMu.Lock()
+	c.pendingRequests["get:/other:1"] = make(chan *protocol.HTTPResponse, 1)
+	c.pendingErrors["get:/other:1"] = otherCh
+	c.pendingMu.Unlock()
+
+	ft.onSend = func(chat xmpp.Chat) {
+		ft.recvCh <- errorStanza(chat.Text, "service-unavailable", "device offline")
+	}
+
+	_, err := c.Get(t.Context(), types.URIStatus)
+	if !errors.Is(err, ErrServerUnavailable) {
+		t.Fatalf("Get error = %v, want ErrServerUnavailable", err)
+	}
+	var xe *XMPPError
+	if !errors.As(err, &xe) || xe.URI != types.URIStatus || xe.Text != "device offline" {
+		t.Errorf("Expected an XMPPError for %s, got %#v", types.URIStatus, xe)
+	}
+
+	select {
+	case err := <-otherCh:
+		t.Errorf("Unrelated pending request failed with %v", err)
+	default:
+	}
+}
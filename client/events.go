@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// eventsBuffer is the number of push notifications an Events channel holds before
+// further notifications are dropped.
+const eventsBuffer = 100
+
+// Events returns a channel that delivers push notifications until ctx is cancelled or
+// the client is closed, after which the channel is closed. It is an alternative to
+// Subscribe for consumers that select on several sources.
+//
+// The channel buffers up to 100 notifications. Notifications arriving while it is full
+// are dropped and logged rather than blocking delivery to other handlers. Ordering is
+// not guaranteed, since handlers are dispatched concurrently.
+func (c *Client) Events(ctx context.Context) <-chan PushNotification {
+	events := make(chan PushNotification, eventsBuffer)
+
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+	unsubscribe := c.subscribe(func(uri string, data interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+
+		select {
+		case events <- PushNotification{URI: uri, Data: data}:
+		default:
+			c.logger.Load().Warn("events channel full, dropping push notification", "uri", uri)
+		}
+	})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.ctx.Done():
+		}
+		unsubscribe()
+
+		mu.Lock()
+		closed = true
+		close(events)
+		mu.Unlock()
+	}()
+
+	return events
+}
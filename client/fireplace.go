@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// FireplaceMode reports whether fireplace mode is active and, on firmware with timed
+// fireplace mode, how long it will still run.
+func (c *Client) FireplaceMode(ctx context.Context) (*types.FireplaceState, error) {
+	ctx = ensureRequestID(ctx)
+
+	mode, err := c.getStringValue(ctx, types.URIFireplaceMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fireplace mode: %w", err)
+	}
+	state := &types.FireplaceState{Active: mode == "on"}
+
+	minutes, err := c.getFloatValue(ctx, types.URIFireplaceDuration)
+	switch {
+	case isUnsupported(err):
+		return state, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get fireplace duration: %w", err)
+	}
+
+	state.Timed = true
+	if state.Active {
+		state.Remaining = types.MinutesDuration(minutes)
+	}
+	return state, nil
+}
+
+// SetFireplaceMode turns fireplace mode on or off. A positive duration of at least a
+// minute, rounded up to whole minutes, makes the mode end by itself; it must not exceed
+// the maximum the device reports. Firmware without timed fireplace mode ignores the
+// duration (with a warning) and runs the mode until it is turned off. The duration is
+// ignored when disabling.
+func (c *Client) SetFireplaceMode(ctx context.Context, enabled bool, duration time.Duration) error {
+	ctx = ensureRequestID(ctx)
+
+	if duration < 0 {
+		return fmt.Errorf("fireplace duration %v must not be negative", duration)
+	}
+
+	if enabled && duration > 0 {
+		minutes, err := types.DurationMinutes(duration)
+		if err != nil {
+			return fmt.Errorf("invalid fireplace duration: %w", err)
+		}
+		if err := c.setFireplaceDuration(ctx, minutes); err != nil {
+			return err
+		}
+	}
+
+	value := "off"
+	if enabled {
+		value = "on"
+	}
+	if err := c.Put(ctx, types.URIFireplaceMode, map[string]string{"value": value}); err != nil {
+		return fmt.Errorf("failed to set fireplace mode: %w", err)
+	}
+
+	return nil
+}
+
+// setFireplaceDuration writes the fireplace run time in minutes, or warns and does
+// nothing if the firmware has no duration endpoint.
+func (c *Client) setFireplaceDuration(ctx context.Context, minutes int) error {
+	data, err := c.Get(ctx, types.URIFireplaceDuration)
+	if isUnsupported(err) {
+		c.log(ctx).Warn("timed fireplace mode not supported, ignoring duration", "minutes", minutes)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get fireplace duration range: %w", err)
+	}
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected fireplace duration response type: %T", data)
+	}
+
+	if maxValue, ok := types.LookupFloat(dataMap, "maxValue"); ok && float64(minutes) > maxValue {
+		return fmt.Errorf("fireplace duration of %d minutes exceeds the device maximum of %v minutes", minutes, maxValue)
+	}
+
+	if err := c.Put(ctx, types.URIFireplaceDuration, map[string]interface{}{"value": minutes}); err != nil {
+		return fmt.Errorf("failed to set fireplace duration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestSetFireplaceModeDuration(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIFireplaceMode, "off")
+	d.set(types.URIFireplaceDuration, `{"id":"`+types.URIFireplaceDuration+`","type":"floatValue","value":0,"minValue":0,"maxValue":240}`)
+
+	err := c.SetFireplaceMode(t.Context(), true, 5*time.Hour)
+	if err == nil || !strings.Contains(err.Error(), "maximum of 240") {
+		t.Errorf("Expected a duration above the device maximum to be rejected, got %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 0 {
+		t.Errorf("Expected nothing to be written for a rejected duration, got %+v", puts)
+	}
+
+	if err := c.SetFireplaceMode(t.Context(), true, 90*time.Minute+10*time.Second); err != nil {
+		t.Fatalf("SetFireplaceMode failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 2 || puts[0].URI != types.URIFireplaceDuration || puts[1].URI != types.URIFireplaceMode {
+		t.Fatalf("Expected the duration to be written before the mode, got %+v", puts)
+	}
+	if puts[0].Body != `{"value":91}` || puts[1].Body != `{"value":"on"}` {
+		t.Errorf("Unexpected PUT bodies %q and %q", puts[0].Body, puts[1].Body)
+	}
+
+	state, err := c.FireplaceMode(t.Context())
+	if err != nil {
+		t.Fatalf("FireplaceMode failed: %v", err)
+	}
+	if !state.Active || !state.Timed || state.Remaining != 91*time.Minute {
+		t.Errorf("Unexpected fireplace state %+v", state)
+	}
+
+}
+
+func TestSetFireplaceModeUntimedFirmware(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIFireplaceMode, "off")
+	d.queue(types.URIFireplaceDuration, fakeResponse{StatusCode: 404, Status: "Not Found"})
+
+	if err := c.SetFireplaceMode(t.Context(), true, time.Hour); err != nil {
+		t.Fatalf("Expected the duration to be ignored, got %v", err)
+	}
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIFireplaceMode {
+		t.Errorf("Expected only the mode to be written, got %+v", puts)
+	}
+
+	d.queue(types.URIFireplaceDuration, fakeResponse{StatusCode: 404, Status: "Not Found"})
+	state, err := c.FireplaceMode(t.Context())
+	if err != nil {
+		t.Fatalf("FireplaceMode failed: %v", err)
+	}
+	if !state.Active || state.Timed || state.Remaining != 0 {
+		t.Errorf("Expected an active untimed fireplace mode, got %+v", state)
+	}
+}
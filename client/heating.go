@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// Sensor values the device reports for an open or short-circuited sensor.
+const (
+	sensorOpenValue  = -3276.8
+	sensorShortValue = 3276.7
+)
+
+// parseFloatValue extracts the numeric "value" of a response, returning ErrNotAvailable
+// for missing values, "notAvailable" style strings and the sensor open/short sentinels.
+func parseFloatValue(data interface{}) (float64, error) {
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("unexpected response type: %T", data)
+	}
+
+	switch v := dataMap["value"].(type) {
+	case nil:
+		return 0, ErrNotAvailable
+	case float64:
+		if v == sensorOpenValue || v == sensorShortValue {
+			return 0, ErrNotAvailable
+		}
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, ErrNotAvailable
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("unexpected value type: %T", v)
+	}
+}
+
+func (c *Client) getFloatValue(ctx context.Context, uri string) (float64, error) {
+	data, err := c.Get(ctx, uri)
+	if err != nil {
+		return 0, err
+	}
+	v, err := parseFloatValue(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", uri, err)
+	}
+	return v, nil
+}
+
+// getStringValue returns the string "value" field of uri.
+func (c *Client) getStringValue(ctx context.Context, uri string) (string, error) {
+	data, err := c.Get(ctx, uri)
+	if err != nil {
+		return "", err
+	}
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("%s: unexpected response type: %T", uri, data)
+	}
+	value, _ := types.LookupString(dataMap, "value")
+	return value, nil
+}
+
+// SupplyTemperature retrieves the actual supply (flow) temperature of the heating circuit
+// in Config.TemperatureUnit.
+func (c *Client) SupplyTemperature(ctx context.Context) (float64, error) {
+	return c.inUnit(c.supplyCelsius(ctx))
+}
+
+// SupplySetpoint retrieves the target supply temperature of the heating circuit
+// in Config.TemperatureUnit.
+func (c *Client) SupplySetpoint(ctx context.Context) (float64, error) {
+	return c.inUnit(c.supplySetpointCelsius(ctx))
+}
+
+// ReturnTemperature retrieves the boiler return temperature in Config.TemperatureUnit.
+func (c *Client) ReturnTemperature(ctx context.Context) (float64, error) {
+	return c.inUnit(c.returnCelsius(ctx))
+}
+
+func (c *Client) supplyCelsius(ctx context.Context) (float64, error) {
+	return c.getFloatValue(ctx, circuitURI(ctx, types.URISupplyTemp))
+}
+
+func (c *Client) supplySetpointCelsius(ctx context.Context) (float64, error) {
+	return c.getFloatValue(ctx, circuitURI(ctx, types.URISupplyTempSetpoint))
+}
+
+func (c *Client) returnCelsius(ctx context.Context) (float64, error) {
+	return c.getFloatValue(ctx, types.URIReturnTemp)
+}
+
+// inUnit converts a Celsius reading to Config.TemperatureUnit, passing errors through.
+func (c *Client) inUnit(celsius float64, err error) (float64, error) {
+	if err != nil {
+		return 0, err
+	}
+	return c.config.TemperatureUnit.FromCelsius(celsius), nil
+}
+
+// Modulation retrieves the current burner modulation (flame level) in percent, 0–100.
+// While the burner is off the appliance reports 0 or a not-available value; both are returned as 0.
+func (c *Client) Modulation(ctx context.Context) (float64, error) {
+	data, err := c.Get(ctx, types.URIModulation)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get modulation: %w", err)
+	}
+	return parseModulation(data)
+}
+
+func parseModulation(data interface{}) (float64, error) {
+	v, err := parseFloatValue(data)
+	if errors.Is(err, ErrNotAvailable) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return math.Min(math.Max(v, 0), 100), nil
+}
+
+// HeatingTemperatures retrieves supply, supply setpoint and return temperatures in one call,
+// in Config.TemperatureUnit.
+// Readings the device reports as not available are listed in Unavailable instead of failing the call.
+func (c *Client) HeatingTemperatures(ctx context.Context) (*types.HeatingTemps, error) {
+	ctx = ensureRequestID(ctx)
+
+	temps := &types.HeatingTemps{}
+	readings := []struct {
+		name      string
+		read      func(context.Context) (float64, error)
+		dst       *float64
+		available bool
+	}{
+		{name: "supply", read: c.supplyCelsius, dst: &temps.Supply},
+		{name: "supply_setpoint", read: c.supplySetpointCelsius, dst: &temps.SupplySetpoint},
+		{name: "return", read: c.returnCelsius, dst: &temps.Return},
+	}
+
+	for i := range readings {
+		r := &readings[i]
+		v, err := r.read(ctx)
+		if errors.Is(err, ErrNotAvailable) {
+			temps.Unavailable = append(temps.Unavailable, r.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s temperature: %w", r.name, err)
+		}
+		*r.dst = v
+		r.available = true
+	}
+
+	unit := c.config.TemperatureUnit
+	temps.TemperatureUnit = unit
+	if readings[0].available && readings[2].available {
+		temps.Delta = unit.DeltaFromCelsius(temps.Supply - temps.Return)
+	}
+	for _, r := range readings {
+		if r.available {
+			*r.dst = unit.FromCelsius(*r.dst)
+		}
+	}
+
+	return temps, nil
+}
+
+// Efficiency estimates at the ends of the return temperature range of a condensing
+// boiler; the estimate is interpolated linearly in between.
+const (
+	lowReturnTemp    = 30.0
+	lowReturnEff     = 98.0
+	noncondensingEff = 88.0
+)
+
+// HeatingEfficiency reads the supply and return temperatures and the burner modulation
+// in one batch and derives the delta-T and an efficiency estimate. Temperatures are in
+// Config.TemperatureUnit; the estimate is derived from the device values in Celsius.
+// Readings the appliance does not provide are listed in Unavailable and the values
+// derived from them left zero.
+func (c *Client) HeatingEfficiency(ctx context.Context) (*types.EfficiencySnapshot, error) {
+	ctx = c.ensureRetryBudget(ensureRequestID(ctx), 3)
+
+	snap := &types.EfficiencySnapshot{}
+	readings := []struct {
+		name        string
+		read        func(context.Context) (float64, error)
+		dst         *float64
+		temperature bool
+	}{
+		{name: "supply", read: c.supplyCelsius, dst: &snap.Supply, temperature: true},
+		{name: "return", read: c.returnCelsius, dst: &snap.Return, temperature: true},
+		{name: "modulation", read: c.Modulation, dst: &snap.Modulation},
+	}
+
+	available := map[string]bool{}
+	for _, r := range readings {
+		v, err := r.read(ctx)
+		if isUnsupported(err) {
+			snap.Unavailable = append(snap.Unavailable, r.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", r.name, err)
+		}
+		*r.dst = v
+		available[r.name] = true
+	}
+
+	if available["supply"] && available["return"] {
+		snap.DeltaT = snap.Supply - snap.Return
+	}
+	if available["return"] {
+		snap.Efficiency = estimateEfficiency(snap.Return)
+		snap.Condensing = snap.Modulation > 0 && snap.Return < types.CondensingReturnTemp
+	}
+
+	unit := c.config.TemperatureUnit
+	snap.TemperatureUnit = unit
+	snap.DeltaT = unit.DeltaFromCelsius(snap.DeltaT)
+	for _, r := range readings {
+		if r.temperature && available[r.name] {
+			*r.dst = unit.FromCelsius(*r.dst)
+		}
+	}
+
+	return snap, nil
+}
+
+// estimateEfficiency maps a return temperature to an efficiency in percent: the cooler
+// the return water, the more the flue gas condenses.
+func estimateEfficiency(returnTemp float64) float64 {
+	switch {
+	case returnTemp <= lowReturnTemp:
+		return lowReturnEff
+	case returnTemp >= types.CondensingReturnTemp:
+		return noncondensingEff
+	}
+	frac := (returnTemp - lowReturnTemp) / (types.CondensingReturnTemp - lowReturnTemp)
+	return math.Round((lowReturnEff-frac*(lowReturnEff-noncondensingEff))*10) / 10
+}
+
+// Sensors reads every temperature sensor listed under types.URISensorTemperatures and
+// returns the readings keyed by sensor name, e.g. "outdoor_t1" or "return". Sensors that
+// report "inactive" or another not-available value are left out, so the map only holds
+// sensors that are actually fitted. Temperatures are in Config.TemperatureUnit.
+func (c *Client) Sensors(ctx context.Context) (map[string]types.TempSample, error) {
+	ctx = ensureRequestID(ctx)
+
+	data, err := c.Get(ctx, types.URISensorTemperatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sensors: %w", err)
+	}
+
+	sensors := make(map[string]types.TempSample)
+	for _, uri := range resourceReferences(data) {
+		v, err := c.getFloatValue(ctx, uri)
+		if errors.Is(err, ErrNotAvailable) {
+			c.log(ctx).Debug("skipping inactive sensor", "uri", uri)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sensor %s: %w", uri, err)
+		}
+		sensors[path.Base(uri)] = types.TempSample{Time: c.now(), Temperature: c.config.TemperatureUnit.FromCelsius(v)}
+	}
+
+	return sensors, nil
+}
+
+// GetOutdoorSource retrieves where the outdoor temperature comes from,
+// types.OutdoorSourcePhysical or types.OutdoorSourceVirtual.
+func (c *Client) GetOutdoorSource(ctx context.Context) (string, error) {
+	source, err := c.getStringValue(ctx, types.URIOutdoorSource)
+	if err != nil {
+		return "", fmt.Errorf("failed to get outdoor temperature source: %w", err)
+	}
+	return source, nil
+}
+
+// SetOutdoorSource selects the outdoor temperature source: types.OutdoorSourcePhysical for a
+// wired sensor or types.OutdoorSourceVirtual for internet weather data. Selecting the physical
+// source without a sensor fitted makes the outdoor temperature not available.
+func (c *Client) SetOutdoorSource(ctx context.Context, source string) error {
+	if source != types.OutdoorSourcePhysical && source != types.OutdoorSourceVirtual {
+		return fmt.Errorf("invalid outdoor temperature source %q (must be %q or %q)",
+			source, types.OutdoorSourcePhysical, types.OutdoorSourceVirtual)
+	}
+
+	if err := c.Put(ctx, types.URIOutdoorSource, map[string]string{"value": source}); err != nil {
+		return fmt.Errorf("failed to set outdoor temperature source: %w", err)
+	}
+
+	return nil
+}
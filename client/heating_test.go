@@ -0,0 +1,288 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestParseFloatValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    interface{}
+		want    float64
+		wantErr error
+	}{
+		{name: "number", data: map[string]interface{}{"value": 45.5}, want: 45.5},
+		{name: "numeric string", data: map[string]interface{}{"value": "38.0"}, want: 38},
+		{name: "missing", data: map[string]interface{}{"id": "/x"}, wantErr: ErrNotAvailable},
+		{name: "not available string", data: map[string]interface{}{"value": "notAvailable"}, wantErr: ErrNotAvailable},
+		{name: "open sensor", data: map[string]interface{}{"value": -3276.8}, wantErr: ErrNotAvailable},
+		{name: "short sensor", data: map[string]interface{}{"value": 3276.7}, wantErr: ErrNotAvailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFloatValue(tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %v, got %v (err %v)", tt.want, got, err)
+			}
+		})
+	}
+
+	if _, err := parseFloatValue("raw"); err == nil || errors.Is(err, ErrNotAvailable) {
+		t.Errorf("Expected type error for non-map response, got %v", err)
+	}
+}
+
+func TestHeatingTemperatures(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URISupplyTemp, 55.0)
+	d.setValue(types.URISupplyTempSetpoint, 60.0)
+	d.setValue(types.URIReturnTemp, 40.5)
+
+	temps, err := c.HeatingTemperatures(t.Context())
+	if err != nil {
+		t.Fatalf("HeatingTemperatures failed: %v", err)
+	}
+	if temps.Supply != 55 || temps.SupplySetpoint != 60 || temps.Return != 40.5 || temps.Delta != 14.5 {
+		t.Errorf("Unexpected temperatures: %+v", temps)
+	}
+
+	sp, err := c.SupplySetpoint(t.Context())
+	if err != nil || sp != 60 {
+		t.Errorf("SupplySetpoint: expected 60, got %v (err %v)", sp, err)
+	}
+}
+
+func TestHeatingTemperaturesFahrenheit(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.TemperatureUnit = types.Fahrenheit
+	d.setValue(types.URISupplyTemp, 55.0)
+	d.setValue(types.URISupplyTempSetpoint, 60.0)
+	d.setValue(types.URIReturnTemp, 40.5)
+
+	temps, err := c.HeatingTemperatures(t.Context())
+	if err != nil {
+		t.Fatalf("HeatingTemperatures failed: %v", err)
+	}
+	if temps.Supply != 131 || temps.SupplySetpoint != 140 || temps.Return != 104.9 || temps.Delta != 26.1 {
+		t.Errorf("Expected Fahrenheit temperatures, got %+v", temps)
+	}
+	if temps.TemperatureUnit != types.Fahrenheit {
+		t.Errorf("TemperatureUnit = %q, want Fahrenheit", temps.TemperatureUnit)
+	}
+
+	if v, err := c.ReturnTemperature(t.Context()); err != nil || v != 104.9 {
+		t.Errorf("ReturnTemperature: expected 104.9, got %v (err %v)", v, err)
+	}
+
+	// The temperatures are converted, the efficiency estimate works on the device values.
+	snap, err := c.HeatingEfficiency(t.Context())
+	if err != nil {
+		t.Fatalf("HeatingEfficiency failed: %v", err)
+	}
+	if snap.Supply != 131 || snap.Return != 104.9 || snap.DeltaT != 26.1 || snap.TemperatureUnit != types.Fahrenheit {
+		t.Errorf("HeatingEfficiency: expected Fahrenheit temperatures, got %+v", snap)
+	}
+	if want := estimateEfficiency(40.5); snap.Efficiency != want {
+		t.Errorf("Efficiency = %v, want %v from the Celsius return temperature", snap.Efficiency, want)
+	}
+}
+
+func TestHeatingTemperaturesReturnUnavailable(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URISupplyTemp, 55.0)
+	d.setValue(types.URISupplyTempSetpoint, 60.0)
+	d.setValue(types.URIReturnTemp, -3276.8)
+
+	if _, err := c.ReturnTemperature(t.Context()); !errors.Is(err, ErrNotAvailable) {
+		t.Errorf("Expected ErrNotAvailable, got %v", err)
+	}
+
+	temps, err := c.HeatingTemperatures(t.Context())
+	if err != nil {
+		t.Fatalf("HeatingTemperatures failed: %v", err)
+	}
+	if temps.Delta != 0 || len(temps.Unavailable) != 1 || temps.Unavailable[0] != "return" {
+		t.Errorf("Expected return reported unavailable with no delta, got %+v", temps)
+	}
+}
+
+func TestParseModulation(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want float64
+	}{
+		{
+			name: "burning",
+			data: map[string]interface{}{"id": "/system/appliance/actualPower", "type": "floatValue", "value": 42.0, "unitOfMeasure": "%", "minValue": 0.0, "maxValue": 100.0},
+			want: 42,
+		},
+		{name: "off", data: map[string]interface{}{"value": 0.0}, want: 0},
+		{name: "not available", data: map[string]interface{}{"value": "notAvailable"}, want: 0},
+		{name: "out of range", data: map[string]interface{}{"value": 101.5}, want: 100},
+	}
+
+	for _, tt := range tests {
+		got, err := parseModulation(tt.data)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: parseModulation = %v (err %v), want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseModulation(map[string]interface{}{"value": true}); err == nil {
+		t.Error("Expected error for a non-numeric value")
+	}
+}
+
+func TestModulation(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIModulation, 63.0)
+
+	got, err := c.Modulation(t.Context())
+	if err != nil || got != 63 {
+		t.Errorf("Modulation = %v (err %v), want 63", got, err)
+	}
+}
+
+func TestSensors(t *testing.T) {
+	c, d := newFakeDevice(t)
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	d.set(types.URISensorTemperatures, `{"id":"/system/sensors/temperatures","type":"refEnum","references":[
+		{"id":"/system/sensors/temperatures/outdoor_t1"},
+		{"id":"/system/sensors/temperatures/return"},
+		{"id":"/system/sensors/temperatures/supply_t1"},
+		{"id":"/system/sensors/temperatures/hotWater_t2"},
+		{"id":"/system/sensors/temperatures/switch"}
+	]}`)
+	d.setValue(types.URIOutdoorTemp, 7.5)
+	d.setValue(types.URIReturnTemp, "41.0")
+	d.setValue("/system/sensors/temperatures/supply_t1", 55.0)
+	d.setValue("/system/sensors/temperatures/hotWater_t2", "inactive")
+	d.setValue("/system/sensors/temperatures/switch", -3276.8)
+
+	sensors, err := c.Sensors(t.Context())
+	if err != nil {
+		t.Fatalf("Sensors failed: %v", err)
+	}
+
+	want := map[string]float64{"outdoor_t1": 7.5, "return": 41, "supply_t1": 55}
+	if len(sensors) != len(want) {
+		t.Fatalf("Expected sensors %v, got %+v", want, sensors)
+	}
+	for name, temp := range want {
+		s, ok := sensors[name]
+		if !ok || s.Temperature != temp || !s.Time.Equal(now) {
+			t.Errorf("Sensor %s: expected %v at %v, got %+v", name, temp, now, s)
+		}
+	}
+}
+
+func TestSensorsFahrenheit(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.TemperatureUnit = types.Fahrenheit
+	d.set(types.URISensorTemperatures, `{"type":"refEnum","references":[{"id":"/system/sensors/temperatures/outdoor_t1"}]}`)
+	d.setValue(types.URIOutdoorTemp, 7.5)
+
+	sensors, err := c.Sensors(t.Context())
+	if err != nil {
+		t.Fatalf("Sensors failed: %v", err)
+	}
+	if got := sensors["outdoor_t1"].Temperature; got != 45.5 {
+		t.Errorf("outdoor_t1 = %v, want 45.5 (Fahrenheit)", got)
+	}
+}
+
+func TestSensorsReadError(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set(types.URISensorTemperatures, `{"type":"refEnum","references":[{"id":"/system/sensors/temperatures/missing"}]}`)
+
+	if _, err := c.Sensors(t.Context()); err == nil {
+		t.Error("Expected an error for an unreadable sensor")
+	}
+}
+
+func TestOutdoorSource(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIOutdoorSource, types.OutdoorSourceVirtual)
+
+	source, err := c.GetOutdoorSource(t.Context())
+	if err != nil || source != types.OutdoorSourceVirtual {
+		t.Fatalf("GetOutdoorSource: expected virtual, got %q (err %v)", source, err)
+	}
+
+	for _, invalid := range []string{"", "Physical", "weather", "sensor"} {
+		if err := c.SetOutdoorSource(t.Context(), invalid); err == nil {
+			t.Errorf("Expected source %q to be rejected", invalid)
+		}
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 0 {
+		t.Fatalf("Expected no PUT for invalid sources, got %+v", puts)
+	}
+
+	if err := c.SetOutdoorSource(t.Context(), types.OutdoorSourcePhysical); err != nil {
+		t.Fatalf("SetOutdoorSource failed: %v", err)
+	}
+	if source, _ := c.GetOutdoorSource(t.Context()); source != types.OutdoorSourcePhysical {
+		t.Errorf("Expected physical after SetOutdoorSource, got %q", source)
+	}
+}
+
+func TestHeatingEfficiency(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URISupplyTemp, 55.0)
+	d.setValue(types.URIReturnTemp, 42.5)
+	d.setValue(types.URIModulation, 35.0)
+
+	snap, err := c.HeatingEfficiency(t.Context())
+	if err != nil {
+		t.Fatalf("HeatingEfficiency failed: %v", err)
+	}
+	if snap.DeltaT != 12.5 || snap.Modulation != 35 || !snap.Condensing {
+		t.Errorf("Unexpected snapshot: %+v", snap)
+	}
+	if snap.Efficiency != 93 {
+		t.Errorf("Efficiency = %v, want 93 halfway between 30 and 55°C return", snap.Efficiency)
+	}
+	if len(snap.Unavailable) != 0 {
+		t.Errorf("Expected all readings available, got %v", snap.Unavailable)
+	}
+}
+
+func TestHeatingEfficiencyMissingReadings(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URISupplyTemp, 60.0)
+	d.setValue(types.URIReturnTemp, -3276.8)
+	d.queue(types.URIModulation, fakeResponse{StatusCode: 404, Status: "Not Found"})
+
+	snap, err := c.HeatingEfficiency(t.Context())
+	if err != nil {
+		t.Fatalf("HeatingEfficiency failed: %v", err)
+	}
+	if snap.Supply != 60 || snap.DeltaT != 0 || snap.Efficiency != 0 || snap.Condensing {
+		t.Errorf("Expected only the supply temperature, got %+v", snap)
+	}
+	if len(snap.Unavailable) != 2 || snap.Unavailable[0] != "return" || snap.Unavailable[1] != "modulation" {
+		t.Errorf("Expected return and modulation unavailable, got %v", snap.Unavailable)
+	}
+}
+
+func TestEstimateEfficiency(t *testing.T) {
+	for _, tt := range []struct{ ret, want float64 }{{20, 98}, {30, 98}, {42.5, 93}, {55, 88}, {70, 88}} {
+		if got := estimateEfficiency(tt.ret); got != tt.want {
+			t.Errorf("estimateEfficiency(%v) = %v, want %v", tt.ret, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// hotWaterTempEndpoint returns the hot water setpoint URI matching the current user mode.
+func (c *Client) hotWaterTempEndpoint(ctx context.Context) (string, error) {
+	mode, err := c.currentUserMode(ctx)
+	if err != nil {
+		return "", err
+	}
+	if mode == types.UserModeClock {
+		return types.URIHotWaterClockTemp, nil
+	}
+	return types.URIHotWaterManualTemp, nil
+}
+
+// HotWaterSetpoint retrieves the target hot water temperature in Config.TemperatureUnit.
+// The endpoint is chosen by user mode as in SetHotWaterSupply.
+func (c *Client) HotWaterSetpoint(ctx context.Context) (float64, error) {
+	ctx = ensureRequestID(ctx)
+
+	endpoint, err := c.hotWaterTempEndpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := c.getFloatValue(ctx, endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get hot water setpoint: %w", err)
+	}
+	return c.config.TemperatureUnit.FromCelsius(v), nil
+}
+
+// SetHotWaterSetpoint changes the target hot water temperature of the current user mode.
+// The temperature is in Config.TemperatureUnit and must lie within the minValue and
+// maxValue the device reports, or types.MinHotWaterSetpoint and types.MaxHotWaterSetpoint
+// if it reports none.
+func (c *Client) SetHotWaterSetpoint(ctx context.Context, temperature float64) error {
+	ctx = ensureRequestID(ctx)
+
+	endpoint, err := c.hotWaterTempEndpoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := c.Get(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to get hot water setpoint range: %w", err)
+	}
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected hot water setpoint response type: %T", data)
+	}
+
+	minValue, ok := types.LookupFloat(dataMap, "minValue")
+	if !ok {
+		minValue = types.MinHotWaterSetpoint
+	}
+	maxValue, ok := types.LookupFloat(dataMap, "maxValue")
+	if !ok {
+		maxValue = types.MaxHotWaterSetpoint
+	}
+
+	celsius := c.config.TemperatureUnit.ToCelsius(temperature)
+	if celsius < minValue || celsius > maxValue {
+		return fmt.Errorf("hot water setpoint %.1f°C out of range %.1f-%.1f°C", celsius, minValue, maxValue)
+	}
+
+	if err := c.Put(ctx, endpoint, map[string]interface{}{"value": celsius}); err != nil {
+		return fmt.Errorf("failed to set hot water setpoint: %w", err)
+	}
+
+	return nil
+}
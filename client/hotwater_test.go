@@ -0,0 +1,74 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestHotWaterSetpoint(t *testing.T) {
+	for _, tt := range []struct {
+		mode     string
+		endpoint string
+		other    string
+	}{
+		{mode: "clock", endpoint: types.URIHotWaterClockTemp, other: types.URIHotWaterManualTemp},
+		{mode: "manual", endpoint: types.URIHotWaterManualTemp, other: types.URIHotWaterClockTemp},
+	} {
+		t.Run(tt.mode, func(t *testing.T) {
+			c, d := newFakeDevice(t)
+			d.setValue(types.URIStatus, map[string]interface{}{"UMD": tt.mode})
+			d.set(tt.endpoint, `{"id":"`+tt.endpoint+`","value":55,"minValue":30,"maxValue":60}`)
+			d.set(tt.other, `{"id":"`+tt.other+`","value":40,"minValue":30,"maxValue":60}`)
+
+			got, err := c.HotWaterSetpoint(t.Context())
+			if err != nil {
+				t.Fatalf("HotWaterSetpoint failed: %v", err)
+			}
+			if got != 55 {
+				t.Errorf("Expected setpoint 55 from %s, got %v", tt.endpoint, got)
+			}
+
+			if err := c.SetHotWaterSetpoint(t.Context(), 50); err != nil {
+				t.Fatalf("SetHotWaterSetpoint failed: %v", err)
+			}
+			puts := d.requestsFor("PUT")
+			if len(puts) != 1 || puts[0].URI != tt.endpoint {
+				t.Fatalf("Expected one PUT to %s, got %+v", tt.endpoint, puts)
+			}
+			var body map[string]float64
+			if err := json.Unmarshal([]byte(puts[0].Body), &body); err != nil || body["value"] != 50 {
+				t.Errorf("Expected value 50 in PUT body, got %q", puts[0].Body)
+			}
+		})
+	}
+}
+
+func TestSetHotWaterSetpointRange(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "manual"})
+	d.set(types.URIHotWaterManualTemp, `{"value":50,"minValue":40,"maxValue":55}`)
+
+	for _, temp := range []float64{39.5, 55.5, 70} {
+		if err := c.SetHotWaterSetpoint(t.Context(), temp); err == nil {
+			t.Errorf("Expected %v to be rejected by the device range 40-55", temp)
+		}
+	}
+	if err := c.SetHotWaterSetpoint(t.Context(), 55); err != nil {
+		t.Errorf("Expected the range maximum to be accepted, got %v", err)
+	}
+
+	// Without metadata the default range applies.
+	d.set(types.URIHotWaterManualTemp, `{"value":50}`)
+	if err := c.SetHotWaterSetpoint(t.Context(), types.MaxHotWaterSetpoint+1); err == nil {
+		t.Error("Expected a setpoint above the default maximum to be rejected")
+	}
+	if err := c.SetHotWaterSetpoint(t.Context(), types.MinHotWaterSetpoint); err != nil {
+		t.Errorf("Expected the default minimum to be accepted, got %v", err)
+	}
+
+	if puts := d.requestsFor("PUT"); len(puts) != 2 {
+		t.Errorf("Expected only the in-range setpoints to be written, got %+v", puts)
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// MaintenanceInfo retrieves the next service date, burner starts and operating hours.
+// Readings the appliance does not provide (not available, or 404 on older appliances)
+// are listed in Unavailable instead of failing the call. A service date reported as
+// "not set" leaves NextServiceDate nil.
+func (c *Client) MaintenanceInfo(ctx context.Context) (*types.MaintenanceInfo, error) {
+	ctx = ensureRequestID(ctx)
+
+	info := &types.MaintenanceInfo{}
+	readings := []struct {
+		name   string
+		uri    string
+		decode func(interface{}) error
+	}{
+		{name: "next_service_date", uri: types.URINextServiceDate, decode: func(data interface{}) (err error) {
+			info.NextServiceDate, err = parseServiceDate(data)
+			return err
+		}},
+		{name: "burner_starts", uri: types.URIBurnerStarts, decode: func(data interface{}) error {
+			v, err := parseFloatValue(data)
+			info.BurnerStarts = int(v)
+			return err
+		}},
+		{name: "operating_hours", uri: types.URIOperatingTime, decode: func(data interface{}) error {
+			minutes, err := parseFloatValue(data)
+			info.OperatingHours = minutes / 60
+			return err
+		}},
+	}
+
+	for _, r := range readings {
+		data, err := c.Get(ctx, r.uri)
+		if err == nil {
+			err = r.decode(data)
+		}
+
+		if isUnsupported(err) {
+			info.Unavailable = append(info.Unavailable, r.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", r.name, err)
+		}
+	}
+
+	return info, nil
+}
+
+// FaultHistory returns the fault log of the appliance in the order the device reports it,
+// with descriptions for known display codes. It returns ErrUnsupported if the appliance
+// has no fault log; an empty slice means no faults are recorded.
+func (c *Client) FaultHistory(ctx context.Context) ([]types.Fault, error) {
+	data, err := c.Get(ctx, types.URIFaultLog)
+	if err != nil {
+		if isUnsupported(err) {
+			return nil, fmt.Errorf("fault history: %w", ErrUnsupported)
+		}
+		return nil, fmt.Errorf("failed to get fault history: %w", err)
+	}
+
+	return types.ParseFaultLog(data)
+}
+
+// unsetServiceDates are the placeholder dates the device reports for an unset service
+// date, like unused recording slots.
+var unsetServiceDates = map[string]bool{
+	"255-256-65535": true,
+}
+
+// parseServiceDate decodes a service date response. "not set", an empty value and the
+// placeholder dates in unsetServiceDates yield nil; any other value that is not a date
+// is an error.
+func parseServiceDate(data interface{}) (*time.Time, error) {
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %T", data)
+	}
+
+	value, ok := dataMap["value"].(string)
+	if !ok {
+		return nil, ErrNotAvailable
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "not set") || unsetServiceDates[value] {
+		return nil, nil
+	}
+
+	date, err := time.Parse(types.RecordingDateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service date %q: %w", value, err)
+	}
+	return &date, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestParseServiceDate(t *testing.T) {
+	due := time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		data interface{}
+		want *time.Time
+	}{
+		{name: "set", data: map[string]interface{}{"id": types.URINextServiceDate, "type": "stringValue", "value": "14-03-2025"}, want: &due},
+		{name: "not set", data: map[string]interface{}{"value": "not set"}},
+		{name: "empty", data: map[string]interface{}{"value": ""}},
+		{name: "placeholder", data: map[string]interface{}{"value": "255-256-65535"}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseServiceDate(tt.data)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseServiceDate = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"2025-03-14", "31-02-2025", "soon"} {
+		if got, err := parseServiceDate(map[string]interface{}{"value": value}); err == nil {
+			t.Errorf("parseServiceDate(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestMaintenanceInfo(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URINextServiceDate, "14-03-2025")
+	d.setValue(types.URIOperatingTime, 1234567.0)
+	// URIBurnerStarts is unknown to the device and answers 404.
+
+	info, err := c.MaintenanceInfo(t.Context())
+	if err != nil {
+		t.Fatalf("MaintenanceInfo failed: %v", err)
+	}
+
+	if info.NextServiceDate == nil || !info.NextServiceDate.Equal(time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected service date %v", info.NextServiceDate)
+	}
+	if want := 1234567.0 / 60; info.OperatingHours != want {
+		t.Errorf("OperatingHours = %v, want %v", info.OperatingHours, want)
+	}
+	if !reflect.DeepEqual(info.Unavailable, []string{"burner_starts"}) {
+		t.Errorf("Expected burner_starts unavailable, got %v", info.Unavailable)
+	}
+
+	d.setValue(types.URIBurnerStarts, 48211.0)
+	d.setValue(types.URINextServiceDate, "not set")
+	info, err = c.MaintenanceInfo(t.Context())
+	if err != nil {
+		t.Fatalf("MaintenanceInfo failed: %v", err)
+	}
+	if info.BurnerStarts != 48211 || info.NextServiceDate != nil || len(info.Unavailable) != 0 {
+		t.Errorf("Unexpected maintenance info: %+v", info)
+	}
+}
+
+func TestFaultHistory(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	if _, err := c.FaultHistory(t.Context()); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Expected ErrUnsupported without a fault log, got %v", err)
+	}
+
+	d.setValue(types.URIFaultLog, []interface{}{})
+	faults, err := c.FaultHistory(t.Context())
+	if err != nil || len(faults) != 0 {
+		t.Fatalf("Expected no faults for an empty log, got %v, %v", faults, err)
+	}
+
+	d.setValue(types.URIFaultLog, []interface{}{
+		map[string]interface{}{"t": "2024-01-14T08:12:00", "dcd": "6A", "ccd": 227},
+		map[string]interface{}{"t": "2024-02-03T21:40:00", "dcd": "2E", "ccd": 207},
+	})
+	faults, err = c.FaultHistory(t.Context())
+	if err != nil {
+		t.Fatalf("FaultHistory failed: %v", err)
+	}
+	if len(faults) != 2 || faults[0].DisplayCode != "6A" || faults[1].Description != "water pressure too low" {
+		t.Errorf("Unexpected faults: %+v", faults)
+	}
+}
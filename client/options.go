@@ -0,0 +1,35 @@
+package client
+
+// WriteOption modifies the behavior of the high-level write methods such as SetUserMode.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	force     bool
+	confirm   bool
+	tolerance float64
+}
+
+// WithForce makes a write method issue its PUT even when the device already reports the requested value.
+func WithForce() WriteOption {
+	return func(o *writeOptions) {
+		o.force = true
+	}
+}
+
+// WithConfirm makes a write method read the value back after the PUT and return a
+// *MismatchError if the device does not report the written value. Numeric values
+// match if they differ by at most tolerance (in device units, e.g. °C).
+func WithConfirm(tolerance float64) WriteOption {
+	return func(o *writeOptions) {
+		o.confirm = true
+		o.tolerance = tolerance
+	}
+}
+
+func applyWriteOptions(opts []WriteOption) writeOptions {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+
+	xmpp "github.com/xmppo/go-xmpp"
+)
+
+// ClientPool manages clients for several devices on the same backend.
+//
+// The backend binds one session to one device JID, so each client still has its own
+// XMPP connection. The pool shares the dialer between them: TLS sessions are resumed
+// from a common cache instead of a full handshake per device, and all clients are
+// connected and closed together.
+type ClientPool struct {
+	mu      sync.Mutex
+	clients map[string]*Client
+	order   []string
+	dial    dialFunc
+	closed  bool
+
+	sessionCache tls.ClientSessionCache
+}
+
+// NewClientPool creates an empty pool.
+func NewClientPool() *ClientPool {
+	p := &ClientPool{
+		clients:      make(map[string]*Client),
+		sessionCache: tls.NewLRUClientSessionCache(0),
+	}
+	p.dial = p.dialShared
+	return p
+}
+
+// dialShared dials with the pool's TLS session cache.
+func (p *ClientPool) dialShared(options xmpp.Options) (transport, error) {
+	if options.TLSConfig != nil {
+		cfg := options.TLSConfig.Clone()
+		cfg.ClientSessionCache = p.sessionCache
+		options.TLSConfig = cfg
+	}
+	return dialXMPP(options)
+}
+
+// Add creates a client for config that dials through the pool.
+// The client is not connected; use Connect on the pool or the client.
+func (p *ClientPool) Add(config Config) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClientClosed
+	}
+	if _, ok := p.clients[config.SerialNumber]; ok {
+		return nil, fmt.Errorf("client for serial %s already in pool", config.SerialNumber)
+	}
+
+	c, err := NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	c.dial = p.dial
+
+	p.clients[config.SerialNumber] = c
+	p.order = append(p.order, config.SerialNumber)
+
+	return c, nil
+}
+
+// Client returns the pooled client for a serial number.
+func (p *ClientPool) Client(serialNumber string) (*Client, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.clients[serialNumber]
+	return c, ok
+}
+
+// Clients returns the pooled clients in the order they were added.
+func (p *ClientPool) Clients() []*Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clients := make([]*Client, 0, len(p.order))
+	for _, serial := range p.order {
+		clients = append(clients, p.clients[serial])
+	}
+	return clients
+}
+
+// Connect connects every client that is not yet connected and returns the joined errors.
+func (p *ClientPool) Connect(ctx context.Context) error {
+	var errs []error
+	for _, c := range p.Clients() {
+		if c.IsConnected() {
+			continue
+		}
+		if err := c.Connect(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.config.SerialNumber, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every client in the pool. The pool cannot be used afterwards.
+func (p *ClientPool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	var errs []error
+	for _, c := range p.Clients() {
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.config.SerialNumber, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	xmpp "github.com/xmppo/go-xmpp"
+)
+
+func TestClientPoolRoutesPerDevice(t *testing.T) {
+	devices := map[string]*fakeDevice{
+		"111111111": newFakeBackend(t, "111111111"),
+		"222222222": newFakeBackend(t, "222222222"),
+	}
+	devices["111111111"].setValue("/test/value", "first")
+	devices["222222222"].setValue("/test/value", "second")
+
+	pool := NewClientPool()
+	pool.dial = func(options xmpp.Options) (transport, error) {
+		for serial, d := range devices {
+			if strings.Contains(options.User, serial) {
+				return d.ft, nil
+			}
+		}
+		t.Fatalf("unexpected dial for %s", options.User)
+		return nil, nil
+	}
+
+	for serial := range devices {
+		c, err := pool.Add(Config{SerialNumber: serial, AccessKey: "abcdefghijklmnop", Password: "secret"})
+		if err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		c.SetLogger(slog.New(slog.DiscardHandler))
+	}
+	if _, err := pool.Add(Config{SerialNumber: "111111111", AccessKey: "abcdefghijklmnop", Password: "secret"}); err == nil {
+		t.Error("Expected error adding a duplicate serial")
+	}
+
+	if err := pool.Connect(t.Context()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	for serial, want := range map[string]string{"111111111": "first", "222222222": "second"} {
+		c, ok := pool.Client(serial)
+		if !ok {
+			t.Fatalf("No client for %s", serial)
+		}
+		data, err := c.Get(t.Context(), "/test/value")
+		if err != nil {
+			t.Fatalf("Get for %s failed: %v", serial, err)
+		}
+		if v := data.(map[string]interface{})["value"]; v != want {
+			t.Errorf("Client %s got %v, want %q", serial, v, want)
+		}
+	}
+
+	for serial, d := range devices {
+		if n := len(d.requestsFor("GET")); n != 1 {
+			t.Errorf("Device %s received %d GETs, want 1", serial, n)
+		}
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	for _, c := range pool.Clients() {
+		if c.IsConnected() {
+			t.Error("Expected all clients disconnected after Close")
+		}
+	}
+	if _, err := pool.Add(Config{SerialNumber: "333333333", AccessKey: "abcdefghijklmnop", Password: "secret"}); err == nil {
+		t.Error("Expected error adding to a closed pool")
+	}
+}
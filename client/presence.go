@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// GetPresence reports whether the device considers the house occupied. It returns
+// ErrUnsupported if the appliance has no occupancy flag.
+func (c *Client) GetPresence(ctx context.Context) (bool, error) {
+	value, err := c.getStringValue(ctx, types.URIPresence)
+	if err != nil {
+		if isUnsupported(err) {
+			return false, fmt.Errorf("presence: %w", ErrUnsupported)
+		}
+		return false, fmt.Errorf("failed to get presence: %w", err)
+	}
+	return value == "on", nil
+}
+
+// SetPresence marks the house as occupied or empty. Unlike home entrance detection
+// (Status.HEDEnabled), which derives presence from registered phones, this sets the
+// flag directly; with HED enabled, the next HED update may overwrite it.
+func (c *Client) SetPresence(ctx context.Context, present bool) error {
+	value := "off"
+	if present {
+		value = "on"
+	}
+
+	if err := c.Put(ctx, types.URIPresence, map[string]string{"value": value}); err != nil {
+		return fmt.Errorf("failed to set presence: %w", err)
+	}
+
+	return nil
+}
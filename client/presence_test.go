@@ -0,0 +1,35 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestPresenceRoundTrip(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	if _, err := c.GetPresence(t.Context()); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Expected ErrUnsupported without an occupancy flag, got %v", err)
+	}
+
+	d.setValue(types.URIPresence, "on")
+	for _, present := range []bool{false, true} {
+		if err := c.SetPresence(t.Context(), present); err != nil {
+			t.Fatalf("SetPresence(%v) failed: %v", present, err)
+		}
+		got, err := c.GetPresence(t.Context())
+		if err != nil {
+			t.Fatalf("GetPresence failed: %v", err)
+		}
+		if got != present {
+			t.Errorf("GetPresence = %v after SetPresence(%v)", got, present)
+		}
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 2 || puts[0].Body != `{"value":"off"}` || puts[1].Body != `{"value":"on"}` {
+		t.Errorf("Unexpected PUTs: %+v", puts)
+	}
+}
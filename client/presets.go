@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// presetURI returns the endpoint storing the named temperature preset.
+func presetURI(name string) (string, error) {
+	switch name {
+	case types.PresetComfort:
+		return types.URIPresetComfort, nil
+	case types.PresetEco:
+		return types.URIPresetEco, nil
+	case types.PresetManual:
+		return types.URIManualSetpoint, nil
+	default:
+		return "", fmt.Errorf("unknown temperature preset %q (want %q, %q or %q)",
+			name, types.PresetComfort, types.PresetEco, types.PresetManual)
+	}
+}
+
+// GetTemperaturePresets retrieves the comfort, eco and manual temperature levels,
+// in Config.TemperatureUnit.
+func (c *Client) GetTemperaturePresets(ctx context.Context) (*types.Presets, error) {
+	ctx = ensureRequestID(ctx)
+
+	var presets types.Presets
+	for _, p := range []struct {
+		uri    string
+		target *float64
+	}{
+		{types.URIPresetComfort, &presets.Comfort},
+		{types.URIPresetEco, &presets.Eco},
+		{types.URIManualSetpoint, &presets.Manual},
+	} {
+		v, err := c.getFloatValue(ctx, circuitURI(ctx, p.uri))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get temperature presets: %w", err)
+		}
+		*p.target = c.config.TemperatureUnit.FromCelsius(v)
+	}
+
+	return &presets, nil
+}
+
+// SetTemperaturePreset changes a named temperature level ("comfort", "eco" or "manual").
+// The temperature is in Config.TemperatureUnit and must lie within
+// types.MinSetpoint and types.MaxSetpoint once converted to Celsius.
+// Changing comfort or eco affects every switchpoint of the clock program using that level.
+func (c *Client) SetTemperaturePreset(ctx context.Context, name string, temperature float64) error {
+	return c.setPresetCelsius(ctx, name, c.config.TemperatureUnit.ToCelsius(temperature))
+}
+
+// setPresetCelsius is SetTemperaturePreset with the temperature in Celsius.
+func (c *Client) setPresetCelsius(ctx context.Context, name string, celsius float64) error {
+	uri, err := presetURI(name)
+	if err != nil {
+		return err
+	}
+
+	if celsius < types.MinSetpoint || celsius > types.MaxSetpoint {
+		return fmt.Errorf("preset temperature %.1f°C out of range %.1f-%.1f°C",
+			celsius, types.MinSetpoint, types.MaxSetpoint)
+	}
+
+	if err := c.Put(ctx, circuitURI(ctx, uri), map[string]interface{}{"value": celsius}); err != nil {
+		return fmt.Errorf("failed to set %s preset: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestPresetURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{types.PresetComfort, types.URIPresetComfort, false},
+		{types.PresetEco, types.URIPresetEco, false},
+		{types.PresetManual, types.URIManualSetpoint, false},
+		{"Comfort", "", true},
+		{"away", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := presetURI(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("presetURI(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("presetURI(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTemperaturePreset(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	if err := c.SetTemperaturePreset(t.Context(), types.PresetEco, 16.5); err != nil {
+		t.Fatalf("SetTemperaturePreset failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIPresetEco {
+		t.Fatalf("Expected one PUT to %s, got %+v", types.URIPresetEco, puts)
+	}
+	if puts[0].Body != `{"value":16.5}` {
+		t.Errorf("Unexpected PUT body %s", puts[0].Body)
+	}
+
+	for _, temp := range []float64{4.5, 30.5} {
+		if err := c.SetTemperaturePreset(t.Context(), types.PresetComfort, temp); err == nil {
+			t.Errorf("Expected out of range error for %.1f", temp)
+		}
+	}
+	if err := c.SetTemperaturePreset(t.Context(), "party", 20); err == nil {
+		t.Error("Expected error for unknown preset name")
+	}
+	if n := len(d.requestsFor("PUT")); n != 1 {
+		t.Errorf("Expected rejected presets not to be sent, got %d PUTs", n)
+	}
+}
+
+func TestGetTemperaturePresets(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIPresetComfort, 21.0)
+	d.setValue(types.URIPresetEco, 16.0)
+	d.setValue(types.URIManualSetpoint, 19.5)
+
+	presets, err := c.GetTemperaturePresets(t.Context())
+	if err != nil {
+		t.Fatalf("GetTemperaturePresets failed: %v", err)
+	}
+
+	want := types.Presets{Comfort: 21, Eco: 16, Manual: 19.5}
+	if *presets != want {
+		t.Errorf("Expected %+v, got %+v", want, *presets)
+	}
+}
@@ -0,0 +1,338 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// deviceDays maps types.ProgramSwitchpoint.DayOfWeek (0=Sunday) to the day codes used by the backend.
+var deviceDays = []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+
+func programURI(program int) (string, error) {
+	switch program {
+	case 1:
+		return types.URIProgram1, nil
+	case 2:
+		return types.URIProgram2, nil
+	default:
+		return "", fmt.Errorf("invalid program %d (must be 1 or 2)", program)
+	}
+}
+
+// ActiveProgram returns the number of the currently active heating program (1 or 2).
+func (c *Client) ActiveProgram(ctx context.Context) (int, error) {
+	data, err := c.Get(ctx, types.URIActiveProgram)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active program: %w", err)
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("unexpected active program response type: %T", data)
+	}
+
+	program, _ := types.LookupInt(dataMap, "value")
+	return program, nil
+}
+
+// Program retrieves the switchpoints of the given heating program (1 or 2), with
+// temperatures in Config.TemperatureUnit.
+// Program.Active reports whether it is the currently active program.
+func (c *Client) Program(ctx context.Context, program int) (*types.Program, error) {
+	if _, err := programURI(program); err != nil {
+		return nil, err
+	}
+
+	ctx = ensureRequestID(ctx)
+
+	active, err := c.ActiveProgram(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := c.readProgram(ctx, program, active == program)
+	if err != nil {
+		return nil, err
+	}
+	return p.InUnit(c.config.TemperatureUnit), nil
+}
+
+// readProgram fetches and decodes the switchpoints of program, whose activity the caller knows.
+// Temperatures are in Celsius as reported by the device.
+func (c *Client) readProgram(ctx context.Context, program int, active bool) (*types.Program, error) {
+	uri, err := programURI(program)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.Get(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program %d: %w", program, err)
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected program response type: %T", data)
+	}
+
+	return decodeProgram(dataMap, active)
+}
+
+// decodeProgram maps a program response ({"value": [switchpoint...]}) to a Program.
+func decodeProgram(dataMap map[string]interface{}, active bool) (*types.Program, error) {
+	entries, ok := dataMap["value"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("program response missing 'value' list")
+	}
+
+	result := &types.Program{
+		Active:       active,
+		Switchpoints: make([]types.ProgramSwitchpoint, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		entryMap, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sp, err := parseSwitchpoint(entryMap)
+		if err != nil {
+			return nil, err
+		}
+		result.Switchpoints = append(result.Switchpoints, sp)
+	}
+
+	return result, nil
+}
+
+// SetProgram uploads the switchpoints of the given heating program (1 or 2).
+// Temperatures are interpreted in Config.TemperatureUnit and converted to Celsius before sending.
+// The Active and TemperatureUnit fields are ignored; use ActivateProgram to switch programs.
+func (c *Client) SetProgram(ctx context.Context, program int, p *types.Program) error {
+	unit := c.config.TemperatureUnit
+	celsius := *p
+	celsius.Switchpoints = make([]types.ProgramSwitchpoint, len(p.Switchpoints))
+	for i, sp := range p.Switchpoints {
+		if sp.Preset == "" {
+			sp.Temperature = unit.ToCelsius(sp.Temperature)
+		}
+		celsius.Switchpoints[i] = sp
+	}
+
+	return c.setProgramCelsius(ctx, program, &celsius)
+}
+
+// setProgramCelsius uploads a program whose temperatures are already in Celsius.
+func (c *Client) setProgramCelsius(ctx context.Context, program int, p *types.Program) error {
+	uri, err := programURI(program)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid program: %w", err)
+	}
+
+	entries := make([]map[string]interface{}, 0, len(p.Switchpoints))
+	for _, sp := range p.Switchpoints {
+		entries = append(entries, formatSwitchpoint(sp))
+	}
+
+	if err := c.Put(ctx, uri, map[string]interface{}{"value": entries}); err != nil {
+		return fmt.Errorf("failed to set program %d: %w", program, err)
+	}
+
+	return nil
+}
+
+// ActivateProgram makes the given heating program (1 or 2) the active schedule.
+func (c *Client) ActivateProgram(ctx context.Context, program int) error {
+	if _, err := programURI(program); err != nil {
+		return err
+	}
+
+	if err := c.Put(ctx, types.URIActiveProgram, map[string]interface{}{"value": program}); err != nil {
+		return fmt.Errorf("failed to activate program %d: %w", program, err)
+	}
+
+	return nil
+}
+
+// AdjustCurrentSwitchpoint changes the temperature of the switchpoint in effect in the active
+// program, so the heating follows the new temperature until the next switchpoint without
+// leaving clock mode as SetTemperature does. The switchpoint is the one the device reports in
+// Status.CurrentSwitchpoint (an index into the active program); a switchpoint that refers to
+// a preset gets an absolute temperature instead. The change is permanent: the switchpoint keeps
+// the temperature on the following weeks too.
+// The temperature is interpreted in Config.TemperatureUnit and snapped like SetTemperature.
+// It returns ErrNotClockMode if the heating is in manual mode.
+// Retries of the sub-requests share one RetryBudget of Config.MaxRetries, unless ctx already carries one.
+func (c *Client) AdjustCurrentSwitchpoint(ctx context.Context, temperature float64) error {
+	celsius, err := c.snapToStep(c.config.TemperatureUnit.ToCelsius(temperature))
+	if err != nil {
+		return err
+	}
+
+	ctx = c.ensureRetryBudget(ensureRequestID(ctx), 4)
+
+	data, err := c.Get(ctx, types.URIStatus)
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected status response type: %T", data)
+	}
+	value, ok := dataMap["value"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("status response missing 'value' field")
+	}
+	status := types.ParseStatus(value)
+
+	if status.UserMode != types.UserModeClock {
+		return fmt.Errorf("adjust current switchpoint (user mode %q): %w", status.UserMode, ErrNotClockMode)
+	}
+
+	active, err := c.ActiveProgram(ctx)
+	if err != nil {
+		return err
+	}
+	program, err := c.readProgram(ctx, active, true)
+	if err != nil {
+		return err
+	}
+
+	index := status.CurrentSwitchpoint
+	if index < 0 || index >= len(program.Switchpoints) {
+		return fmt.Errorf("current switchpoint %d not in program %d with %d switchpoints",
+			index, active, len(program.Switchpoints))
+	}
+
+	sp := &program.Switchpoints[index]
+	c.log(ctx).Debug("adjusting current switchpoint",
+		"program", active, "index", index, "day", sp.DayOfWeek, "time", sp.Time, "temperature", celsius)
+	sp.Temperature = celsius
+	sp.Preset = ""
+
+	return c.setProgramCelsius(ctx, active, program)
+}
+
+// ResolvedSchedule returns the switchpoints of the active program ordered by day of week
+// (Sunday first) and time, with switchpoints that refer to a preset resolved to the
+// preset's current temperature. Temperatures are in Config.TemperatureUnit.
+func (c *Client) ResolvedSchedule(ctx context.Context) ([]types.ResolvedSwitchpoint, error) {
+	ctx = ensureRequestID(ctx)
+
+	active, err := c.ActiveProgram(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := c.readProgram(ctx, active, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var presets *types.Presets
+	resolved := make([]types.ResolvedSwitchpoint, 0, len(program.Switchpoints))
+	for _, sp := range program.Switchpoints {
+		r := types.ResolvedSwitchpoint{
+			DayOfWeek:   sp.DayOfWeek,
+			Time:        sp.Time,
+			Temperature: c.config.TemperatureUnit.FromCelsius(sp.Temperature),
+			Preset:      sp.Preset,
+		}
+
+		if sp.Preset != "" {
+			if presets == nil {
+				if presets, err = c.GetTemperaturePresets(ctx); err != nil {
+					return nil, fmt.Errorf("failed to resolve switchpoint presets: %w", err)
+				}
+			}
+			switch sp.Preset {
+			case types.PresetComfort:
+				r.Temperature = presets.Comfort
+			case types.PresetEco:
+				r.Temperature = presets.Eco
+			}
+		}
+
+		resolved = append(resolved, r)
+	}
+
+	// Times are zero-padded HH:MM, so they sort as strings.
+	sort.SliceStable(resolved, func(i, j int) bool {
+		if resolved[i].DayOfWeek != resolved[j].DayOfWeek {
+			return resolved[i].DayOfWeek < resolved[j].DayOfWeek
+		}
+		return resolved[i].Time < resolved[j].Time
+	})
+
+	return resolved, nil
+}
+
+// parseSwitchpoint converts a backend switchpoint ({"d":"Mo","t":420,"T":21}), where t is minutes after midnight.
+func parseSwitchpoint(m map[string]interface{}) (types.ProgramSwitchpoint, error) {
+	day, _ := types.LookupString(m, "d")
+	dow := -1
+	for i, d := range deviceDays {
+		if d == day {
+			dow = i
+			break
+		}
+	}
+	if dow < 0 {
+		return types.ProgramSwitchpoint{}, fmt.Errorf("unknown switchpoint day %q", day)
+	}
+
+	minutes, _ := types.LookupInt(m, "t")
+
+	sp := types.ProgramSwitchpoint{
+		DayOfWeek: dow,
+		Time:      fmt.Sprintf("%02d:%02d", minutes/60, minutes%60),
+	}
+
+	// "T" is either a temperature or the name of a temperature level.
+	if level, ok := m["T"].(string); ok {
+		if _, isNumber := types.LookupFloat(m, "T"); !isNumber {
+			preset, ok := levelPresets[level]
+			if !ok {
+				return types.ProgramSwitchpoint{}, fmt.Errorf("unknown switchpoint temperature level %q", level)
+			}
+			sp.Preset = preset
+			return sp, nil
+		}
+	}
+	sp.Temperature, _ = types.LookupFloat(m, "T")
+
+	return sp, nil
+}
+
+// levelPresets maps the temperature level names used in program switchpoints to presets.
+var levelPresets = map[string]string{
+	"comfort":  types.PresetComfort,
+	"comfort2": types.PresetComfort,
+	"eco":      types.PresetEco,
+}
+
+// presetLevels maps presets to the level names written in program switchpoints.
+var presetLevels = map[string]string{
+	types.PresetComfort: "comfort2",
+	types.PresetEco:     "eco",
+}
+
+func formatSwitchpoint(sp types.ProgramSwitchpoint) map[string]interface{} {
+	minutes, _ := sp.Minutes()
+	entry := map[string]interface{}{
+		"d": deviceDays[sp.DayOfWeek],
+		"t": minutes,
+		"T": sp.Temperature,
+	}
+	if level, ok := presetLevels[sp.Preset]; ok {
+		entry["T"] = level
+	}
+	return entry
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestResolvedSchedule(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIActiveProgram, 1)
+	d.setValue(types.URIProgram1, []interface{}{
+		map[string]interface{}{"d": "Mo", "t": 1350, "T": "eco"},
+		map[string]interface{}{"d": "Mo", "t": 390, "T": "comfort2"},
+		map[string]interface{}{"d": "Su", "t": 480, "T": 20.5},
+	})
+	d.setValue(types.URIProgram2, []interface{}{})
+	d.setValue(types.URIPresetComfort, 21.5)
+	d.setValue(types.URIPresetEco, 16.0)
+	d.setValue(types.URIManualSetpoint, 19.0)
+
+	got, err := c.ResolvedSchedule(t.Context())
+	if err != nil {
+		t.Fatalf("ResolvedSchedule failed: %v", err)
+	}
+
+	want := []types.ResolvedSwitchpoint{
+		{DayOfWeek: 0, Time: "08:00", Temperature: 20.5},
+		{DayOfWeek: 1, Time: "06:30", Temperature: 21.5, Preset: types.PresetComfort},
+		{DayOfWeek: 1, Time: "22:30", Temperature: 16, Preset: types.PresetEco},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolvedSchedule =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSwitchpointPresetRoundTrip(t *testing.T) {
+	sp, err := parseSwitchpoint(map[string]interface{}{"d": "Tu", "t": 420.0, "T": "comfort2"})
+	if err != nil {
+		t.Fatalf("parseSwitchpoint failed: %v", err)
+	}
+	if sp.Preset != types.PresetComfort || sp.Temperature != 0 {
+		t.Errorf("Expected a comfort preset reference, got %+v", sp)
+	}
+	if entry := formatSwitchpoint(sp); entry["T"] != "comfort2" {
+		t.Errorf("Expected the level name to be written back, got %v", entry["T"])
+	}
+
+	if _, err := parseSwitchpoint(map[string]interface{}{"d": "Tu", "t": 420.0, "T": "party"}); err == nil {
+		t.Error("Expected an error for an unknown temperature level")
+	}
+	if sp, err := parseSwitchpoint(map[string]interface{}{"d": "Tu", "t": 420.0, "T": "19.5"}); err != nil || sp.Temperature != 19.5 {
+		t.Errorf("Expected a numeric string temperature, got %+v (err %v)", sp, err)
+	}
+}
+
+func TestAdjustCurrentSwitchpoint(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock", "CSP": "1"})
+	d.setValue(types.URIActiveProgram, 2)
+	d.setValue(types.URIProgram2, []interface{}{
+		map[string]interface{}{"d": "Mo", "t": 390, "T": 21},
+		map[string]interface{}{"d": "Mo", "t": 1350, "T": "eco"},
+	})
+
+	if err := c.AdjustCurrentSwitchpoint(t.Context(), 17.5); err != nil {
+		t.Fatalf("AdjustCurrentSwitchpoint failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 1 || puts[0].URI != types.URIProgram2 {
+		t.Fatalf("Expected only the active program to be written, got %+v", puts)
+	}
+	want := `{"value":[{"T":21,"d":"Mo","t":390},{"T":17.5,"d":"Mo","t":1350}]}`
+	if puts[0].Body != want {
+		t.Errorf("Program PUT = %s, want %s", puts[0].Body, want)
+	}
+}
+
+func TestProgramFahrenheit(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.TemperatureUnit = types.Fahrenheit
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "clock", "CSP": "0"})
+	d.setValue(types.URIActiveProgram, 1)
+	d.setValue(types.URIProgram1, []interface{}{
+		map[string]interface{}{"d": "Mo", "t": 390, "T": 21},
+		map[string]interface{}{"d": "Mo", "t": 1350, "T": "eco"},
+	})
+
+	p, err := c.Program(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("Program failed: %v", err)
+	}
+	if p.Switchpoints[0].Temperature != 69.8 || p.Switchpoints[1].Preset != types.PresetEco || p.TemperatureUnit != types.Fahrenheit {
+		t.Errorf("Expected Fahrenheit switchpoints, got %+v", p)
+	}
+
+	// Writing the program back sends the device values again.
+	if err := c.SetProgram(t.Context(), 1, p); err != nil {
+		t.Fatalf("SetProgram failed: %v", err)
+	}
+	// Adjusting converts the new temperature once; the others stay as read.
+	if err := c.AdjustCurrentSwitchpoint(t.Context(), 64.4); err != nil {
+		t.Fatalf("AdjustCurrentSwitchpoint failed: %v", err)
+	}
+
+	puts := d.requestsFor("PUT")
+	if len(puts) != 2 {
+		t.Fatalf("Expected two program writes, got %+v", puts)
+	}
+	for i, want := range []string{
+		`{"value":[{"T":21,"d":"Mo","t":390},{"T":"eco","d":"Mo","t":1350}]}`,
+		`{"value":[{"T":18,"d":"Mo","t":390},{"T":"eco","d":"Mo","t":1350}]}`,
+	} {
+		if puts[i].Body != want {
+			t.Errorf("Program PUT %d = %s, want %s", i, puts[i].Body, want)
+		}
+	}
+}
+
+func TestAdjustCurrentSwitchpointManualMode(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"UMD": "manual", "CSP": "0"})
+
+	if err := c.AdjustCurrentSwitchpoint(t.Context(), 20); !errors.Is(err, ErrNotClockMode) {
+		t.Fatalf("Expected ErrNotClockMode, got %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 0 {
+		t.Errorf("Expected no writes in manual mode, got %+v", puts)
+	}
+}
+
+func TestAdjustCurrentSwitchpointMissingValue(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set(types.URIStatus, `{"id":"/ecus/rrc/uiStatus"}`)
+
+	err := c.AdjustCurrentSwitchpoint(t.Context(), 20)
+	if err == nil || errors.Is(err, ErrNotClockMode) || !strings.Contains(err.Error(), "missing 'value'") {
+		t.Fatalf("Expected a missing value error, got %v", err)
+	}
+}
@@ -2,14 +2,40 @@ package client
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrQueueStopped is returned by Submit once the queue has been closed.
+var ErrQueueStopped = errors.New("queue is stopped")
+
 type requestItem struct {
 	ctx      context.Context
 	execute  func() (interface{}, error)
 	resultCh chan requestResult
+
+	enqueued time.Time
+	// started is set by the worker when execute is called.
+	started *atomic.Pointer[time.Time]
+}
+
+// QueueTiming splits the duration of a submitted request into the time spent waiting
+// in the queue and the time spent executing.
+type QueueTiming struct {
+	Wait time.Duration
+	Run  time.Duration
+}
+
+// timing returns the durations of req so far. A request that never started counts as waiting throughout.
+func (req requestItem) timing() QueueTiming {
+	now := time.Now()
+	started := req.started.Load()
+	if started == nil {
+		return QueueTiming{Wait: now.Sub(req.enqueued)}
+	}
+	return QueueTiming{Wait: started.Sub(req.enqueued), Run: now.Sub(*started)}
 }
 
 type requestResult struct {
@@ -24,6 +50,9 @@ type RequestQueue struct {
 	stopCh    chan struct{}
 	wg        sync.WaitGroup
 	once      sync.Once
+
+	// depth counts requests submitted but not yet picked up by the worker.
+	depth atomic.Int64
 }
 
 // NewRequestQueue creates and starts a new request queue with background worker.
@@ -45,44 +74,105 @@ func (q *RequestQueue) worker() {
 	for {
 		select {
 		case <-q.stopCh:
+			q.rejectQueued()
 			return
 		case req := <-q.requestCh:
-			value, err := req.execute()
-
+			q.depth.Add(-1)
 			select {
-			case req.resultCh <- requestResult{value: value, err: err}:
-			case <-req.ctx.Done():
+			case <-q.stopCh:
+				// Close raced with this request; do not start new work.
+				req.resultCh <- requestResult{err: ErrQueueStopped}
+				q.rejectQueued()
+				return
+			default:
+			}
+
+			if req.ctx.Err() != nil {
+				// The caller gave up while the request was queued.
+				req.resultCh <- requestResult{err: req.ctx.Err()}
+				continue
 			}
+
+			started := time.Now()
+			req.started.Store(&started)
+			value, err := req.execute()
+
+			// resultCh is buffered, so this never blocks even if the caller is gone.
+			req.resultCh <- requestResult{value: value, err: err}
 		}
 	}
 }
 
-// Submit queues a request for execution and blocks until it completes or the context is cancelled.
+// rejectQueued fails every request still waiting in the queue with ErrQueueStopped.
+func (q *RequestQueue) rejectQueued() {
+	for {
+		select {
+		case req := <-q.requestCh:
+			q.depth.Add(-1)
+			req.resultCh <- requestResult{err: ErrQueueStopped}
+		default:
+			return
+		}
+	}
+}
+
+// Submit queues a request for execution and blocks until it completes, the context is
+// cancelled or the queue is closed. Requests still queued when the queue is closed are
+// not executed and return ErrQueueStopped.
 func (q *RequestQueue) Submit(ctx context.Context, fn func() (interface{}, error)) (interface{}, error) {
+	value, _, err := q.SubmitTimed(ctx, fn)
+	return value, err
+}
+
+// SubmitTimed is like Submit but also reports how long the request waited in the queue
+// and how long it ran, including for requests that fail or are abandoned.
+func (q *RequestQueue) SubmitTimed(ctx context.Context, fn func() (interface{}, error)) (interface{}, QueueTiming, error) {
 	resultCh := make(chan requestResult, 1)
 
 	req := requestItem{
 		ctx:      ctx,
 		execute:  fn,
 		resultCh: resultCh,
+		enqueued: time.Now(),
+		started:  new(atomic.Pointer[time.Time]),
 	}
 
+	q.depth.Add(1)
 	select {
 	case q.requestCh <- req:
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		q.depth.Add(-1)
+		return nil, req.timing(), ctx.Err()
 	case <-q.stopCh:
-		return nil, fmt.Errorf("queue is stopped")
+		q.depth.Add(-1)
+		return nil, req.timing(), ErrQueueStopped
 	}
 
 	select {
 	case result := <-resultCh:
-		return result.value, result.err
+		return result.value, req.timing(), result.err
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, req.timing(), ctx.Err()
+	case <-q.stopCh:
+		// The worker answers the running request and rejects queued ones before exiting.
+		q.wg.Wait()
+		select {
+		case result := <-resultCh:
+			return result.value, req.timing(), result.err
+		default:
+			// Enqueued after the worker drained the queue.
+			return nil, req.timing(), ErrQueueStopped
+		}
 	}
 }
 
+// Depth returns the number of requests waiting for their turn, not counting the one
+// running. A depth that keeps growing means requests are submitted faster than the
+// backend answers them. Requests whose caller gave up count until the worker skips them.
+func (q *RequestQueue) Depth() int {
+	return int(q.depth.Load())
+}
+
 // Close gracefully shuts down the queue worker.
 func (q *RequestQueue) Close() {
 	q.once.Do(func() {
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func TestQueueCloseUnblocksSubmit(t *testing.T) {
+	ignore := goleak.IgnoreCurrent()
+	q := NewRequestQueue()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+
+	// The first request occupies the worker; the rest stay queued.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := q.Submit(context.Background(), func() (interface{}, error) {
+			close(started)
+			<-release
+			return "done", nil
+		})
+		errs <- err
+	}()
+	<-started
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := q.Submit(context.Background(), func() (interface{}, error) {
+				t.Error("Queued request executed after Close")
+				return nil, nil
+			})
+			errs <- err
+		}()
+	}
+
+	// Give the submitters time to park on the queue.
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		q.Close()
+		close(closed)
+	}()
+	// Let the running request finish only once Close has signalled the worker.
+	<-q.stopCh
+	close(release)
+	<-closed
+
+	wg.Wait()
+	close(errs)
+
+	var stopped, succeeded int
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrQueueStopped):
+			stopped++
+		default:
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if succeeded != 1 || stopped != 5 {
+		t.Errorf("Expected 1 completed and 5 stopped requests, got %d and %d", succeeded, stopped)
+	}
+
+	if _, err := q.Submit(context.Background(), func() (interface{}, error) { return nil, nil }); !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("Expected ErrQueueStopped after Close, got %v", err)
+	}
+
+	goleak.VerifyNone(t, ignore)
+}
+
+func TestClientCloseCleansPendingRequests(t *testing.T) {
+	ignore := goleak.IgnoreCurrent()
+	ft := newFakeTransport()
+	c := newTestClient(t, ft)
+	c.config.RetryTimeout = time.Minute
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.Get(context.Background(), "/never/answered")
+		errCh <- err
+	}()
+
+	// Wait for the GET to be sent and parked on the response.
+	deadline := time.Now().Add(time.Second)
+	for len(ft.sentChats()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("GET was not sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = c.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Expected ErrClientClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GET did not return after Close")
+	}
+
+	c.pendingMu.RLock()
+	pending := len(c.pendingRequests) + len(c.pendingErrors)
+	c.pendingMu.RUnlock()
+	if pending != 0 {
+		t.Errorf("Expected no pending request entries, got %d", pending)
+	}
+
+	goleak.VerifyNone(t, ignore)
+}
+
+func TestQueueDepth(t *testing.T) {
+	q := NewRequestQueue()
+	defer q.Close()
+
+	release := make(chan struct{})
+	running := make(chan struct{}, 1)
+	blocked := func() (interface{}, error) {
+		select {
+		case running <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	submit := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = q.Submit(t.Context(), blocked)
+		}()
+	}
+
+	submit()
+	<-running
+	for range 3 {
+		submit()
+	}
+
+	waitForDepth := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for q.Depth() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Depth = %d, want %d", q.Depth(), want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// One request runs, three wait behind it.
+	waitForDepth(3)
+
+	close(release)
+	wg.Wait()
+	waitForDepth(0)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// quickStatusTTL is how long QuickStatus answers from its cache. It is short because
+// the indoor temperature changes continuously; the point is to absorb bursts of polls.
+const quickStatusTTL = 10 * time.Second
+
+// cachedQuickStatus is the last QuickStatus result, in Celsius.
+type cachedQuickStatus struct {
+	status types.QuickStatus
+	at     time.Time
+}
+
+// forgetQuickStatus invalidates the cached QuickStatus, e.g. after a write.
+func (c *Client) forgetQuickStatus() {
+	c.quickStatus.Store(nil)
+}
+
+// QuickStatus returns the indoor temperature, setpoint and user mode of the default
+// heating circuit. It reads uiStatus like Status but decodes only these fields, and
+// repeated calls within a few seconds are answered from a cache without a request.
+// The cache is dropped on (re)connect and by every PUT.
+func (c *Client) QuickStatus(ctx context.Context) (*types.QuickStatus, error) {
+	if cached := c.quickStatus.Load(); cached != nil && c.now().Sub(cached.at) < quickStatusTTL {
+		quick := cached.status
+		return quick.InUnit(c.config.TemperatureUnit), nil
+	}
+
+	quick, err := c.fetchQuickStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return quick.InUnit(c.config.TemperatureUnit), nil
+}
+
+// fetchQuickStatus reads uiStatus without consulting the cache and stores the result
+// in it. The returned temperatures are in Celsius.
+func (c *Client) fetchQuickStatus(ctx context.Context) (*types.QuickStatus, error) {
+	statusData, err := c.Get(ctx, types.URIStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	statusMap, ok := statusData.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected status response type: %T", statusData)
+	}
+
+	valueMap, ok := statusMap["value"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("status response missing 'value' field")
+	}
+
+	quick := types.ParseQuickStatus(valueMap)
+	c.quickStatus.Store(&cachedQuickStatus{status: *quick, at: c.now()})
+	c.rememberUserMode(quick.UserMode)
+
+	return quick, nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// maxRecordingPages bounds paging in case the device never returns an empty page.
+const maxRecordingPages = 100
+
+// GetRecordings reads all pages of a recordings resource (e.g. types.URIGasUsage) and
+// returns the entries oldest first.
+//
+// Pages are addressed as resource?page=N starting at 1. Each page holds a fixed number
+// of daily slots; unused slots carry an invalid date. Paging stops at the first page
+// without valid entries, or at an error after the first page.
+func (c *Client) GetRecordings(ctx context.Context, resource string) ([]types.Recording, error) {
+	ctx = ensureRequestID(ctx)
+
+	var recordings []types.Recording
+
+	for page := 1; page <= maxRecordingPages; page++ {
+		data, err := c.Get(ctx, fmt.Sprintf("%s?page=%d", resource, page))
+		if err != nil {
+			if page == 1 {
+				return nil, fmt.Errorf("failed to get recordings: %w", err)
+			}
+			c.log(ctx).Debug("stopping recordings paging on error", "resource", resource, "page", page, "error", err)
+			break
+		}
+
+		p, err := types.ParseRecordingPage(data)
+		if err != nil {
+			return nil, err
+		}
+		if p.Empty() {
+			break
+		}
+
+		recordings = append(recordings, p.Entries...)
+	}
+
+	sort.SliceStable(recordings, func(i, j int) bool {
+		return recordings[i].Date.Before(recordings[j].Date)
+	})
+
+	return recordings, nil
+}
+
+// OutdoorTemperatureHistory returns the recorded daily average outdoor temperatures, oldest first,
+// in Config.TemperatureUnit. Days without a recording are omitted.
+func (c *Client) OutdoorTemperatureHistory(ctx context.Context) ([]types.TempSample, error) {
+	recordings, err := c.GetRecordings(ctx, types.URIGasUsage)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]types.TempSample, 0, len(recordings))
+	for _, rec := range recordings {
+		temp, ok := rec.Values["T"]
+		if !ok {
+			continue
+		}
+		samples = append(samples, types.TempSample{
+			Time:        rec.Date,
+			Temperature: c.config.TemperatureUnit.FromCelsius(temp),
+		})
+	}
+
+	return samples, nil
+}
+
+// ElectricityUsage returns the recorded daily electrical consumption, oldest first.
+// It returns ErrUnsupported if the appliance has no electricity recordings; an empty
+// slice means the resource exists but holds no data yet.
+func (c *Client) ElectricityUsage(ctx context.Context) ([]types.EnergyRecord, error) {
+	recordings, err := c.GetRecordings(ctx, types.URIElectricityUsage)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+			return nil, fmt.Errorf("electricity usage: %w", ErrUnsupported)
+		}
+		return nil, err
+	}
+
+	records := make([]types.EnergyRecord, 0, len(recordings))
+	for _, rec := range recordings {
+		kwh, ok := rec.Values["el"]
+		if !ok {
+			continue
+		}
+		records = append(records, types.EnergyRecord{
+			Date: rec.Date,
+			KWh:  kwh,
+		})
+	}
+
+	return records, nil
+}
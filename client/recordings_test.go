@@ -0,0 +1,133 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestOutdoorTemperatureHistory(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	d.set(types.URIGasUsage+"?page=1", `{"id":"/ecus/rrc/recordings/gasusage","value":[
+		{"d":"03-01-2024","hw":1.0,"ch":5.0,"T":4.5},
+		{"d":"01-01-2024","hw":1.1,"ch":6.0,"T":2.0},
+		{"d":"255-256-65535","hw":0,"ch":0,"T":0}
+	]}`)
+	d.set(types.URIGasUsage+"?page=2", `{"id":"/ecus/rrc/recordings/gasusage","value":[
+		{"d":"02-01-2024","hw":0.9,"ch":5.5,"T":-1.5}
+	]}`)
+	d.set(types.URIGasUsage+"?page=3", `{"id":"/ecus/rrc/recordings/gasusage","value":[
+		{"d":"255-256-65535","hw":0,"ch":0,"T":0}
+	]}`)
+
+	samples, err := c.OutdoorTemperatureHistory(t.Context())
+	if err != nil {
+		t.Fatalf("OutdoorTemperatureHistory failed: %v", err)
+	}
+
+	want := []types.TempSample{
+		{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Temperature: 2.0},
+		{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Temperature: -1.5},
+		{Time: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Temperature: 4.5},
+	}
+
+	if len(samples) != len(want) {
+		t.Fatalf("Expected %d samples, got %d: %+v", len(want), len(samples), samples)
+	}
+	for i := range want {
+		if !samples[i].Time.Equal(want[i].Time) || samples[i].Temperature != want[i].Temperature {
+			t.Errorf("Sample %d: expected %+v, got %+v", i, want[i], samples[i])
+		}
+	}
+}
+
+func TestOutdoorTemperatureHistoryFahrenheit(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.TemperatureUnit = types.Fahrenheit
+	d.set(types.URIGasUsage+"?page=1", `{"id":"/ecus/rrc/recordings/gasusage","value":[
+		{"d":"01-01-2024","hw":1.1,"ch":6.0,"T":-1.5},
+		{"d":"255-256-65535","hw":0,"ch":0,"T":0}
+	]}`)
+
+	samples, err := c.OutdoorTemperatureHistory(t.Context())
+	if err != nil {
+		t.Fatalf("OutdoorTemperatureHistory failed: %v", err)
+	}
+	if len(samples) != 1 || samples[0].Temperature != 29.3 {
+		t.Errorf("Expected one sample of 29.3 (Fahrenheit), got %+v", samples)
+	}
+}
+
+func TestGetRecordingsPaging(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	resource := "/ecus/rrc/recordings/test"
+	d.set(resource+"?page=1", `{"value":[{"d":"01-02-2024","x":1}]}`)
+	d.set(resource+"?page=2", `{"value":[{"d":"02-02-2024","x":2}]}`)
+	d.set(resource+"?page=3", `{"value":[{"d":"03-02-2024","x":3},{"d":"255-256-65535","x":0}]}`)
+	d.set(resource+"?page=4", `{"value":[]}`)
+	d.set(resource+"?page=5", `{"value":[{"d":"04-02-2024","x":4}]}`)
+
+	recordings, err := c.GetRecordings(t.Context(), resource)
+	if err != nil {
+		t.Fatalf("GetRecordings failed: %v", err)
+	}
+
+	if len(recordings) != 3 {
+		t.Fatalf("Expected 3 recordings, got %d: %+v", len(recordings), recordings)
+	}
+	for i, rec := range recordings {
+		if rec.Values["x"] != float64(i+1) || rec.Date.Day() != i+1 {
+			t.Errorf("Recording %d: unexpected %+v", i, rec)
+		}
+	}
+
+	gets := d.requestsFor("GET")
+	if len(gets) != 4 || gets[3].URI != resource+"?page=4" {
+		t.Errorf("Expected paging to stop at the empty page 4, got %+v", gets)
+	}
+}
+
+func TestElectricityUsage(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	d.set(types.URIElectricityUsage+"?page=1", `{"id":"/ecus/rrc/recordings/electricityusage","value":[
+		{"d":"02-01-2024","el":3.2},
+		{"d":"01-01-2024","el":2.5},
+		{"d":"255-256-65535","el":0}
+	]}`)
+	d.set(types.URIElectricityUsage+"?page=2", `{"value":[]}`)
+
+	records, err := c.ElectricityUsage(t.Context())
+	if err != nil {
+		t.Fatalf("ElectricityUsage failed: %v", err)
+	}
+
+	want := []types.EnergyRecord{
+		{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), KWh: 2.5},
+		{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), KWh: 3.2},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Expected %d records, got %+v", len(want), records)
+	}
+	for i := range want {
+		if !records[i].Date.Equal(want[i].Date) || records[i].KWh != want[i].KWh {
+			t.Errorf("Record %d: expected %+v, got %+v", i, want[i], records[i])
+		}
+	}
+}
+
+func TestElectricityUsageUnsupported(t *testing.T) {
+	c, _ := newFakeDevice(t)
+
+	records, err := c.ElectricityUsage(t.Context())
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Expected ErrUnsupported, got %v", err)
+	}
+	if records != nil {
+		t.Errorf("Expected nil records, got %+v", records)
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// maxResourceDepth bounds how deep ListResources follows references below the root.
+const maxResourceDepth = 8
+
+// ListResources walks the resource tree below root and returns the URIs of all
+// discovered resources, sorted. Directory resources answer with
+// {"type": "refEnum", "references": [{"id": ...}]}; their references are followed
+// up to maxResourceDepth levels, and each URI is visited once so cyclic references
+// terminate. Children that cannot be read are still listed but not descended into.
+func (c *Client) ListResources(ctx context.Context, root string) ([]string, error) {
+	ctx = ensureRequestID(ctx)
+
+	data, err := c.Get(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", root, err)
+	}
+
+	visited := map[string]bool{root: true}
+	var found []string
+
+	var walk func(data interface{}, depth int)
+	walk = func(data interface{}, depth int) {
+		for _, ref := range resourceReferences(data) {
+			if visited[ref] {
+				continue
+			}
+			visited[ref] = true
+			found = append(found, ref)
+
+			if depth >= maxResourceDepth {
+				c.log(ctx).Debug("resource depth limit reached", "uri", ref)
+				continue
+			}
+
+			child, err := c.Get(ctx, ref)
+			if err != nil {
+				c.log(ctx).Debug("skipping unreadable resource", "uri", ref, "error", err)
+				continue
+			}
+			walk(child, depth+1)
+		}
+	}
+	walk(data, 1)
+
+	sort.Strings(found)
+	return found, nil
+}
+
+// resourceReferences returns the child ids of a refEnum response, or nil for other resources.
+func resourceReferences(data interface{}) []string {
+	doc, ok := data.(map[string]interface{})
+	if !ok || doc["type"] != "refEnum" {
+		return nil
+	}
+
+	refs, _ := doc["references"].([]interface{})
+	ids := make([]string, 0, len(refs))
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, ok := ref["id"].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestListResources(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	d.set("/system", `{"id":"/system","type":"refEnum","references":[
+		{"id":"/system/appliance","uri":"http://127.0.0.1/system/appliance"},
+		{"id":"/system/sensors","uri":"http://127.0.0.1/system/sensors"},
+		{"id":"/system/locked","uri":"http://127.0.0.1/system/locked"}
+	]}`)
+	d.set("/system/appliance", `{"id":"/system/appliance","type":"refEnum","references":[
+		{"id":"/system/appliance/systemPressure"},
+		{"id":"/system"}
+	]}`)
+	d.set("/system/appliance/systemPressure", `{"id":"/system/appliance/systemPressure","type":"floatValue","value":1.6}`)
+	d.set("/system/sensors", `{"id":"/system/sensors","type":"refEnum","references":[
+		{"id":"/system/sensors/temperatures"}
+	]}`)
+	d.set("/system/sensors/temperatures", `{"id":"/system/sensors/temperatures","type":"refEnum","references":[
+		{"id":"/system/sensors/temperatures/outdoor_t1"},
+		{"id":"/system/appliance"}
+	]}`)
+	d.set("/system/sensors/temperatures/outdoor_t1", `{"id":"/system/sensors/temperatures/outdoor_t1","type":"floatValue","value":7.5}`)
+	// /system/locked is not set and answers 404.
+
+	got, err := c.ListResources(t.Context(), "/system")
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+
+	want := []string{
+		"/system/appliance",
+		"/system/appliance/systemPressure",
+		"/system/locked",
+		"/system/sensors",
+		"/system/sensors/temperatures",
+		"/system/sensors/temperatures/outdoor_t1",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListResources = %v, want %v", got, want)
+	}
+
+	// Every resource is fetched at most once despite the cycles.
+	seen := map[string]int{}
+	for _, r := range d.requestsFor("GET") {
+		seen[r.URI]++
+		if seen[r.URI] > 1 {
+			t.Errorf("Resource %s fetched more than once", r.URI)
+		}
+	}
+}
+
+func TestListResourcesDepthLimit(t *testing.T) {
+	c, d := newFakeDevice(t)
+
+	uri := "/deep"
+	for i := 0; i < maxResourceDepth+3; i++ {
+		child := uri + "/x"
+		d.set(uri, `{"id":"`+uri+`","type":"refEnum","references":[{"id":"`+child+`"}]}`)
+		uri = child
+	}
+
+	got, err := c.ListResources(t.Context(), "/deep")
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+	if len(got) != maxResourceDepth {
+		t.Errorf("Expected %d resources within the depth limit, got %d", maxResourceDepth, len(got))
+	}
+}
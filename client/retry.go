@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// DefaultRetryPolicy is the retry policy of Put when Config.RetryPolicy is nil. It retries
+// attempts that timed out, including errors whose text reports a "timeout" such as an XMPP
+// remote-server-timeout, and nothing else: a 4xx means the request itself is wrong, and
+// 5xx responses, including a 504 Gateway Timeout, usually point at the API or the boiler.
+func DefaultRetryPolicy(err error, attempt int) bool {
+	return errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout")
+}
+
+// defaultGetRetryPolicy is the retry policy of Get when Config.RetryPolicy is nil. It only
+// retries attempts that got no response in time; an error response is returned as is.
+func defaultGetRetryPolicy(err error, attempt int) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// shouldRetry reports whether a request with the given method whose attempt-th attempt failed
+// with err is retried, according to Config.RetryPolicy. MaxRetries and the retry budget still
+// bound the attempts.
+func (c *Client) shouldRetry(method string, err error, attempt int) bool {
+	if policy := c.config.RetryPolicy; policy != nil {
+		return policy(err, attempt)
+	}
+	if method == "GET" {
+		return defaultGetRetryPolicy(err, attempt)
+	}
+	return DefaultRetryPolicy(err, attempt)
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// retryStatus retries API errors with the given status code, and nothing else.
+func retryStatus(code int) func(error, int) bool {
+	return func(err error, attempt int) bool {
+		var apiErr *APIError
+		return errors.As(err, &apiErr) && apiErr.StatusCode == code
+	}
+}
+
+func TestRetryPolicyRetries503(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.RetryTimeout = 10 * time.Millisecond
+	c.config.RetryPolicy = retryStatus(503)
+
+	d.setValue(types.URIPressure, 1.5)
+	d.queue(types.URIPressure, fakeResponse{StatusCode: 503, Status: "Service Unavailable"})
+
+	if _, err := c.Get(t.Context(), types.URIPressure); err != nil {
+		t.Fatalf("Expected the 503 to be retried, got %v", err)
+	}
+	if gets := d.requestsFor("GET"); len(gets) != 2 {
+		t.Errorf("Expected 2 GET attempts, got %d", len(gets))
+	}
+
+	d.queue(types.URIManualSetpoint, fakeResponse{StatusCode: 503, Status: "Service Unavailable"})
+	if err := c.Put(t.Context(), types.URIManualSetpoint, map[string]interface{}{"value": 20}); err != nil {
+		t.Fatalf("Expected the 503 to be retried, got %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 2 {
+		t.Errorf("Expected 2 PUT attempts, got %d", len(puts))
+	}
+}
+
+func TestRetryPolicyDoesNotRetry400(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.RetryTimeout = 10 * time.Millisecond
+
+	var seen []int
+	c.config.RetryPolicy = func(err error, attempt int) bool {
+		seen = append(seen, attempt)
+		return retryStatus(503)(err, attempt)
+	}
+
+	d.queue(types.URIManualSetpoint, fakeResponse{StatusCode: 400, Status: "Bad Request"})
+	err := c.Put(t.Context(), types.URIManualSetpoint, map[string]interface{}{"value": 20})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Fatalf("Expected the 400 to be returned, got %v", err)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 1 {
+		t.Errorf("Expected a single PUT attempt, got %d", len(puts))
+	}
+	if len(seen) != 1 || seen[0] != 1 {
+		t.Errorf("Expected the policy to be asked once for attempt 1, got %v", seen)
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), true},
+		{&APIError{StatusCode: 504, Status: "Gateway Timeout"}, false},
+		{&XMPPError{Condition: "remote-server-timeout"}, true},
+		{&APIError{StatusCode: 400, Status: "Bad Request"}, false},
+		{&APIError{StatusCode: 503, Status: "Service Unavailable"}, false},
+		{ErrClientClosed, false},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultRetryPolicy(tt.err, 1); got != tt.want {
+			t.Errorf("DefaultRetryPolicy(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultRetryPolicyGet(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.RetryTimeout = 10 * time.Millisecond
+
+	// An error response answers the GET, even one reporting a timeout.
+	d.queue(types.URIPressure, fakeResponse{StatusCode: 504, Status: "Gateway Timeout"})
+	var apiErr *APIError
+	if _, err := c.Get(t.Context(), types.URIPressure); !errors.As(err, &apiErr) || apiErr.StatusCode != 504 {
+		t.Fatalf("Expected the 504 to be returned, got %v", err)
+	}
+	if gets := d.requestsFor("GET"); len(gets) != 1 {
+		t.Errorf("Expected a single GET attempt, got %d", len(gets))
+	}
+
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), true},
+		{&APIError{StatusCode: 504, Status: "Gateway Timeout"}, false},
+		{&XMPPError{Condition: "remote-server-timeout"}, false},
+	} {
+		if got := c.shouldRetry("GET", tt.err, 1); got != tt.want {
+			t.Errorf("shouldRetry(GET, %v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
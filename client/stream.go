@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// streamReconnectTimeout bounds each reconnect attempt of StreamEvents.
+	streamReconnectTimeout = 30 * time.Second
+	// maxStreamBackoff caps the wait between reconnect attempts of StreamEvents.
+	maxStreamBackoff = 30 * time.Second
+)
+
+// ConnectionState is the state reported by a connection marker of StreamEvents.
+type ConnectionState int
+
+const (
+	// ConnectionLost is reported when the connection drops, before reconnecting.
+	ConnectionLost ConnectionState = iota + 1
+	// ConnectionRestored is reported once a new connection delivers pushes again.
+	ConnectionRestored
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionLost:
+		return "lost"
+	case ConnectionRestored:
+		return "restored"
+	default:
+		return fmt.Sprintf("ConnectionState(%d)", int(s))
+	}
+}
+
+// ConnectionEvent describes a connection state change in a StreamEvents stream.
+type ConnectionEvent struct {
+	State ConnectionState
+	// Err is the error that ended the connection, for ConnectionLost.
+	Err error
+	// Attempts is the number of reconnect attempts it took, for ConnectionRestored.
+	// It is zero if the connection was restored by another caller of Reconnect.
+	Attempts int
+}
+
+// StreamOption modifies the behavior of StreamEvents.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	markers bool
+}
+
+// WithConnectionMarkers makes StreamEvents deliver a PushNotification with Connection set
+// (and URI and Data empty) whenever the connection is lost or restored.
+func WithConnectionMarkers() StreamOption {
+	return func(o *streamOptions) {
+		o.markers = true
+	}
+}
+
+// StreamEvents is like Events, but survives connection drops: when the connection is lost
+// (ErrReceiveFailed or ErrConnectionDead), it reconnects with exponential backoff starting
+// at Config.RetryTimeout, and the same channel keeps delivering push notifications from the
+// new connection. Pass WithConnectionMarkers to see the drops in the stream.
+//
+// The channel is closed when ctx is cancelled or the client is closed. Notifications sent
+// while the connection is down are lost; the backend does not replay them.
+func (c *Client) StreamEvents(ctx context.Context, opts ...StreamOption) <-chan PushNotification {
+	var o streamOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events := c.Events(ctx)
+
+	lost := make(chan connectionLoss, 1)
+	unwatch := c.watchConnectionLoss(func(loss connectionLoss) {
+		select {
+		case lost <- loss:
+		default:
+		}
+	})
+
+	out := make(chan PushNotification, eventsBuffer)
+	send := func(n PushNotification) bool {
+		select {
+		case out <- n:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(out)
+		defer cancel()
+		defer unwatch()
+
+		for {
+			select {
+			case n, ok := <-events:
+				if !ok || !send(n) {
+					return
+				}
+			case loss := <-lost:
+				if o.markers && !send(PushNotification{Connection: &ConnectionEvent{State: ConnectionLost, Err: loss.err}}) {
+					return
+				}
+
+				attempts, err := c.restoreConnection(ctx, loss)
+				if err != nil {
+					return
+				}
+				// Losses reported by the old connection while we reconnected are stale.
+				select {
+				case <-lost:
+				default:
+				}
+
+				if o.markers && !send(PushNotification{Connection: &ConnectionEvent{State: ConnectionRestored, Attempts: attempts}}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// restoreConnection reconnects until it succeeds, ctx is done or the client is closed,
+// returning the number of attempts made. It stops without reconnecting if the connection
+// that was lost has already been replaced, e.g. by another stream.
+func (c *Client) restoreConnection(ctx context.Context, loss connectionLoss) (int, error) {
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(c.streamBackoff(attempt)):
+		}
+
+		if c.connectedAt.Load() != loss.connectedAt {
+			return 0, nil
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, streamReconnectTimeout)
+		err := c.Reconnect(attemptCtx)
+		cancel()
+		if err == nil {
+			return attempt, nil
+		}
+		if errors.Is(err, ErrClientClosed) || ctx.Err() != nil {
+			return attempt, err
+		}
+		c.log(ctx).Warn("reconnect failed", "attempt", attempt, "error", err)
+	}
+}
+
+// streamBackoff returns the wait before the given (1-based) reconnect attempt.
+func (c *Client) streamBackoff(attempt int) time.Duration {
+	backoff := c.config.RetryTimeout
+	for i := 1; i < attempt && backoff < maxStreamBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxStreamBackoff)
+}
+
+// connectionLoss is an error that ended the connection established at connectedAt.
+type connectionLoss struct {
+	err         error
+	connectedAt int64
+}
+
+// watchConnectionLoss registers fn to be called when the connection is lost and returns
+// a function that removes it again. fn is called synchronously and must not block.
+func (c *Client) watchConnectionLoss(fn func(connectionLoss)) (unwatch func()) {
+	h := &fn
+
+	c.connWatchersMu.Lock()
+	defer c.connWatchersMu.Unlock()
+	c.connWatchers = append(c.connWatchers, h)
+
+	return func() {
+		c.connWatchersMu.Lock()
+		defer c.connWatchersMu.Unlock()
+		c.connWatchers = slices.DeleteFunc(c.connWatchers, func(other *func(connectionLoss)) bool {
+			return other == h
+		})
+	}
+}
+
+// notifyConnectionLoss passes err to the connection loss watchers if it means the
+// connection is gone.
+func (c *Client) notifyConnectionLoss(err error) {
+	if !errors.Is(err, ErrReceiveFailed) && !errors.Is(err, ErrConnectionDead) {
+		return
+	}
+
+	loss := connectionLoss{err: err, connectedAt: c.connectedAt.Load()}
+
+	c.connWatchersMu.Lock()
+	defer c.connWatchersMu.Unlock()
+	for _, fn := range c.connWatchers {
+		(*fn)(loss)
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	xmpp "github.com/xmppo/go-xmpp"
+)
+
+func TestStreamEventsSurvivesReconnect(t *testing.T) {
+	c, d := newFakeDevice(t)
+	c.config.RetryTimeout = 10 * time.Millisecond
+
+	dials := 0
+	c.dial = func(xmpp.Options) (transport, error) {
+		dials++
+		if dials == 1 {
+			return nil, errors.New("connection refused")
+		}
+		ft := newFakeTransport()
+		ft.onSend = d.handle
+		d.ft = ft
+		return ft, nil
+	}
+
+	stream := c.StreamEvents(t.Context(), WithConnectionMarkers())
+	next := func() PushNotification {
+		t.Helper()
+		select {
+		case n, ok := <-stream:
+			if !ok {
+				t.Fatal("Stream closed unexpectedly")
+			}
+			return n
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for the stream")
+		}
+		return PushNotification{}
+	}
+
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"id":"/before","value":1}`})
+	if n := next(); n.URI != "/before" {
+		t.Fatalf("Expected push for /before, got %+v", n)
+	}
+
+	d.ft.recvCh <- errors.New("connection reset by peer")
+
+	if n := next(); n.Connection == nil || n.Connection.State != ConnectionLost || !errors.Is(n.Connection.Err, ErrReceiveFailed) {
+		t.Fatalf("Expected a lost marker, got %+v", n)
+	}
+	n := next()
+	if n.Connection == nil || n.Connection.State != ConnectionRestored || n.Connection.Attempts != 2 {
+		t.Fatalf("Expected a restored marker after two attempts, got %+v", n)
+	}
+
+	d.reply(fakeResponse{StatusCode: 200, Status: "OK", Body: `{"id":"/after","value":2}`})
+	if n := next(); n.URI != "/after" || n.Connection != nil {
+		t.Fatalf("Expected push for /after from the new connection, got %+v", n)
+	}
+}
+
+func TestStreamEventsClosesWithClient(t *testing.T) {
+	c, _ := newFakeDevice(t)
+	stream := c.StreamEvents(t.Context())
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	select {
+	case _, ok := <-stream:
+		if ok {
+			t.Error("Expected no events after Close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to close the stream")
+	}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// SystemInfo retrieves the appliance type, fuel type and installed circuits. This tells
+// which features are meaningful: a system without a hot water circuit has no use for the
+// hot water methods. Readings the appliance does not provide are left empty.
+//
+// The installation does not change at runtime, so the first successful result is cached
+// for the lifetime of the client and later calls make no requests.
+func (c *Client) SystemInfo(ctx context.Context) (*types.SystemInfo, error) {
+	if info := c.systemInfo.Load(); info != nil {
+		return copySystemInfo(info), nil
+	}
+
+	ctx = ensureRequestID(ctx)
+
+	info := &types.SystemInfo{}
+
+	applianceType, err := c.optionalString(ctx, types.URIApplianceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appliance type: %w", err)
+	}
+	info.RawApplianceType = applianceType
+	info.ApplianceType = types.ParseApplianceType(applianceType)
+
+	if info.FuelType, err = c.optionalString(ctx, types.URIFuelType); err != nil {
+		return nil, fmt.Errorf("failed to get fuel type: %w", err)
+	}
+
+	for _, collection := range []struct {
+		uri       string
+		installed *bool
+	}{
+		{uri: types.URIHeatingCircuits},
+		{uri: types.URIDHWCircuits, installed: &info.HotWater},
+		{uri: types.URISolarCircuits, installed: &info.Solar},
+	} {
+		data, err := c.Get(ctx, collection.uri)
+		if isUnsupported(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", collection.uri, err)
+		}
+
+		refs := resourceReferences(data)
+		for _, ref := range refs {
+			info.Circuits = append(info.Circuits, path.Base(ref))
+		}
+		if collection.installed != nil {
+			*collection.installed = len(refs) > 0
+		}
+	}
+	sort.Strings(info.Circuits)
+
+	c.systemInfo.Store(info)
+
+	return copySystemInfo(info), nil
+}
+
+// copySystemInfo returns a copy of info that callers may modify without affecting the cache.
+func copySystemInfo(info *types.SystemInfo) *types.SystemInfo {
+	copied := *info
+	copied.Circuits = append([]string(nil), info.Circuits...)
+	return &copied
+}
+
+// optionalString returns the string "value" of uri, or "" if the appliance does not provide it.
+func (c *Client) optionalString(ctx context.Context, uri string) (string, error) {
+	data, err := c.Get(ctx, uri)
+	if isUnsupported(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected response type: %T", data)
+	}
+	value, _ := types.LookupString(dataMap, "value")
+	return value, nil
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestSystemInfo(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.set(types.URIApplianceType, `{"id":"/system/appliance/type","type":"stringValue","value":"CombiBoiler"}`)
+	d.set(types.URIFuelType, `{"id":"/system/appliance/fuelType","type":"stringValue","value":"natural gas"}`)
+	d.set(types.URIHeatingCircuits, `{"id":"/heatingCircuits","type":"refEnum","references":[
+		{"id":"/heatingCircuits/hc1","uri":"http://127.0.0.1/heatingCircuits/hc1"}
+	]}`)
+	d.set(types.URIDHWCircuits, `{"id":"/dhwCircuits","type":"refEnum","references":[
+		{"id":"/dhwCircuits/dhwA","uri":"http://127.0.0.1/dhwCircuits/dhwA"}
+	]}`)
+	// No solar circuits: /solarCircuits answers 404.
+
+	info, err := c.SystemInfo(t.Context())
+	if err != nil {
+		t.Fatalf("SystemInfo failed: %v", err)
+	}
+
+	if info.ApplianceType != types.ApplianceCombi || info.RawApplianceType != "CombiBoiler" {
+		t.Errorf("Expected a combi appliance, got %q (%q)", info.ApplianceType, info.RawApplianceType)
+	}
+	if info.FuelType != "natural gas" {
+		t.Errorf("Expected fuel type natural gas, got %q", info.FuelType)
+	}
+	if !slices.Equal(info.Circuits, []string{"dhwA", "hc1"}) || !info.HotWater || info.Solar {
+		t.Errorf("Unexpected circuits: %+v", info)
+	}
+
+	// The result is cached, and callers cannot modify the cached copy.
+	requests := len(d.requestLog())
+	info.Circuits[0] = "changed"
+	again, err := c.SystemInfo(t.Context())
+	if err != nil {
+		t.Fatalf("SystemInfo failed: %v", err)
+	}
+	if n := len(d.requestLog()); n != requests {
+		t.Errorf("Expected no requests for the cached result, got %d more", n-requests)
+	}
+	if again.Circuits[0] != "dhwA" {
+		t.Errorf("Expected the cache to be unaffected by callers, got %v", again.Circuits)
+	}
+}
+
+func TestSystemInfoUnsupported(t *testing.T) {
+	c, _ := newFakeDevice(t)
+
+	info, err := c.SystemInfo(t.Context())
+	if err != nil {
+		t.Fatalf("SystemInfo failed: %v", err)
+	}
+	if info.ApplianceType != types.ApplianceUnknown || info.FuelType != "" || len(info.Circuits) != 0 || info.HotWater {
+		t.Errorf("Expected an empty system info when nothing is provided, got %+v", info)
+	}
+}
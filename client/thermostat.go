@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// ThermostatState reads the room temperature, the effective setpoint (see
+// types.Status.EffectiveSetpoint) and the switching hysteresis of the thermostat.
+// If the device does not report a hysteresis, "hysteresis" is listed in Unavailable
+// and the switching thresholds are left zero.
+// Temperatures are in Config.TemperatureUnit.
+func (c *Client) ThermostatState(ctx context.Context) (*types.ThermostatState, error) {
+	ctx = c.ensureRetryBudget(ensureRequestID(ctx), 2)
+
+	status, err := c.Status(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := c.config.TemperatureUnit
+	state := &types.ThermostatState{
+		Measured:        status.InHouseTemp,
+		TemperatureUnit: status.TemperatureUnit,
+	}
+	state.Setpoint, state.Reason = status.EffectiveSetpoint()
+
+	hysteresis, err := c.getFloatValue(ctx, circuitURI(ctx, types.URIRoomHysteresis))
+	switch {
+	case isUnsupported(err):
+		state.Unavailable = append(state.Unavailable, "hysteresis")
+	case err != nil:
+		return nil, fmt.Errorf("failed to get hysteresis: %w", err)
+	default:
+		state.Hysteresis = unit.DeltaFromCelsius(hysteresis)
+		state.SwitchOn = state.Setpoint - state.Hysteresis
+		state.SwitchOff = state.Setpoint + state.Hysteresis
+	}
+
+	return state, nil
+}
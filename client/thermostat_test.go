@@ -0,0 +1,55 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kradalby/nefit-go/internal/fixtures"
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestThermostatState(t *testing.T) {
+	value, err := fixtures.Value("uistatus_clock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, value)
+	d.setValue(types.URIRoomHysteresis, 0.5)
+
+	state, err := c.ThermostatState(t.Context())
+	if err != nil {
+		t.Fatalf("ThermostatState failed: %v", err)
+	}
+	want := types.ThermostatState{
+		Measured:   20.85,
+		Setpoint:   21,
+		Reason:     types.SetpointProgram,
+		Hysteresis: 0.5,
+		SwitchOn:   20.5,
+		SwitchOff:  21.5,
+	}
+	if !reflect.DeepEqual(*state, want) {
+		t.Errorf("ThermostatState =\n%+v\nwant\n%+v", *state, want)
+	}
+}
+
+func TestThermostatStateWithoutHysteresis(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIStatus, map[string]interface{}{"IHT": "19.0", "TSP": "20.0", "UMD": "manual", "MMT": "20.5"})
+
+	state, err := c.ThermostatState(t.Context())
+	if err != nil {
+		t.Fatalf("ThermostatState failed: %v", err)
+	}
+	want := types.ThermostatState{
+		Measured:    19,
+		Setpoint:    20.5,
+		Reason:      types.SetpointManual,
+		Unavailable: []string{"hysteresis"},
+	}
+	if !reflect.DeepEqual(*state, want) {
+		t.Errorf("ThermostatState =\n%+v\nwant\n%+v", *state, want)
+	}
+}
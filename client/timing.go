@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// RequestTiming describes how long a Get or Put took, summed over all its attempts.
+type RequestTiming struct {
+	Method   string
+	URI      string
+	Attempts int
+
+	// QueueWait is the time spent waiting for earlier requests to finish.
+	QueueWait time.Duration
+	// Request is the time spent on the wire, from sending the request to its response or timeout.
+	Request time.Duration
+	// Total is the wall time of the call, including retry backoff.
+	Total time.Duration
+
+	// Err is the error returned to the caller, if any.
+	Err error
+}
+
+// TimingHook receives the timing of every completed Get and Put, e.g. to feed a metrics
+// collector. It is called synchronously, so it should return quickly.
+type TimingHook func(RequestTiming)
+
+// SetTimingHook installs fn to receive request timings. Pass nil to remove it.
+func (c *Client) SetTimingHook(fn TimingHook) {
+	if fn == nil {
+		c.timingHook.Store(nil)
+		return
+	}
+	c.timingHook.Store(&fn)
+}
+
+// requestTimer accumulates the queue timings of the attempts of one request.
+type requestTimer struct {
+	timing RequestTiming
+	start  time.Time
+}
+
+func newRequestTimer(method, uri string) *requestTimer {
+	return &requestTimer{
+		timing: RequestTiming{Method: method, URI: uri},
+		start:  time.Now(),
+	}
+}
+
+func (t *requestTimer) add(q QueueTiming) {
+	t.timing.Attempts++
+	t.timing.QueueWait += q.Wait
+	t.timing.Request += q.Run
+}
+
+// finishTimer logs the timing of the request and passes it to the timing hook.
+func (c *Client) finishTimer(ctx context.Context, t *requestTimer, err error) {
+	t.timing.Total = time.Since(t.start)
+	t.timing.Err = err
+
+	c.log(ctx).Debug("request completed",
+		"method", t.timing.Method,
+		"uri", t.timing.URI,
+		"attempts", t.timing.Attempts,
+		"queue_wait_ms", t.timing.QueueWait.Milliseconds(),
+		"request_ms", t.timing.Request.Milliseconds(),
+		"total_ms", t.timing.Total.Milliseconds(),
+		"error", err)
+
+	if fn := c.timingHook.Load(); fn != nil {
+		(*fn)(t.timing)
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestTimingHook(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIPressure, 1.6)
+
+	var mu sync.Mutex
+	var timings []RequestTiming
+	c.SetTimingHook(func(rt RequestTiming) {
+		mu.Lock()
+		defer mu.Unlock()
+		timings = append(timings, rt)
+	})
+
+	if _, err := c.Get(t.Context(), types.URIPressure); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := c.Put(t.Context(), types.URIUserMode, map[string]string{"value": "clock"}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := c.Get(t.Context(), "/missing"); err == nil {
+		t.Fatal("Expected an error for a missing resource")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(timings) != 3 {
+		t.Fatalf("Expected 3 timings, got %+v", timings)
+	}
+	want := []struct{ method, uri string }{
+		{"GET", types.URIPressure},
+		{"PUT", types.URIUserMode},
+		{"GET", "/missing"},
+	}
+	for i, rt := range timings {
+		if rt.Method != want[i].method || rt.URI != want[i].uri {
+			t.Errorf("Timing %d: expected %s %s, got %s %s", i, want[i].method, want[i].uri, rt.Method, rt.URI)
+		}
+		if rt.Attempts != 1 {
+			t.Errorf("Timing %d: expected 1 attempt, got %d", i, rt.Attempts)
+		}
+		if rt.QueueWait < 0 || rt.Request < 0 || rt.Total < rt.QueueWait+rt.Request {
+			t.Errorf("Timing %d: implausible durations %+v", i, rt)
+		}
+	}
+	if timings[0].Err != nil || timings[2].Err == nil {
+		t.Errorf("Expected the error of the failed GET only, got %v and %v", timings[0].Err, timings[2].Err)
+	}
+
+	c.SetTimingHook(nil)
+	if _, err := c.Get(t.Context(), types.URIPressure); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(timings) != 3 {
+		t.Errorf("Expected no timings after removing the hook, got %d", len(timings))
+	}
+}
+
+func TestSubmitTimedQueueWait(t *testing.T) {
+	q := NewRequestQueue()
+	defer q.Close()
+
+	release := make(chan struct{})
+	first := make(chan QueueTiming, 1)
+	go func() {
+		_, timing, _ := q.SubmitTimed(t.Context(), func() (interface{}, error) {
+			<-release
+			return nil, nil
+		})
+		first <- timing
+	}()
+
+	// Let the first request start before queueing the second behind it.
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	_, timing, err := q.SubmitTimed(t.Context(), func() (interface{}, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("SubmitTimed failed: %v", err)
+	}
+	if timing.Wait < 10*time.Millisecond {
+		t.Errorf("Expected the second request to wait behind the first, got %+v", timing)
+	}
+
+	if ft := <-first; ft.Run < 20*time.Millisecond {
+		t.Errorf("Expected the first request to run until released, got %+v", ft)
+	}
+}
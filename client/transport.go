@@ -0,0 +1,21 @@
+package client
+
+import (
+	xmpp "github.com/xmppo/go-xmpp"
+)
+
+// transport is the subset of *xmpp.Client used by Client.
+// It exists so the connection can be substituted in tests.
+type transport interface {
+	Send(chat xmpp.Chat) (int, error)
+	SendPresence(presence xmpp.Presence) (int, error)
+	Recv() (interface{}, error)
+	Close() error
+}
+
+// dialFunc establishes a new transport from the given XMPP options.
+type dialFunc func(options xmpp.Options) (transport, error)
+
+func dialXMPP(options xmpp.Options) (transport, error) {
+	return options.NewClient()
+}
@@ -0,0 +1,317 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kradalby/nefit-go/crypto"
+	xmpp "github.com/xmppo/go-xmpp"
+)
+
+// fakeTransport is an in-memory transport. Stanzas (or errors) pushed onto
+// recvCh are returned from Recv, and every sent chat is recorded and passed
+// to onSend so tests can script backend responses.
+type fakeTransport struct {
+	mu        sync.Mutex
+	sent      []xmpp.Chat
+	presences int
+	onSend    func(chat xmpp.Chat)
+
+	recvCh    chan interface{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newFakeTransport() *fakeTransport {
+	return &fakeTransport{
+		recvCh: make(chan interface{}, 100),
+		closed: make(chan struct{}),
+	}
+}
+
+func (f *fakeTransport) Send(chat xmpp.Chat) (int, error) {
+	f.mu.Lock()
+	f.sent = append(f.sent, chat)
+	onSend := f.onSend
+	f.mu.Unlock()
+
+	if onSend != nil {
+		onSend(chat)
+	}
+	return len(chat.Text), nil
+}
+
+func (f *fakeTransport) SendPresence(presence xmpp.Presence) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.presences++
+	return 0, nil
+}
+
+func (f *fakeTransport) Recv() (interface{}, error) {
+	select {
+	case item := <-f.recvCh:
+		if err, ok := item.(error); ok {
+			return nil, err
+		}
+		return item, nil
+	case <-f.closed:
+		return nil, io.EOF
+	}
+}
+
+func (f *fakeTransport) Close() error {
+	f.closeOnce.Do(func() { close(f.closed) })
+	return nil
+}
+
+func (f *fakeTransport) sentChats() []xmpp.Chat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]xmpp.Chat(nil), f.sent...)
+}
+
+// newTestClient returns a client connected to the given fake transport.
+func newTestClient(t *testing.T, ft *fakeTransport) *Client {
+	t.Helper()
+
+	c := newUnconnectedClient(t)
+	c.dial = func(xmpp.Options) (transport, error) {
+		return ft, nil
+	}
+
+	if err := c.Connect(t.Context()); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+// fakeRequest is a request received by a fakeDevice, with the PUT body decrypted.
+type fakeRequest struct {
+	Method string
+	URI    string
+	Body   string
+}
+
+// fakeResponse overrides the reply for a URI.
+type fakeResponse struct {
+	StatusCode int
+	Status     string
+	Headers    map[string]string
+	Body       string // plaintext, encrypted before sending
+	// Encrypted, if set, is sent verbatim instead of encrypting Body.
+	Encrypted string
+}
+
+// fakeDevice emulates the backend on top of a fakeTransport. GETs are answered from
+// values (URI -> JSON document) and PUTs of {"value": ...} update the stored document.
+type fakeDevice struct {
+	t   *testing.T
+	ft  *fakeTransport
+	enc *crypto.Encryptor
+
+	mu        sync.Mutex
+	values    map[string]string
+	responses map[string][]fakeResponse
+	ignored   map[string]bool
+	hung      map[string]bool
+	dropped   map[string]int
+	requests  []fakeRequest
+}
+
+// newFakeDevice returns a connected client backed by a fake device.
+func newFakeDevice(t *testing.T) (*Client, *fakeDevice) {
+	t.Helper()
+
+	d := newFakeBackend(t, "123456789")
+	return newTestClient(t, d.ft), d
+}
+
+// newFakeBackend returns a fake device for the given serial number, using the
+// access key and password of newUnconnectedClient.
+func newFakeBackend(t *testing.T, serial string) *fakeDevice {
+	t.Helper()
+
+	enc, err := crypto.NewEncryptor(serial, "abcdefghijklmnop", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &fakeDevice{
+		t:         t,
+		ft:        newFakeTransport(),
+		enc:       enc,
+		values:    make(map[string]string),
+		responses: make(map[string][]fakeResponse),
+		ignored:   make(map[string]bool),
+		hung:      make(map[string]bool),
+		dropped:   make(map[string]int),
+	}
+	d.ft.onSend = d.handle
+
+	return d
+}
+
+// set stores the JSON document returned for GET uri.
+func (d *fakeDevice) set(uri, document string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.values[uri] = document
+}
+
+// setValue stores {"id": uri, "value": value} for GET uri.
+func (d *fakeDevice) setValue(uri string, value interface{}) {
+	doc, err := json.Marshal(map[string]interface{}{"id": uri, "value": value})
+	if err != nil {
+		d.t.Fatal(err)
+	}
+	d.set(uri, string(doc))
+}
+
+// queue makes the next request to uri receive resp instead of the default reply.
+func (d *fakeDevice) queue(uri string, resp fakeResponse) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.responses[uri] = append(d.responses[uri], resp)
+}
+
+// ignorePuts makes PUTs to uri succeed without changing the stored value.
+func (d *fakeDevice) ignorePuts(uri string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.ignored[uri] = true
+}
+
+// hang makes requests to uri go unanswered.
+func (d *fakeDevice) hang(uri string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.hung[uri] = true
+}
+
+// drop leaves the next n requests for uri unanswered, like a flaky backend.
+func (d *fakeDevice) drop(uri string, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dropped[uri] = n
+}
+
+func (d *fakeDevice) requestLog() []fakeRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]fakeRequest(nil), d.requests...)
+}
+
+// requestsFor returns the requests with the given method ("GET" or "PUT").
+func (d *fakeDevice) requestsFor(method string) []fakeRequest {
+	var out []fakeRequest
+	for _, r := range d.requestLog() {
+		if r.Method == method {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (d *fakeDevice) handle(chat xmpp.Chat) {
+	// The XML body decodes the protocol's "&#13;\n" line breaks to "\r\n".
+	text := strings.ReplaceAll(chat.Text, "\r\n", "\r")
+	head, body, _ := strings.Cut(text, "\r\r")
+	requestLine, _, _ := strings.Cut(head, "\r")
+	fields := strings.Fields(requestLine)
+	if len(fields) < 2 {
+		d.t.Errorf("malformed request line %q", requestLine)
+		return
+	}
+	method, uri := fields[0], fields[1]
+
+	if body != "" {
+		decrypted, err := d.enc.DecryptAndStrip(body)
+		if err != nil {
+			d.t.Errorf("failed to decrypt request body: %v", err)
+			return
+		}
+		body = decrypted
+	}
+
+	d.mu.Lock()
+	d.requests = append(d.requests, fakeRequest{Method: method, URI: uri, Body: body})
+	if d.hung[uri] {
+		d.mu.Unlock()
+		return
+	}
+	if d.dropped[uri] > 0 {
+		d.dropped[uri]--
+		d.mu.Unlock()
+		return
+	}
+
+	resp := fakeResponse{StatusCode: 200, Status: "OK"}
+	if queued := d.responses[uri]; len(queued) > 0 {
+		resp = queued[0]
+		d.responses[uri] = queued[1:]
+	} else if method == "PUT" {
+		resp.StatusCode, resp.Status = 204, "No Content"
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &payload); err == nil && !d.ignored[uri] {
+			doc, _ := json.Marshal(map[string]interface{}{"id": uri, "value": payload["value"]})
+			d.values[uri] = string(doc)
+		}
+	} else if doc, ok := d.values[uri]; ok {
+		resp.Body = doc
+	} else {
+		resp.StatusCode, resp.Status = 404, "Not Found"
+	}
+	d.mu.Unlock()
+
+	d.reply(resp)
+}
+
+func (d *fakeDevice) reply(resp fakeResponse) {
+	text := fmt.Sprintf("HTTP/1.0 %d %s\n", resp.StatusCode, resp.Status)
+	contentType := "Content-Type: application/json\n"
+	for k, v := range resp.Headers {
+		text += fmt.Sprintf("%s: %s\n", k, v)
+		if strings.EqualFold(k, "Content-Type") {
+			contentType = ""
+		}
+	}
+	if resp.Encrypted != "" {
+		text += contentType + "\n" + resp.Encrypted
+	} else if resp.Body != "" {
+		encrypted, err := d.enc.Encrypt(resp.Body)
+		if err != nil {
+			d.t.Errorf("failed to encrypt response: %v", err)
+			return
+		}
+		text += contentType + "\n" + encrypted
+	} else {
+		text += "\n"
+	}
+
+	d.ft.recvCh <- xmpp.Chat{Type: "chat", Text: text}
+}
+
+// replayCapture returns a client whose fake device answers with the recorded responses
+// of a capture, in order per URI. Recorded encrypted bodies are sent verbatim, so the
+// capture must have been made with the test credentials of newUnconnectedClient.
+func replayCapture(t *testing.T, entries []CaptureEntry) (*Client, *fakeDevice) {
+	t.Helper()
+
+	c, d := newFakeDevice(t)
+	for _, e := range entries {
+		d.queue(e.URI, fakeResponse{
+			StatusCode: e.StatusCode,
+			Status:     e.Status,
+			Encrypted:  e.ResponseEncrypted,
+		})
+	}
+
+	return c, d
+}
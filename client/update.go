@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// UpdateStatus reads the installed firmware version and the update state of the gateway.
+// It returns ErrUnsupported if the firmware does not report update availability. The
+// versions are left empty if the device does not provide them.
+func (c *Client) UpdateStatus(ctx context.Context) (*types.UpdateStatus, error) {
+	ctx = ensureRequestID(ctx)
+
+	state, err := c.getStringValue(ctx, types.URIUpdateState)
+	if err != nil {
+		if isUnsupported(err) {
+			return nil, fmt.Errorf("update status: %w", ErrUnsupported)
+		}
+		return nil, fmt.Errorf("failed to get update state: %w", err)
+	}
+
+	current, err := c.optionalString(ctx, types.URIFirmwareVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firmware version: %w", err)
+	}
+	available, err := c.optionalString(ctx, types.URIUpdateVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get update version: %w", err)
+	}
+
+	return decodeUpdateStatus(state, current, available), nil
+}
+
+// decodeUpdateStatus combines the update readings. An offered version equal to the
+// installed one is not an update.
+func decodeUpdateStatus(state, current, available string) *types.UpdateStatus {
+	status := &types.UpdateStatus{
+		CurrentVersion: current,
+		State:          types.UpdateState(state),
+	}
+	if available != "" && available != current {
+		status.AvailableVersion = available
+	}
+	status.Available = status.State == types.UpdateAvailable
+	return status
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestDecodeUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name                      string
+		state, current, available string
+		want                      types.UpdateStatus
+	}{
+		{
+			name:  "available",
+			state: "available", current: "04.08.02", available: "04.10.00",
+			want: types.UpdateStatus{CurrentVersion: "04.08.02", AvailableVersion: "04.10.00", State: types.UpdateAvailable, Available: true},
+		},
+		{
+			name:  "current",
+			state: "idle", current: "04.10.00", available: "04.10.00",
+			want: types.UpdateStatus{CurrentVersion: "04.10.00", State: types.UpdateIdle},
+		},
+		{
+			name:  "installing without versions",
+			state: "installing",
+			want:  types.UpdateStatus{State: types.UpdateInstalling},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := decodeUpdateStatus(tt.state, tt.current, tt.available); *got != tt.want {
+			t.Errorf("%s: decodeUpdateStatus = %+v, want %+v", tt.name, *got, tt.want)
+		}
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	c, d := newFakeDevice(t)
+	d.setValue(types.URIUpdateState, "available")
+	d.setValue(types.URIFirmwareVersion, "04.08.02")
+	d.setValue(types.URIUpdateVersion, "04.09.01")
+
+	status, err := c.UpdateStatus(t.Context())
+	if err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	if !status.Available || status.CurrentVersion != "04.08.02" || status.AvailableVersion != "04.09.01" {
+		t.Errorf("Unexpected update status: %+v", status)
+	}
+	if puts := d.requestsFor("PUT"); len(puts) != 0 {
+		t.Errorf("Expected UpdateStatus to be read-only, got %+v", puts)
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// userModeTTL is how long a cached user mode is trusted. The mode can also be changed
+// on the thermostat itself, which is only noticed through push notifications.
+const userModeTTL = 5 * time.Minute
+
+// cachedUserMode is the last known user mode of the default heating circuit.
+type cachedUserMode struct {
+	mode types.UserMode
+	at   time.Time
+}
+
+// rememberUserMode caches mode, or drops the cache if mode is empty.
+func (c *Client) rememberUserMode(mode types.UserMode) {
+	if mode == "" {
+		c.userMode.Store(nil)
+		return
+	}
+	c.userMode.Store(&cachedUserMode{mode: mode, at: c.now()})
+}
+
+// forgetUserMode invalidates the cached user mode.
+func (c *Client) forgetUserMode() {
+	c.userMode.Store(nil)
+}
+
+// cachedMode returns the cached user mode if it is still fresh.
+func (c *Client) cachedMode() (types.UserMode, bool) {
+	cached := c.userMode.Load()
+	if cached == nil || c.now().Sub(cached.at) >= userModeTTL {
+		return "", false
+	}
+	return cached.mode, true
+}
+
+// currentUserMode returns the user mode of the default heating circuit, from the
+// cache when it is fresh and from a status read otherwise.
+func (c *Client) currentUserMode(ctx context.Context) (types.UserMode, error) {
+	if mode, ok := c.cachedMode(); ok {
+		c.log(ctx).Debug("using cached user mode", "mode", mode)
+		return mode, nil
+	}
+
+	status, err := c.Status(WithCircuit(ctx, types.DefaultCircuit), false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get status: %w", err)
+	}
+	return status.UserMode, nil
+}
+
+// hotWaterEndpoint returns the hot water URI matching the current user mode.
+func (c *Client) hotWaterEndpoint(ctx context.Context) (string, error) {
+	mode, err := c.currentUserMode(ctx)
+	if err != nil {
+		return "", err
+	}
+	if mode == types.UserModeClock {
+		return types.URIHotWaterClockMode, nil
+	}
+	return types.URIHotWaterManualMode, nil
+}
+
+// observeUserMode updates the cached user mode from a push notification.
+func (c *Client) observeUserMode(uri string, data interface{}) {
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	var mode string
+	switch uri {
+	case types.URIUserMode:
+		mode, _ = types.LookupString(dataMap, "value")
+	case types.URIStatus:
+		if value, ok := dataMap["value"].(map[string]interface{}); ok {
+			mode, _ = types.LookupString(value, "UMD")
+		}
+	}
+	// Partial status pushes leave out UMD; they say nothing about the mode.
+	if mode != "" {
+		c.rememberUserMode(types.UserMode(mode))
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kradalby/nefit-go/client"
+	"github.com/kradalby/nefit-go/types"
+)
+
+// heatingClient is the subset of *client.Client used by the circuit-aware commands.
+type heatingClient interface {
+	Status(ctx context.Context, includeOutdoorTemp bool) (*types.Status, error)
+	SetTemperature(ctx context.Context, temperature float64, opts ...client.WriteOption) error
+	SetUserMode(ctx context.Context, mode types.UserMode, opts ...client.WriteOption) error
+}
+
+// withCircuitFlag validates the --circuit flag and selects that circuit in ctx.
+func withCircuitFlag(ctx context.Context) (context.Context, error) {
+	if !types.ValidCircuit(*circuit) {
+		return nil, fmt.Errorf("invalid circuit %q (expected hc1, hc2, ...)", *circuit)
+	}
+	return client.WithCircuit(ctx, *circuit), nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kradalby/nefit-go/client"
+	"github.com/kradalby/nefit-go/types"
+)
+
+// fakeHeatingClient records the URIs the client would target for each call.
+type fakeHeatingClient struct {
+	uris []string
+}
+
+func (f *fakeHeatingClient) target(ctx context.Context, uri string) {
+	f.uris = append(f.uris, types.CircuitURI(uri, client.CircuitFromContext(ctx)))
+}
+
+func (f *fakeHeatingClient) Status(ctx context.Context, includeOutdoorTemp bool) (*types.Status, error) {
+	f.target(ctx, types.URIUserMode)
+	return &types.Status{}, nil
+}
+
+func (f *fakeHeatingClient) SetTemperature(ctx context.Context, temperature float64, opts ...client.WriteOption) error {
+	f.target(ctx, types.URIManualSetpoint)
+	return nil
+}
+
+func (f *fakeHeatingClient) SetUserMode(ctx context.Context, mode types.UserMode, opts ...client.WriteOption) error {
+	f.target(ctx, types.URIUserMode)
+	return nil
+}
+
+func TestCircuitFlagWiring(t *testing.T) {
+	defer func(prev string) { *circuit = prev }(*circuit)
+
+	tests := []struct {
+		circuit string
+		want    []string
+	}{
+		{types.DefaultCircuit, []string{types.URIUserMode, types.URIManualSetpoint, types.URIUserMode}},
+		{"hc2", []string{"/heatingCircuits/hc2/usermode", "/heatingCircuits/hc2/temperatureRoomManual", "/heatingCircuits/hc2/usermode"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.circuit, func(t *testing.T) {
+			*circuit = tt.circuit
+			ctx, err := withCircuitFlag(t.Context())
+			if err != nil {
+				t.Fatalf("withCircuitFlag failed: %v", err)
+			}
+
+			fc := &fakeHeatingClient{}
+			if err := runStatus(ctx, fc, false); err != nil {
+				t.Fatal(err)
+			}
+			if err := runSetTemperature(ctx, fc, 21); err != nil {
+				t.Fatal(err)
+			}
+			if err := runSetUserMode(ctx, fc, types.UserModeClock); err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Join(fc.uris, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected URIs %v, got %v", tt.want, fc.uris)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "hc0", "dhwA", "hc1/x"} {
+		*circuit = bad
+		if _, err := withCircuitFlag(t.Context()); err == nil {
+			t.Errorf("Expected error for circuit %q", bad)
+		}
+	}
+}
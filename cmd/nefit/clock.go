@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var (
+	clockFlagSet = flag.NewFlagSet("clock", flag.ExitOnError)
+	clockSync    = clockFlagSet.Bool("sync", false, "Set the thermostat clock to the host time (WRITE operation)")
+)
+
+var clockCmd = &ffcli.Command{
+	Name:       "clock",
+	ShortUsage: "nefit clock [--sync]",
+	ShortHelp:  "Show the thermostat clock drift",
+	LongHelp: `Compare the thermostat clock with the host clock.
+
+A wrong device clock makes the heating program switch at the wrong time.
+The drift is positive when the thermostat is ahead of the host.
+
+With --sync, the thermostat clock is set to the host time afterwards.
+
+Examples:
+  nefit clock
+  nefit clock --sync`,
+	FlagSet: clockFlagSet,
+	Exec: func(ctx context.Context, args []string) error {
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+
+		device, err := c.DeviceTime(reqCtx)
+		if err != nil {
+			return err
+		}
+		drift, err := c.ClockDrift(reqCtx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Device time: %s\n", device.Format(time.DateTime))
+		fmt.Printf("Host time:   %s\n", time.Now().Format(time.DateTime))
+		fmt.Printf("Drift:       %s\n", drift.Round(time.Second))
+
+		if !*clockSync {
+			if drift.Abs() >= time.Minute {
+				fmt.Fprintln(os.Stderr, "Run 'nefit clock --sync' to correct the thermostat clock.")
+			}
+			return nil
+		}
+
+		if err := c.SetDeviceTime(reqCtx, time.Now()); err != nil {
+			return err
+		}
+		fmt.Println("OK - Thermostat clock synchronized")
+		return nil
+	},
+}
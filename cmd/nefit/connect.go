@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kradalby/nefit-go/client"
+)
+
+// maxConnectBackoff caps the wait between connection attempts.
+const maxConnectBackoff = 30 * time.Second
+
+// connectBackoff returns the wait before the given (1-based) connection retry.
+func connectBackoff(attempt int) time.Duration {
+	backoff := time.Second
+	for i := 1; i < attempt && backoff < maxConnectBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxConnectBackoff)
+}
+
+// connecter is the part of *client.Client used to establish the connection.
+type connecter interface {
+	Connect(ctx context.Context) error
+}
+
+// connectWithRetry connects c, retrying a failed attempt up to retries times after
+// waiting backoff(n) before the n-th retry. Rejected credentials are not retried. All
+// attempts and waits share ctx, so its deadline bounds the whole sequence; the error of
+// the last attempt is returned. Each retry is announced on status.
+func connectWithRetry(ctx context.Context, c connecter, retries int, backoff func(int) time.Duration, status io.Writer) error {
+	for attempt := 1; ; attempt++ {
+		err := c.Connect(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt > retries || errors.Is(err, client.ErrAuthFailed) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff(attempt)
+		fmt.Fprintf(status, "Connection failed (%v), retrying in %s...\n", err, wait)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/client"
+)
+
+// fakeConnecter fails the first len(errs) Connect calls with errs in turn.
+type fakeConnecter struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeConnecter) Connect(ctx context.Context) error {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return nil
+}
+
+func noBackoff(int) time.Duration { return time.Millisecond }
+
+func TestConnectWithRetry(t *testing.T) {
+	c := &fakeConnecter{errs: []error{fmt.Errorf("%w: connection reset", client.ErrNetwork)}}
+	var status bytes.Buffer
+
+	if err := connectWithRetry(t.Context(), c, 2, noBackoff, &status); err != nil {
+		t.Fatalf("Expected the second attempt to succeed, got %v", err)
+	}
+	if c.calls != 2 {
+		t.Errorf("Expected 2 connect attempts, got %d", c.calls)
+	}
+	if !strings.Contains(status.String(), "retrying") {
+		t.Errorf("Expected the retry to be announced, got %q", status.String())
+	}
+}
+
+func TestConnectWithRetryGivesUp(t *testing.T) {
+	netErr := fmt.Errorf("%w: connection reset", client.ErrNetwork)
+
+	c := &fakeConnecter{errs: []error{netErr, netErr, netErr}}
+	if err := connectWithRetry(t.Context(), c, 1, noBackoff, &bytes.Buffer{}); !errors.Is(err, client.ErrNetwork) || c.calls != 2 {
+		t.Errorf("Expected the last error after 2 attempts, got %v after %d", err, c.calls)
+	}
+
+	c = &fakeConnecter{errs: []error{fmt.Errorf("%w: not-authorized", client.ErrAuthFailed)}}
+	if err := connectWithRetry(t.Context(), c, 3, noBackoff, &bytes.Buffer{}); !errors.Is(err, client.ErrAuthFailed) || c.calls != 1 {
+		t.Errorf("Expected rejected credentials not to be retried, got %v after %d attempts", err, c.calls)
+	}
+
+	// The backoff does not outlast the overall timeout.
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	c = &fakeConnecter{errs: []error{netErr, netErr}}
+	start := time.Now()
+	err := connectWithRetry(ctx, c, 5, func(int) time.Duration { return time.Minute }, &bytes.Buffer{})
+	if !errors.Is(err, client.ErrNetwork) || time.Since(start) > time.Second {
+		t.Errorf("Expected to stop at the timeout with the last error, got %v after %v", err, time.Since(start))
+	}
+}
+
+func TestConnectBackoff(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i, w := range want {
+		if got := connectBackoff(i + 1); got != w {
+			t.Errorf("connectBackoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
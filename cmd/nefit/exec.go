@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var execCmd = &ffcli.Command{
+	Name:       "exec",
+	ShortUsage: "nefit exec < commands.json",
+	ShortHelp:  "Execute a batch of raw GET/PUT commands from stdin",
+	LongHelp: `Execute a JSON array of raw commands read from stdin over a single connection.
+
+⚠️  WARNING: "put" commands perform WRITE operations on your thermostat!
+
+Each command is an object with a method, a URI and, for puts, the data:
+  [
+    {"method": "get", "uri": "/ecus/rrc/uiStatus"},
+    {"method": "put", "uri": "/heatingCircuits/hc1/temperatureRoomManual", "data": {"value": 21.5}}
+  ]
+
+Commands run one at a time in order. A JSON array of results is written to
+stdout, one per command, each with "success" and either "data" or "error".
+A failing command does not stop the remaining commands.
+
+Example:
+  echo '[{"method":"get","uri":"/system/appliance/systemPressure"}]' | nefit exec`,
+	Exec: func(ctx context.Context, args []string) error {
+		commands, err := readExecCommands(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		return printJSON(runExecCommands(ctx, c, commands))
+	},
+}
+
+// rawClient is the subset of *client.Client used by exec.
+type rawClient interface {
+	Get(ctx context.Context, uri string) (interface{}, error)
+	Put(ctx context.Context, uri string, data interface{}) error
+}
+
+type execCommand struct {
+	Method string      `json:"method"`
+	URI    string      `json:"uri"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+type execResult struct {
+	Method  string      `json:"method"`
+	URI     string      `json:"uri"`
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+func readExecCommands(r io.Reader) ([]execCommand, error) {
+	var commands []execCommand
+	if err := json.NewDecoder(r).Decode(&commands); err != nil {
+		return nil, fmt.Errorf("invalid command list: %w", err)
+	}
+
+	for i, cmd := range commands {
+		switch strings.ToLower(cmd.Method) {
+		case "get":
+		case "put":
+			if cmd.Data == nil {
+				return nil, fmt.Errorf("command %d: put requires data", i)
+			}
+		default:
+			return nil, fmt.Errorf("command %d: invalid method %q (must be 'get' or 'put')", i, cmd.Method)
+		}
+		if cmd.URI == "" {
+			return nil, fmt.Errorf("command %d: uri required", i)
+		}
+	}
+
+	return commands, nil
+}
+
+// runExecCommands executes the commands sequentially, each with its own request timeout.
+func runExecCommands(ctx context.Context, c rawClient, commands []execCommand) []execResult {
+	results := make([]execResult, 0, len(commands))
+
+	for _, cmd := range commands {
+		result := execResult{Method: strings.ToLower(cmd.Method), URI: cmd.URI}
+
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		var err error
+		if result.Method == "get" {
+			result.Data, err = c.Get(reqCtx, cmd.URI)
+		} else {
+			err = c.Put(reqCtx, cmd.URI, cmd.Data)
+		}
+		cancel()
+
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeRawClient struct {
+	calls []string
+	data  map[string]interface{}
+}
+
+func (f *fakeRawClient) Get(ctx context.Context, uri string) (interface{}, error) {
+	f.calls = append(f.calls, "get "+uri)
+	if v, ok := f.data[uri]; ok {
+		return v, nil
+	}
+	return nil, errors.New("HTTP error 404: Not Found")
+}
+
+func (f *fakeRawClient) Put(ctx context.Context, uri string, data interface{}) error {
+	f.calls = append(f.calls, "put "+uri)
+	f.data[uri] = data
+	return nil
+}
+
+func TestRunExecCommands(t *testing.T) {
+	input := `[
+		{"method": "get", "uri": "/a"},
+		{"method": "PUT", "uri": "/b", "data": {"value": 1}},
+		{"method": "get", "uri": "/missing"},
+		{"method": "get", "uri": "/b"}
+	]`
+
+	commands, err := readExecCommands(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readExecCommands failed: %v", err)
+	}
+
+	fc := &fakeRawClient{data: map[string]interface{}{"/a": "A"}}
+	results := runExecCommands(t.Context(), fc, commands)
+
+	wantCalls := []string{"get /a", "put /b", "get /missing", "get /b"}
+	if strings.Join(fc.calls, ",") != strings.Join(wantCalls, ",") {
+		t.Errorf("Expected calls %v, got %v", wantCalls, fc.calls)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+	if !results[0].Success || results[0].Data != "A" {
+		t.Errorf("Unexpected result for get /a: %+v", results[0])
+	}
+	if !results[1].Success || results[1].Method != "put" {
+		t.Errorf("Unexpected result for put /b: %+v", results[1])
+	}
+	if results[2].Success || !strings.Contains(results[2].Error, "404") {
+		t.Errorf("Expected failure for /missing, got %+v", results[2])
+	}
+	if !results[3].Success || results[3].Data == nil {
+		t.Errorf("Expected get /b to return written data, got %+v", results[3])
+	}
+}
+
+func TestReadExecCommandsInvalid(t *testing.T) {
+	for _, input := range []string{
+		`{"method":"get"}`,
+		`[{"method":"delete","uri":"/a"}]`,
+		`[{"method":"get"}]`,
+		`[{"method":"put","uri":"/a"}]`,
+	} {
+		if _, err := readExecCommands(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected error for %s", input)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+// exploreRoots are the top-level resources walked when no root is given.
+var exploreRoots = []string{"/dhwCircuits", "/ecus", "/gateway", "/heatingCircuits", "/system"}
+
+var exploreCmd = &ffcli.Command{
+	Name:       "explore",
+	ShortUsage: "nefit explore [root...]",
+	ShortHelp:  "List the resources the device exposes",
+	LongHelp: `Walk the device's resource tree and print every discovered URI.
+
+Without arguments the top-level roots (/dhwCircuits, /ecus, /gateway,
+/heatingCircuits, /system) are walked. The walk issues one request per
+resource, so it can take a while; the --timeout flag does not apply to it.
+
+Examples:
+  nefit explore
+  nefit explore /system
+  nefit --pretty explore /heatingCircuits`,
+	Exec: func(ctx context.Context, args []string) error {
+		roots := args
+		if len(roots) == 0 {
+			roots = exploreRoots
+		}
+
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		var all []string
+		for _, root := range roots {
+			resources, err := c.ListResources(ctx, root)
+			if err != nil {
+				if len(args) > 0 {
+					return err
+				}
+				// Not every appliance has every default root.
+				if *verbose {
+					fmt.Fprintf(os.Stderr, "skipping %s: %v\n", root, err)
+				}
+				continue
+			}
+			all = append(all, resources...)
+		}
+
+		if *pretty {
+			return printJSON(all)
+		}
+		for _, uri := range all {
+			fmt.Println(uri)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/kradalby/nefit-go/client"
+	"github.com/kradalby/nefit-go/types"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var (
+	gasUsageFlagSet  = flag.NewFlagSet("gas-usage", flag.ExitOnError)
+	gasUsageWatch    = gasUsageFlagSet.Bool("watch", false, "Keep running and append new days on every --interval")
+	gasUsageOut      = gasUsageFlagSet.String("out", "", "Append the records to this CSV file instead of printing them")
+	gasUsageInterval = gasUsageFlagSet.Duration("interval", time.Hour, "Polling interval of --watch")
+)
+
+// gasUsageHeader is the first line of a gas usage CSV file.
+var gasUsageHeader = []string{"date", "hot_water", "central_heating", "outdoor_temp"}
+
+// gasUsageDateLayout is the date format of the CSV "date" column.
+const gasUsageDateLayout = "2006-01-02"
+
+var gasUsageCmd = &ffcli.Command{
+	Name:       "gas-usage",
+	ShortUsage: "nefit gas-usage [--out <file.csv>] [--watch [--interval <duration>]]",
+	ShortHelp:  "Print or log the daily gas usage as CSV",
+	LongHelp: `Print the recorded daily gas usage as CSV (date, hot water, central
+heating, average outdoor temperature).
+
+With --out, the days not yet in the file are appended to it, writing the
+header first if the file is empty. Today is left out until it is complete.
+
+With --watch, the recordings are read again every --interval (default 1h)
+and new days are appended, until you press Ctrl+C. The file is reopened on
+every write, so it may be rotated or truncated meanwhile; days already
+written by this run are not written again.
+
+Examples:
+  nefit gas-usage
+  nefit gas-usage --out usage.csv
+  nefit gas-usage --watch --out usage.csv`,
+	FlagSet: gasUsageFlagSet,
+	Exec: func(ctx context.Context, args []string) error {
+		if *gasUsageWatch && *gasUsageOut == "" {
+			return fmt.Errorf("--watch requires --out")
+		}
+		if *gasUsageInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		written := map[string]bool{}
+		poll := func() error {
+			reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+			defer cancel()
+
+			recordings, err := c.GetRecordings(reqCtx, types.URIGasUsage)
+			if err != nil {
+				return err
+			}
+
+			if *gasUsageOut == "" {
+				return writeGasUsage(os.Stdout, recordings, time.Now())
+			}
+			n, err := appendGasUsage(*gasUsageOut, recordings, written, time.Now())
+			if err != nil {
+				return err
+			}
+			if *verbose || !*gasUsageWatch {
+				fmt.Fprintf(os.Stderr, "Appended %d day(s) to %s\n", n, *gasUsageOut)
+			}
+			return nil
+		}
+
+		if err := poll(); err != nil {
+			return err
+		}
+		if !*gasUsageWatch {
+			return nil
+		}
+
+		return watchGasUsage(ctx, c, poll)
+	},
+}
+
+// watchGasUsage calls poll every --interval until interrupted. Failed polls are
+// reported and retried at the next interval.
+func watchGasUsage(ctx context.Context, c *client.Client, poll func() error) error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(*gasUsageInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigChan:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if !c.IsConnected() {
+				if err := connectClient(c); err != nil {
+					fmt.Fprintf(os.Stderr, "Reconnect failed: %v\n", err)
+					continue
+				}
+			}
+			if err := poll(); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to update gas usage: %v\n", err)
+			}
+		}
+	}
+}
+
+// appendGasUsage appends the completed days of recordings (those before today) that
+// are neither in the file at path nor in written, and marks them in written. The header
+// is written first if the file is empty, e.g. after it was rotated. It returns the
+// number of days appended.
+func appendGasUsage(path string, recordings []types.Recording, written map[string]bool, now time.Time) (int, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	existing, err := readGasUsageDates(f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for date := range existing {
+		written[date] = true
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	var header []string
+	if info.Size() == 0 {
+		header = gasUsageHeader
+	}
+
+	var fresh []types.Recording
+	for _, rec := range recordings {
+		if !written[rec.Date.Format(gasUsageDateLayout)] {
+			fresh = append(fresh, rec)
+		}
+	}
+
+	w := csv.NewWriter(f)
+	if header != nil {
+		if err := w.Write(header); err != nil {
+			return 0, err
+		}
+	}
+	n, err := writeGasUsageRows(w, fresh, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	for _, rec := range fresh[:n] {
+		written[rec.Date.Format(gasUsageDateLayout)] = true
+	}
+	return n, nil
+}
+
+// readGasUsageDates returns the dates in the first column of a gas usage CSV.
+func readGasUsageDates(r io.Reader) (map[string]bool, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	dates := map[string]bool{}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return dates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) > 0 && row[0] != gasUsageHeader[0] {
+			dates[row[0]] = true
+		}
+	}
+}
+
+// writeGasUsage writes recordings as CSV with a header line.
+func writeGasUsage(out io.Writer, recordings []types.Recording, now time.Time) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(gasUsageHeader); err != nil {
+		return err
+	}
+	_, err := writeGasUsageRows(w, recordings, now)
+	return err
+}
+
+// writeGasUsageRows writes the recordings dated before the day of now, which is
+// still being recorded, and flushes w. Recordings must be oldest first. It returns
+// the number of rows written.
+func writeGasUsageRows(w *csv.Writer, recordings []types.Recording, now time.Time) (int, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	n := 0
+	for _, rec := range recordings {
+		if !rec.Date.Before(today) {
+			break
+		}
+		row := []string{
+			rec.Date.Format(gasUsageDateLayout),
+			formatValue(rec.Values, "hw"),
+			formatValue(rec.Values, "ch"),
+			formatValue(rec.Values, "T"),
+		}
+		if err := w.Write(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	w.Flush()
+	return n, w.Error()
+}
+
+// formatValue formats values[key], or returns "" if the day has no such value.
+func formatValue(values map[string]float64, key string) string {
+	v, ok := values[key]
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func gasUsageDay(day int, hw, ch, outdoor float64) types.Recording {
+	return types.Recording{
+		Date:   time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC),
+		Values: map[string]float64{"hw": hw, "ch": ch, "T": outdoor},
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestAppendGasUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.csv")
+	now := time.Date(2024, time.January, 16, 9, 0, 0, 0, time.UTC)
+	written := map[string]bool{}
+
+	records := []types.Recording{gasUsageDay(14, 0.8, 12.4, 3.5), gasUsageDay(15, 1.2, 14.1, -0.5), gasUsageDay(16, 0.1, 2, 1)}
+	n, err := appendGasUsage(path, records, written, now)
+	if err != nil {
+		t.Fatalf("appendGasUsage failed: %v", err)
+	}
+	want := "date,hot_water,central_heating,outdoor_temp\n2024-01-14,0.8,12.4,3.5\n2024-01-15,1.2,14.1,-0.5\n"
+	if n != 2 || readFile(t, path) != want {
+		t.Fatalf("First append wrote %d day(s):\n%s\nwant today left out:\n%s", n, readFile(t, path), want)
+	}
+
+	// The next day, the same recordings plus the now completed 16th.
+	now = now.AddDate(0, 0, 1)
+	records[2] = gasUsageDay(16, 0.9, 13, 1)
+	if n, err = appendGasUsage(path, records, written, now); err != nil {
+		t.Fatalf("appendGasUsage failed: %v", err)
+	}
+	want += "2024-01-16,0.9,13,1\n"
+	if n != 1 || readFile(t, path) != want {
+		t.Fatalf("Second append wrote %d day(s):\n%s\nwant only the new day:\n%s", n, readFile(t, path), want)
+	}
+
+	// A fresh process knows nothing, so the file itself must prevent duplicates.
+	if n, err = appendGasUsage(path, records, map[string]bool{}, now); err != nil || n != 0 {
+		t.Fatalf("Append with existing file wrote %d day(s), err %v; want none", n, err)
+	}
+
+	// Rotated away: the new file gets a header and only days not written before.
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	records = append(records, gasUsageDay(17, 1, 11, 2))
+	now = now.AddDate(0, 0, 1)
+	if n, err = appendGasUsage(path, records, written, now); err != nil {
+		t.Fatalf("appendGasUsage failed: %v", err)
+	}
+	want = "date,hot_water,central_heating,outdoor_temp\n2024-01-17,1,11,2\n"
+	if n != 1 || readFile(t, path) != want {
+		t.Fatalf("Append after truncation wrote %d day(s):\n%s\nwant:\n%s", n, readFile(t, path), want)
+	}
+}
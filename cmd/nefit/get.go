@@ -2,14 +2,25 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/kradalby/nefit-go/types"
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
 
+var (
+	getFlagSet = flag.NewFlagSet("get", flag.ExitOnError)
+	getField   = getFlagSet.String("field", "", "Print only the value at this dot-separated path (e.g. value.IHT)")
+	getDecode  = getFlagSet.Bool("decode", false, "Rename uiStatus keys to readable names and decode their values")
+)
+
 var getCmd = &ffcli.Command{
 	Name:       "get",
-	ShortUsage: "nefit get <uri>",
+	ShortUsage: "nefit get [--field <path>] [--decode] <uri>",
 	ShortHelp:  "Perform a raw GET request",
 	LongHelp: `Perform a raw GET request to any endpoint.
 
@@ -24,7 +35,17 @@ Common URIs:
 
 Examples:
   nefit get /ecus/rrc/uiStatus
-  nefit get /system/sensors/temperatures/outdoor_t1 --pretty`,
+  nefit get /system/sensors/temperatures/outdoor_t1 --pretty
+  nefit get --field value.IHT /ecus/rrc/uiStatus
+  nefit get --decode /ecus/rrc/uiStatus
+
+With --field, the path is looked up in the decoded response; array elements are
+addressed by index (value.0.d). Scalars are printed unquoted, objects as JSON.
+
+With --decode, the cryptic uiStatus keys (UMD, IHT, BAI, ...) are renamed to
+readable names and on/off, numeric and boiler indicator values decoded; unknown
+keys are kept. It is applied before --field, so paths use the readable names.`,
+	FlagSet: getFlagSet,
 	Exec: func(ctx context.Context, args []string) error {
 		if len(args) < 1 {
 			return fmt.Errorf("uri required: nefit get <uri>")
@@ -50,6 +71,91 @@ Examples:
 			return fmt.Errorf("GET request failed: %w", err)
 		}
 
+		if *getDecode {
+			data = decodeUIStatus(data)
+		}
+
+		if *getField != "" {
+			value, err := lookupField(data, *getField)
+			if err != nil {
+				return err
+			}
+			out, err := formatField(value)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			return nil
+		}
+
 		return printJSON(data)
 	},
 }
+
+// decodeUIStatus applies types.DecodeUIStatusKeys to the "value" object of a uiStatus
+// response. Other responses are returned unchanged.
+func decodeUIStatus(data interface{}) interface{} {
+	doc, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+	value, ok := doc["value"].(map[string]interface{})
+	if !ok || (doc["id"] != nil && doc["id"] != types.URIStatus) {
+		return data
+	}
+
+	decoded := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		decoded[k] = v
+	}
+	decoded["value"] = types.DecodeUIStatusKeys(value)
+	return decoded
+}
+
+// lookupField navigates a decoded JSON value along a dot-separated path.
+// Object members are addressed by key and array elements by index.
+func lookupField(data interface{}, path string) (interface{}, error) {
+	keys := strings.Split(path, ".")
+	current := data
+	for i, key := range keys {
+		at := strings.Join(keys[:i+1], ".")
+
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("field %q not found", at)
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, fmt.Errorf("field %q not found: invalid index into array of %d", at, len(v))
+			}
+			current = v[idx]
+		default:
+			return nil, fmt.Errorf("field %q not found: %T has no fields", at, current)
+		}
+	}
+	return current, nil
+}
+
+// formatField renders a looked-up value: scalars unquoted, objects and arrays as JSON.
+func formatField(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "null", nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return string(data), nil
+	}
+}
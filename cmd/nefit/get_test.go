@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLookupField(t *testing.T) {
+	var data interface{}
+	sample := `{
+		"id": "/ecus/rrc/uiStatus",
+		"value": {
+			"IHT": "20.50",
+			"TSP": 21,
+			"ESI": false,
+			"nested": {"a": [{"d": "01-01-2024"}, {"d": "02-01-2024"}]}
+		}
+	}`
+	if err := json.Unmarshal([]byte(sample), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "id", want: "/ecus/rrc/uiStatus"},
+		{path: "value.IHT", want: "20.50"},
+		{path: "value.TSP", want: "21"},
+		{path: "value.ESI", want: "false"},
+		{path: "value.nested.a.1.d", want: "02-01-2024"},
+		{path: "value.nested.a.0", want: `{"d":"01-01-2024"}`},
+		{path: "value.missing", wantErr: true},
+		{path: "value.IHT.deeper", wantErr: true},
+		{path: "value.nested.a.5", wantErr: true},
+		{path: "value.nested.a.x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, err := lookupField(data, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupField(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := formatField(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("lookupField(%q) = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeUIStatus(t *testing.T) {
+	var status, pressure interface{}
+	if err := json.Unmarshal([]byte(`{"id":"/ecus/rrc/uiStatus","type":"uiUpdate","value":{"IHT":"20.50","BAI":"No"}}`), &status); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"/system/appliance/systemPressure","value":{"IHT":"1"}}`), &pressure); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := decodeUIStatus(status)
+	temp, err := lookupField(decoded, "value.in_house_temp")
+	if err != nil || temp != 20.5 {
+		t.Errorf("Expected the decoded indoor temperature, got %v (err %v)", temp, err)
+	}
+	if v, _ := lookupField(decoded, "value.boiler_indicator"); v != "off" {
+		t.Errorf("Expected the boiler indicator to be decoded, got %v", v)
+	}
+	if v, _ := lookupField(decoded, "type"); v != "uiUpdate" {
+		t.Errorf("Expected the other response fields to be kept, got %v", v)
+	}
+	if _, err := lookupField(status, "value.IHT"); err != nil {
+		t.Error("Expected the original response to be left unchanged")
+	}
+
+	if _, err := lookupField(decodeUIStatus(pressure), "value.IHT"); err != nil {
+		t.Error("Expected responses for other URIs not to be decoded")
+	}
+}
@@ -3,12 +3,15 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"github.com/kradalby/nefit-go/client"
+	"github.com/kradalby/nefit-go/types"
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
 
@@ -19,8 +22,12 @@ var (
 	accessKey    = rootFlagSet.String("access-key", os.Getenv("NEFIT_ACCESS_KEY"), "Access key (or NEFIT_ACCESS_KEY env)")
 	password     = rootFlagSet.String("password", os.Getenv("NEFIT_PASSWORD"), "Password (or NEFIT_PASSWORD env)")
 	timeout      = rootFlagSet.Duration("timeout", 30*time.Second, "Request timeout")
+	connRetries  = rootFlagSet.Int("connect-retries", 2, "Retry a failed connection this many times within --timeout")
 	pretty       = rootFlagSet.Bool("pretty", false, "Pretty-print JSON output")
 	verbose      = rootFlagSet.Bool("verbose", false, "Verbose output")
+	dryRun       = rootFlagSet.Bool("dry-run", false, "Log write operations instead of sending them")
+	captureFile  = rootFlagSet.String("capture", "", "Append request/response pairs (serial redacted) to this file as JSON lines")
+	circuit      = rootFlagSet.String("circuit", types.DefaultCircuit, "Heating circuit for status and set commands (hc1, hc2, ...)")
 )
 
 func main() {
@@ -39,6 +46,7 @@ Environment variables:
 Examples:
   nefit status                      # Get system status
   nefit get /ecus/rrc/uiStatus     # Raw GET request
+  nefit explore /system             # List available resources
   nefit set temperature 21.5        # Set temperature to 21.5°C
   nefit pressure                    # Get system pressure`,
 		FlagSet: rootFlagSet,
@@ -47,8 +55,15 @@ Examples:
 			pressureCmd,
 			getCmd,
 			putCmd,
+			execCmd,
+			exploreCmd,
 			setCmd,
 			hotWaterCmd,
+			presenceCmd,
+			programCmd,
+			clockCmd,
+			updateCmd,
+			gasUsageCmd,
 			subscribeCmd,
 			versionCmd,
 		},
@@ -83,9 +98,31 @@ func createClient() (*client.Client, error) {
 		SerialNumber: *serialNumber,
 		AccessKey:    *accessKey,
 		Password:     *password,
+		DryRun:       *dryRun,
 	}
 
-	return client.NewClient(config)
+	c, err := client.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if *captureFile != "" {
+		f, err := os.OpenFile(*captureFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open capture file: %w", err)
+		}
+		c.SetCapture(f)
+		c.RegisterShutdownHook(func() error {
+			c.SetCapture(nil)
+			if err := f.Sync(); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("failed to flush capture file: %w", err)
+			}
+			return f.Close()
+		})
+	}
+
+	return c, nil
 }
 
 func printJSON(v interface{}) error {
@@ -110,11 +147,21 @@ func connectClient(c *client.Client) error {
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
 
+	status := io.Discard
 	if *verbose {
+		status = os.Stderr
 		fmt.Fprintln(os.Stderr, "Connecting to Nefit Easy...")
 	}
 
-	if err := c.Connect(ctx); err != nil {
+	if err := connectWithRetry(ctx, c, *connRetries, connectBackoff, status); err != nil {
+		switch {
+		case errors.Is(err, client.ErrAuthFailed):
+			return fmt.Errorf("connection failed (check your access key and password): %w", err)
+		case errors.Is(err, client.ErrNetwork):
+			return fmt.Errorf("connection failed (check your internet connection): %w", err)
+		case errors.Is(err, client.ErrServerUnavailable):
+			return fmt.Errorf("connection failed (backend unavailable, try again later): %w", err)
+		}
 		return fmt.Errorf("connection failed: %w", err)
 	}
 
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var presenceCmd = &ffcli.Command{
+	Name:       "presence",
+	ShortUsage: "nefit presence [home|away]",
+	ShortHelp:  "Get or set the occupancy flag",
+	LongHelp: `Get or set whether the thermostat considers the house occupied.
+
+Without arguments, shows the current presence.
+With 'home' or 'away', sets it (WRITE operation). With home entrance
+detection (HED) enabled, the next HED update may overwrite it.
+
+Examples:
+  nefit presence            # Get current presence
+  nefit presence away       # Mark the house as empty
+  nefit presence home       # Mark the house as occupied`,
+	Exec: func(ctx context.Context, args []string) error {
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+
+		if len(args) == 0 {
+			present, err := c.GetPresence(reqCtx)
+			if err != nil {
+				return err
+			}
+
+			status := "away"
+			if present {
+				status = "home"
+			}
+			fmt.Printf("Presence: %s\n", status)
+			return nil
+		}
+
+		arg := args[0]
+		var present bool
+
+		switch arg {
+		case "home":
+			present = true
+		case "away":
+			present = false
+		default:
+			return fmt.Errorf("invalid argument %q (must be 'home' or 'away')", arg)
+		}
+
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "Setting presence to %s...\n", arg)
+		}
+
+		if err := c.SetPresence(reqCtx, present); err != nil {
+			return err
+		}
+
+		fmt.Printf("OK - Presence set to %s\n", arg)
+		return nil
+	},
+}
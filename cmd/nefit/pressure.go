@@ -2,20 +2,31 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
 
+var (
+	pressureFlagSet = flag.NewFlagSet("pressure", flag.ExitOnError)
+	pressureAll     = pressureFlagSet.Bool("all", false, "Show every pressure the appliance reports, keyed by name")
+)
+
 var pressureCmd = &ffcli.Command{
 	Name:       "pressure",
-	ShortUsage: "nefit pressure",
+	ShortUsage: "nefit pressure [--all]",
 	ShortHelp:  "Get system pressure",
 	LongHelp: `Get the current system pressure.
 
+With --all, every pressure the appliance reports is shown, such as the
+hot water circuit pressure on systems that have a sensor for it.
+
 Example:
   nefit pressure
-  nefit pressure --pretty`,
+  nefit pressure --pretty
+  nefit pressure --all`,
+	FlagSet: pressureFlagSet,
 	Exec: func(ctx context.Context, args []string) error {
 		c, err := createClient()
 		if err != nil {
@@ -30,6 +41,14 @@ Example:
 		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
 		defer cancel()
 
+		if *pressureAll {
+			pressures, err := c.Pressures(reqCtx)
+			if err != nil {
+				return fmt.Errorf("failed to get pressures: %w", err)
+			}
+			return printJSON(pressures)
+		}
+
 		pressure, err := c.Pressure(reqCtx)
 		if err != nil {
 			return fmt.Errorf("failed to get pressure: %w", err)
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/kradalby/nefit-go/types"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var dayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+var programFlagSet = flag.NewFlagSet("program", flag.ExitOnError)
+
+var programCmd = &ffcli.Command{
+	Name:       "program",
+	ShortUsage: "nefit program <subcommand>",
+	ShortHelp:  "Read and edit heating programs",
+	LongHelp: `Read and edit the heating programs (weekly schedules).
+
+Available subcommands:
+  show [1|2]               - Show a program as a weekly table (default: active program)
+  set <1|2> <file.json>    - Upload a program from a JSON file (WRITE operation)
+  activate <1|2>           - Make a program the active schedule (WRITE operation)
+
+The JSON file must match the program format:
+  {"switchpoints": [{"day_of_week": 1, "time": "06:30", "temperature": 21}]}
+
+day_of_week is 0-6 (0=Sunday) and time is HH:MM.
+
+Examples:
+  nefit program show
+  nefit program show 2
+  nefit program set 1 schedule.json
+  nefit program activate 2`,
+	FlagSet: programFlagSet,
+	Subcommands: []*ffcli.Command{
+		programShowCmd,
+		programSetCmd,
+		programActivateCmd,
+	},
+	Exec: func(ctx context.Context, args []string) error {
+		return flag.ErrHelp
+	},
+}
+
+var programShowCmd = &ffcli.Command{
+	Name:       "show",
+	ShortUsage: "nefit program show [1|2]",
+	ShortHelp:  "Show a heating program as a weekly table",
+	Exec: func(ctx context.Context, args []string) error {
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+
+		var program int
+		if len(args) > 0 {
+			program, err = parseProgramNumber(args[0])
+			if err != nil {
+				return err
+			}
+		} else {
+			program, err = c.ActiveProgram(reqCtx)
+			if err != nil {
+				return err
+			}
+		}
+
+		p, err := c.Program(reqCtx, program)
+		if err != nil {
+			return err
+		}
+
+		active := ""
+		if p.Active {
+			active = " (active)"
+		}
+		fmt.Printf("Program %d%s\n\n", program, active)
+
+		return renderProgramTable(os.Stdout, p)
+	},
+}
+
+var programSetCmd = &ffcli.Command{
+	Name:       "set",
+	ShortUsage: "nefit program set <1|2> <file.json>",
+	ShortHelp:  "Upload a heating program from a JSON file",
+	Exec: func(ctx context.Context, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("program and file required: nefit program set <1|2> <file.json>")
+		}
+
+		program, err := parseProgramNumber(args[0])
+		if err != nil {
+			return err
+		}
+
+		p, err := loadProgramFile(args[1])
+		if err != nil {
+			return err
+		}
+
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "Uploading %d switchpoints to program %d...\n", len(p.Switchpoints), program)
+		}
+
+		if err := c.SetProgram(reqCtx, program, p); err != nil {
+			return err
+		}
+
+		fmt.Printf("OK - Program %d updated\n", program)
+		return nil
+	},
+}
+
+var programActivateCmd = &ffcli.Command{
+	Name:       "activate",
+	ShortUsage: "nefit program activate <1|2>",
+	ShortHelp:  "Make a heating program the active schedule",
+	Exec: func(ctx context.Context, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("program required: nefit program activate <1|2>")
+		}
+
+		program, err := parseProgramNumber(args[0])
+		if err != nil {
+			return err
+		}
+
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+
+		if err := c.ActivateProgram(reqCtx, program); err != nil {
+			return err
+		}
+
+		fmt.Printf("OK - Program %d activated\n", program)
+		return nil
+	},
+}
+
+func parseProgramNumber(arg string) (int, error) {
+	program, err := strconv.Atoi(arg)
+	if err != nil || (program != 1 && program != 2) {
+		return 0, fmt.Errorf("invalid program %q (must be 1 or 2)", arg)
+	}
+	return program, nil
+}
+
+// loadProgramFile reads and validates a program JSON file.
+func loadProgramFile(path string) (*types.Program, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read program file: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var p types.Program
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid program JSON: %w", err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid program: %w", err)
+	}
+
+	return &p, nil
+}
+
+// renderProgramTable writes the program grouped by day, one switchpoint per row.
+func renderProgramTable(w io.Writer, p *types.Program) error {
+	byDay := make([][]types.ProgramSwitchpoint, len(dayNames))
+	for _, sp := range p.Switchpoints {
+		if sp.DayOfWeek >= 0 && sp.DayOfWeek < len(dayNames) {
+			byDay[sp.DayOfWeek] = append(byDay[sp.DayOfWeek], sp)
+		}
+	}
+
+	unit := p.TemperatureUnit
+	if unit == "" {
+		unit = types.Celsius
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DAY\tTIME\tTEMPERATURE")
+
+	for day, switchpoints := range byDay {
+		for i, sp := range switchpoints {
+			name := ""
+			if i == 0 {
+				name = dayNames[day]
+			}
+			if sp.Preset != "" {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", name, sp.Time, sp.Preset)
+				continue
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%.1f°%s\n", name, sp.Time, sp.Temperature, unit)
+		}
+	}
+
+	return tw.Flush()
+}
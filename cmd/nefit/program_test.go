@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+func TestRenderProgramTable(t *testing.T) {
+	p := &types.Program{
+		Switchpoints: []types.ProgramSwitchpoint{
+			{DayOfWeek: 1, Time: "06:30", Temperature: 21},
+			{DayOfWeek: 1, Time: "22:00", Temperature: 16},
+			{DayOfWeek: 0, Time: "08:00", Temperature: 20.5},
+		},
+	}
+
+	var sb strings.Builder
+	if err := renderProgramTable(&sb, p); err != nil {
+		t.Fatalf("renderProgramTable failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	want := [][]string{
+		{"DAY", "TIME", "TEMPERATURE"},
+		{"Sunday", "08:00", "20.5°C"},
+		{"Monday", "06:30", "21.0°C"},
+		{"22:00", "16.0°C"},
+	}
+
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(want), len(lines), sb.String())
+	}
+	for i, fields := range want {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(fields, " ") {
+			t.Errorf("Line %d: expected %v, got %v", i, fields, got)
+		}
+	}
+
+	sb.Reset()
+	fahrenheit := &types.Program{
+		Switchpoints:    []types.ProgramSwitchpoint{{DayOfWeek: 1, Time: "06:30", Temperature: 69.8}},
+		TemperatureUnit: types.Fahrenheit,
+	}
+	if err := renderProgramTable(&sb, fahrenheit); err != nil {
+		t.Fatalf("renderProgramTable failed: %v", err)
+	}
+	if !strings.Contains(sb.String(), "69.8°F") {
+		t.Errorf("Expected the temperature in Fahrenheit, got:\n%s", sb.String())
+	}
+}
+
+func TestLoadProgramFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `{"switchpoints":[{"day_of_week":1,"time":"06:30","temperature":21}]}`,
+		},
+		{
+			name:    "invalid day",
+			content: `{"switchpoints":[{"day_of_week":7,"time":"06:30","temperature":21}]}`,
+			wantErr: "day_of_week",
+		},
+		{
+			name:    "invalid time",
+			content: `{"switchpoints":[{"day_of_week":1,"time":"6:30","temperature":21}]}`,
+			wantErr: "HH:MM",
+		},
+		{
+			name:    "out of range time",
+			content: `{"switchpoints":[{"day_of_week":1,"time":"25:00","temperature":21}]}`,
+			wantErr: "HH:MM",
+		},
+		{
+			name:    "unknown field",
+			content: `{"switchpoints":[{"day":1,"time":"06:30","temperature":21}]}`,
+			wantErr: "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "program.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := loadProgramFile(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -3,14 +3,20 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
 
+var (
+	putFlagSet = flag.NewFlagSet("put", flag.ExitOnError)
+	putForce   = putFlagSet.Bool("force", false, "Send the data even if it fails validation for a known URI")
+)
+
 var putCmd = &ffcli.Command{
 	Name:       "put",
-	ShortUsage: "nefit put <uri> <json-data>",
+	ShortUsage: "nefit put [--force] <uri> <json-data>",
 	ShortHelp:  "Perform a raw PUT request (WRITE operation - use carefully!)",
 	LongHelp: `Perform a raw PUT request to any endpoint.
 
@@ -20,12 +26,18 @@ var putCmd = &ffcli.Command{
 The data should be valid JSON, typically in the format:
   {"value": <your-value>}
 
+For known URIs the value is checked before sending, e.g. setpoints must be
+numbers in range and the user mode "manual" or "clock". Use --force to skip
+the check.
+
 Examples:
   nefit put /heatingCircuits/hc1/temperatureRoomManual '{"value":21.5}'
-  nefit put /ecus/rrc/usermode '{"value":"manual"}'
+  nefit put /heatingCircuits/hc1/usermode '{"value":"manual"}'
+  nefit put --force /heatingCircuits/hc1/temperatureRoomManual '{"value":35}'
 
 For simple values, you can also use:
   nefit set temperature 21.5    # Easier than using PUT directly`,
+	FlagSet: putFlagSet,
 	Exec: func(ctx context.Context, args []string) error {
 		if len(args) < 2 {
 			return fmt.Errorf("uri and data required: nefit put <uri> <json-data>")
@@ -40,6 +52,12 @@ For simple values, you can also use:
 			return fmt.Errorf("invalid JSON data: %w", err)
 		}
 
+		if !*putForce {
+			if err := validatePut(uri, data); err != nil {
+				return fmt.Errorf("%w (use --force to send anyway)", err)
+			}
+		}
+
 		c, err := createClient()
 		if err != nil {
 			return err
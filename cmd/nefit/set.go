@@ -7,6 +7,8 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/kradalby/nefit-go/client"
+	"github.com/kradalby/nefit-go/types"
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
 
@@ -29,7 +31,8 @@ Examples:
   nefit set temperature 21.5
   nefit set temperature 22
   nefit set user-mode manual
-  nefit set user-mode clock`,
+  nefit set user-mode clock
+  nefit --circuit hc2 set temperature 19`,
 	FlagSet: setFlagSet,
 	Subcommands: []*ffcli.Command{
 		setTemperatureCmd,
@@ -70,6 +73,11 @@ Examples:
 			return fmt.Errorf("temperature %v is outside reasonable range (5-30°C)", temp)
 		}
 
+		ctx, err = withCircuitFlag(ctx)
+		if err != nil {
+			return err
+		}
+
 		c, err := createClient()
 		if err != nil {
 			return err
@@ -83,17 +91,21 @@ Examples:
 		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
 		defer cancel()
 
-		if *verbose {
-			fmt.Fprintf(os.Stderr, "Setting temperature to %.1f°C...\n", temp)
-		}
+		return runSetTemperature(reqCtx, c, temp)
+	},
+}
 
-		if err := c.SetTemperature(reqCtx, temp); err != nil {
-			return fmt.Errorf("failed to set temperature: %w", err)
-		}
+func runSetTemperature(ctx context.Context, c heatingClient, temp float64) error {
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "Setting temperature of %s to %.1f°C...\n", client.CircuitFromContext(ctx), temp)
+	}
 
-		fmt.Printf("OK - Temperature set to %.1f°C\n", temp)
-		return nil
-	},
+	if err := c.SetTemperature(ctx, temp); err != nil {
+		return fmt.Errorf("failed to set temperature: %w", err)
+	}
+
+	fmt.Printf("OK - Temperature set to %.1f°C\n", temp)
+	return nil
 }
 
 var setUserModeCmd = &ffcli.Command{
@@ -113,9 +125,14 @@ Examples:
 			return fmt.Errorf("mode required: nefit set user-mode <manual|clock>")
 		}
 
-		mode := args[0]
-		if mode != "manual" && mode != "clock" {
-			return fmt.Errorf("invalid mode %q (must be 'manual' or 'clock')", mode)
+		mode, err := types.ParseUserMode(args[0])
+		if err != nil {
+			return err
+		}
+
+		ctx, err = withCircuitFlag(ctx)
+		if err != nil {
+			return err
 		}
 
 		c, err := createClient()
@@ -131,15 +148,19 @@ Examples:
 		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
 		defer cancel()
 
-		if *verbose {
-			fmt.Fprintf(os.Stderr, "Setting user mode to %s...\n", mode)
-		}
+		return runSetUserMode(reqCtx, c, mode)
+	},
+}
 
-		if err := c.SetUserMode(reqCtx, mode); err != nil {
-			return fmt.Errorf("failed to set user mode: %w", err)
-		}
+func runSetUserMode(ctx context.Context, c heatingClient, mode types.UserMode) error {
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "Setting user mode of %s to %s...\n", client.CircuitFromContext(ctx), mode)
+	}
 
-		fmt.Printf("OK - User mode set to %s\n", mode)
-		return nil
-	},
+	if err := c.SetUserMode(ctx, mode); err != nil {
+		return fmt.Errorf("failed to set user mode: %w", err)
+	}
+
+	fmt.Printf("OK - User mode set to %s\n", mode)
+	return nil
 }
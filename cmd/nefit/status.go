@@ -29,9 +29,15 @@ var statusCmd = &ffcli.Command{
 Example:
   nefit status
   nefit status --pretty
-  nefit status --skip-outdoor`,
+  nefit status --skip-outdoor
+  nefit --circuit hc2 status`,
 	FlagSet: statusFlagSet,
 	Exec: func(ctx context.Context, args []string) error {
+		ctx, err := withCircuitFlag(ctx)
+		if err != nil {
+			return err
+		}
+
 		c, err := createClient()
 		if err != nil {
 			return err
@@ -45,11 +51,15 @@ Example:
 		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
 		defer cancel()
 
-		status, err := c.Status(reqCtx, !*statusSkipOutdoor)
-		if err != nil {
-			return fmt.Errorf("failed to get status: %w", err)
-		}
-
-		return printJSON(status)
+		return runStatus(reqCtx, c, !*statusSkipOutdoor)
 	},
 }
+
+func runStatus(ctx context.Context, c heatingClient, includeOutdoorTemp bool) error {
+	status, err := c.Status(ctx, includeOutdoorTemp)
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+
+	return printJSON(status)
+}
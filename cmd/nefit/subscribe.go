@@ -3,18 +3,64 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/kradalby/nefit-go/client"
+	"github.com/kradalby/nefit-go/types"
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
 
+var (
+	subscribeFlagSet = flag.NewFlagSet("subscribe", flag.ExitOnError)
+	subscribeRaw     = subscribeFlagSet.Bool("raw", false, "Do not infer the URI of notifications that lack one")
+	subscribeJSONL   = subscribeFlagSet.Bool("jsonl", false, "Print one compact JSON object per event (timestamp, uri, data)")
+	subscribeNoRetry = subscribeFlagSet.Bool("no-reconnect", false, "Exit when the connection is lost instead of reconnecting")
+)
+
+// subscribeEvent is a push notification as printed by --jsonl.
+type subscribeEvent struct {
+	Timestamp   string      `json:"timestamp"`
+	URI         string      `json:"uri"`
+	URIInferred bool        `json:"uri_inferred,omitempty"`
+	Data        interface{} `json:"data"`
+}
+
+// formatConnectionNotice renders a connection marker of the event stream as a status line.
+func formatConnectionNotice(ts time.Time, ev *client.ConnectionEvent) string {
+	timestamp := ts.Format("15:04:05")
+	switch {
+	case ev.State == client.ConnectionLost:
+		return fmt.Sprintf("[%s] Connection lost (%v), reconnecting...\n", timestamp, ev.Err)
+	case ev.Attempts > 0:
+		return fmt.Sprintf("[%s] Reconnected after %d attempt(s), resuming events\n", timestamp, ev.Attempts)
+	default:
+		return fmt.Sprintf("[%s] Reconnected, resuming events\n", timestamp)
+	}
+}
+
+// formatEventLine renders a notification as a single newline-terminated JSON object.
+func formatEventLine(ts time.Time, uri string, inferred bool, data interface{}) ([]byte, error) {
+	line, err := json.Marshal(subscribeEvent{
+		Timestamp:   ts.Format(time.RFC3339Nano),
+		URI:         uri,
+		URIInferred: inferred,
+		Data:        data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
 var subscribeCmd = &ffcli.Command{
 	Name:       "subscribe",
-	ShortUsage: "nefit subscribe",
+	ShortUsage: "nefit subscribe [--raw] [--jsonl] [--no-reconnect]",
 	ShortHelp:  "Subscribe to all backend push notifications (debug)",
 	LongHelp: `Subscribe to all backend push notifications and print them as they arrive.
 
@@ -25,11 +71,26 @@ any updates from the Nefit Easy backend. This can include:
   - Status updates
   - Any other state changes from the thermostat
 
+Notifications without a URI are labelled with the endpoint their payload
+looks like (e.g. a payload with "IHT" is the uiStatus), marked as inferred.
+Use --raw to print them unlabelled.
+
+With --jsonl, each event is written to stdout as soon as it arrives as one
+compact JSON object per line ({"timestamp", "uri", "data"}), for piping into
+log processors. Status messages then go to stderr.
+
+If the connection drops, the command reconnects with increasing backoff,
+reporting the drop and the recovery on stderr, and resumes printing events. Use
+--no-reconnect to exit with an error instead.
+
 The command will run until you press Ctrl+C.
 
 Example:
   nefit subscribe
-  nefit subscribe --pretty`,
+  nefit --pretty subscribe
+  nefit subscribe --raw
+  nefit subscribe --jsonl | jq .data`,
+	FlagSet: subscribeFlagSet,
 	Exec: func(ctx context.Context, args []string) error {
 		c, err := createClient()
 		if err != nil {
@@ -41,52 +102,94 @@ Example:
 			return err
 		}
 
-		fmt.Println("Connected and subscribed to push notifications.")
-		fmt.Println("Listening for updates... (press Ctrl+C to exit)")
-		fmt.Println()
-
-		// Subscribe to all events
-		c.Subscribe(func(uri string, data interface{}) {
-			timestamp := time.Now().Format("15:04:05")
-
-			if *pretty {
-				// Pretty print JSON
-				jsonData, err := json.MarshalIndent(map[string]interface{}{
-					"timestamp": timestamp,
-					"uri":       uri,
-					"data":      data,
-				}, "", "  ")
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "[%s] ERROR: Failed to format data: %v\n", timestamp, err)
-					return
-				}
-				fmt.Println(string(jsonData))
-			} else {
-				// Compact print
-				jsonData, err := json.Marshal(data)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "[%s] ERROR: Failed to format data: %v\n", timestamp, err)
-					return
-				}
-				if uri != "" {
-					fmt.Printf("[%s] %s: %s\n", timestamp, uri, string(jsonData))
-				} else {
-					fmt.Printf("[%s] %s\n", timestamp, string(jsonData))
-				}
-			}
-		})
+		// Keep stdout machine-readable in JSON-lines mode.
+		status := os.Stdout
+		if *subscribeJSONL {
+			status = os.Stderr
+		}
+		fmt.Fprintln(status, "Connected and subscribed to push notifications.")
+		fmt.Fprintln(status, "Listening for updates... (press Ctrl+C to exit)")
+		fmt.Fprintln(status)
 
-		// Wait for interrupt signal
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		streamCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-		select {
-		case <-sigChan:
-			fmt.Println("\nReceived interrupt, shutting down...")
-		case <-ctx.Done():
-			fmt.Println("\nContext cancelled, shutting down...")
+		for n := range c.StreamEvents(streamCtx, client.WithConnectionMarkers()) {
+			if n.Connection == nil {
+				printEvent(n.URI, n.Data)
+				continue
+			}
+			if n.Connection.State == client.ConnectionLost && *subscribeNoRetry {
+				return fmt.Errorf("connection lost: %w", n.Connection.Err)
+			}
+			fmt.Fprint(os.Stderr, formatConnectionNotice(time.Now(), n.Connection))
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			fmt.Fprintln(status, "\nContext cancelled, shutting down...")
+		case streamCtx.Err() != nil:
+			fmt.Fprintln(status, "\nReceived interrupt, shutting down...")
+		default:
+			return errors.New("event stream closed")
 		}
 
 		return nil
 	},
 }
+
+// printEvent writes a push notification to stdout in the format selected by the flags.
+func printEvent(uri string, data interface{}) {
+	now := time.Now()
+	timestamp := now.Format("15:04:05")
+
+	inferred := false
+	if uri == "" && !*subscribeRaw {
+		uri, inferred = types.InferURI(data)
+	}
+
+	if *subscribeJSONL {
+		line, err := formatEventLine(now, uri, inferred, data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] ERROR: Failed to format data: %v\n", timestamp, err)
+			return
+		}
+		// os.Stdout is unbuffered, so each event reaches the pipe with this single write.
+		_, _ = os.Stdout.Write(line)
+		return
+	}
+
+	if *pretty {
+		// Pretty print JSON
+		out := map[string]interface{}{
+			"timestamp": timestamp,
+			"uri":       uri,
+			"data":      data,
+		}
+		if inferred {
+			out["uri_inferred"] = true
+		}
+		jsonData, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] ERROR: Failed to format data: %v\n", timestamp, err)
+			return
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	// Compact print
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] ERROR: Failed to format data: %v\n", timestamp, err)
+		return
+	}
+	switch {
+	case inferred:
+		fmt.Printf("[%s] %s (inferred): %s\n", timestamp, uri, string(jsonData))
+	case uri != "":
+		fmt.Printf("[%s] %s: %s\n", timestamp, uri, string(jsonData))
+	default:
+		fmt.Printf("[%s] %s\n", timestamp, string(jsonData))
+	}
+}
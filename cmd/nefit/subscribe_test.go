@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kradalby/nefit-go/client"
+)
+
+func TestFormatEventLine(t *testing.T) {
+	ts := time.Date(2024, time.January, 15, 7, 30, 0, 500, time.UTC)
+
+	line, err := formatEventLine(ts, "/ecus/rrc/uiStatus", true, map[string]interface{}{"value": map[string]interface{}{"IHT": "20.5"}})
+	if err != nil {
+		t.Fatalf("formatEventLine failed: %v", err)
+	}
+
+	want := `{"timestamp":"2024-01-15T07:30:00.0000005Z","uri":"/ecus/rrc/uiStatus","uri_inferred":true,"data":{"value":{"IHT":"20.5"}}}` + "\n"
+	if string(line) != want {
+		t.Errorf("formatEventLine =\n%s\nwant\n%s", line, want)
+	}
+
+	line, err = formatEventLine(ts, "", false, "multi\nline")
+	if err != nil {
+		t.Fatalf("formatEventLine failed: %v", err)
+	}
+	if bytes.Count(line, []byte("\n")) != 1 || !bytes.HasSuffix(line, []byte("\n")) {
+		t.Errorf("Expected exactly one trailing newline, got %q", line)
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(line, &event); err != nil {
+		t.Fatalf("Line is not valid JSON: %v", err)
+	}
+	if _, ok := event["uri_inferred"]; ok {
+		t.Error("uri_inferred must be omitted when the URI was not inferred")
+	}
+	if event["data"] != "multi\nline" {
+		t.Errorf("Unexpected data %v", event["data"])
+	}
+}
+
+func TestFormatConnectionNotice(t *testing.T) {
+	ts := time.Date(2024, time.January, 15, 7, 30, 5, 0, time.UTC)
+
+	tests := []struct {
+		event client.ConnectionEvent
+		want  string
+	}{
+		{
+			event: client.ConnectionEvent{State: client.ConnectionLost, Err: errors.New("receive failed: EOF")},
+			want:  "[07:30:05] Connection lost (receive failed: EOF), reconnecting...\n",
+		},
+		{
+			event: client.ConnectionEvent{State: client.ConnectionRestored, Attempts: 3},
+			want:  "[07:30:05] Reconnected after 3 attempt(s), resuming events\n",
+		},
+		{
+			event: client.ConnectionEvent{State: client.ConnectionRestored},
+			want:  "[07:30:05] Reconnected, resuming events\n",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := formatConnectionNotice(ts, &tt.event); got != tt.want {
+			t.Errorf("formatConnectionNotice(%+v) = %q, want %q", tt.event, got, tt.want)
+		}
+	}
+}
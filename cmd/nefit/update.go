@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var updateCmd = &ffcli.Command{
+	Name:       "update",
+	ShortUsage: "nefit update",
+	ShortHelp:  "Show firmware update availability",
+	LongHelp: `Show the installed firmware version and whether an update is available.
+
+Example:
+  nefit update`,
+	Exec: func(ctx context.Context, args []string) error {
+		c, err := createClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close() //nolint:errcheck
+
+		if err := connectClient(c); err != nil {
+			return err
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+
+		status, err := c.UpdateStatus(reqCtx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Firmware: %s\n", orUnknown(status.CurrentVersion))
+		fmt.Printf("State:    %s\n", status.State)
+		if status.Available {
+			fmt.Printf("Update:   %s available\n", orUnknown(status.AvailableVersion))
+		}
+		return nil
+	},
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
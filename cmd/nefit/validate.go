@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// putValidator checks the "value" of a PUT payload for one endpoint.
+type putValidator func(value interface{}) error
+
+// putValidators maps the known writable URIs to a check of their value.
+// Heating circuit URIs are listed for hc1 and apply to every circuit.
+var putValidators = map[string]putValidator{
+	types.URIUserMode:                   oneOf(string(types.UserModeManual), string(types.UserModeClock)),
+	types.URIManualSetpoint:             numberIn(types.MinSetpoint, types.MaxSetpoint),
+	types.URIManualTempOverrideTemp:     numberIn(types.MinSetpoint, types.MaxSetpoint),
+	types.URIPresetComfort:              numberIn(types.MinSetpoint, types.MaxSetpoint),
+	types.URIPresetEco:                  numberIn(types.MinSetpoint, types.MaxSetpoint),
+	types.URIManualTempOverrideStatus:   oneOf("on", "off"),
+	types.URIHotWaterClockMode:          oneOf("on", "off"),
+	types.URIHotWaterManualMode:         oneOf("on", "off"),
+	types.URIDisplayStandby:             oneOf("on", "off"),
+	types.URIFireplaceMode:              oneOf("on", "off"),
+	types.URIFireplaceDuration:          isNumber,
+	types.URIManualTempOverrideDuration: isNumber,
+	types.URIPresence:                   oneOf("on", "off"),
+	types.URIUpdateState:                oneOf("start"),
+	// The hot water range differs per appliance, so only the type is checked.
+	types.URIHotWaterClockTemp:  isNumber,
+	types.URIHotWaterManualTemp: isNumber,
+	types.URIDisplayBrightness:  numberIn(types.MinBrightness, types.MaxBrightness),
+	types.URIActiveProgram:      numberIn(1, 2),
+	types.URIOutdoorSource:      oneOf(types.OutdoorSourcePhysical, types.OutdoorSourceVirtual),
+	types.URILocationLatitude:   numberIn(-90, 90),
+	types.URILocationLongitude:  numberIn(-180, 180),
+	types.URIProgram1:           isList,
+	types.URIProgram2:           isList,
+	types.URIDateTime:           isString,
+}
+
+var heatingCircuitURI = regexp.MustCompile(`^/heatingCircuits/hc[0-9]+/`)
+
+// validatePut checks data against the validator for uri, if there is one. URIs without
+// a validator are accepted as they are.
+func validatePut(uri string, data interface{}) error {
+	validate, ok := putValidators[heatingCircuitURI.ReplaceAllString(uri, "/heatingCircuits/"+types.DefaultCircuit+"/")]
+	if !ok {
+		return nil
+	}
+
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s expects an object like {\"value\": ...}, got %s", uri, jsonKind(data))
+	}
+	value, ok := object["value"]
+	if !ok {
+		return fmt.Errorf("%s expects a \"value\" field", uri)
+	}
+
+	if err := validate(value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", uri, err)
+	}
+	return nil
+}
+
+func oneOf(allowed ...string) putValidator {
+	return func(value interface{}) error {
+		s, ok := value.(string)
+		if ok {
+			for _, a := range allowed {
+				if s == a {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("got %v, want one of %q", value, allowed)
+	}
+}
+
+func numberIn(lo, hi float64) putValidator {
+	return func(value interface{}) error {
+		if err := isNumber(value); err != nil {
+			return err
+		}
+		if v := value.(float64); v < lo || v > hi {
+			return fmt.Errorf("%v out of range %v-%v", v, lo, hi)
+		}
+		return nil
+	}
+}
+
+func isNumber(value interface{}) error {
+	if _, ok := value.(float64); !ok {
+		return fmt.Errorf("want a number, got %s", jsonKind(value))
+	}
+	return nil
+}
+
+func isString(value interface{}) error {
+	if _, ok := value.(string); !ok {
+		return fmt.Errorf("want a string, got %s", jsonKind(value))
+	}
+	return nil
+}
+
+func isList(value interface{}) error {
+	if _, ok := value.([]interface{}); !ok {
+		return fmt.Errorf("want a list, got %s", jsonKind(value))
+	}
+	return nil
+}
+
+// jsonKind names the JSON type of a decoded value for error messages.
+func jsonKind(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidatePut(t *testing.T) {
+	tests := []struct {
+		uri     string
+		data    string
+		wantErr string
+	}{
+		{"/heatingCircuits/hc1/temperatureRoomManual", `{"value":21.5}`, ""},
+		{"/heatingCircuits/hc2/temperatureRoomManual", `{"value":21.5}`, ""},
+		{"/heatingCircuits/hc1/temperatureRoomManual", `{"value":"manual"}`, "want a number"},
+		{"/heatingCircuits/hc2/temperatureRoomManual", `{"value":"21"}`, "want a number"},
+		{"/heatingCircuits/hc1/temperatureRoomManual", `{"value":45}`, "out of range"},
+		{"/heatingCircuits/hc1/temperatureRoomManual", `21.5`, "expects an object"},
+		{"/heatingCircuits/hc1/temperatureRoomManual", `{"temperature":21.5}`, `"value" field`},
+		{"/heatingCircuits/hc1/usermode", `{"value":"clock"}`, ""},
+		{"/heatingCircuits/hc1/usermode", `{"value":"off"}`, "want one of"},
+		{"/heatingCircuits/hc1/usermode", `{"value":1}`, "want one of"},
+		{"/dhwCircuits/dhwA/dhwOperationClockMode", `{"value":"on"}`, ""},
+		{"/dhwCircuits/dhwA/dhwOperationClockMode", `{"value":true}`, "want one of"},
+		{"/dhwCircuits/dhwA/dhwTemperatureManualMode", `{"value":70}`, ""},
+		{"/ecus/rrc/display/brightness", `{"value":11}`, "out of range"},
+		{"/ecus/rrc/userprogram/activeprogram", `{"value":2}`, ""},
+		{"/ecus/rrc/userprogram/program1", `{"value":{}}`, "want a list"},
+		{"/system/location/latitude", `{"value":52.1}`, ""},
+		{"/system/sensors/outdoorTemperatureSource", `{"value":"sensor"}`, "want one of"},
+		// Unknown URIs are not checked.
+		{"/some/unknown/endpoint", `"anything"`, ""},
+	}
+
+	for _, tt := range tests {
+		var data interface{}
+		if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+			t.Fatal(err)
+		}
+
+		err := validatePut(tt.uri, data)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("validatePut(%s, %s) = %v, want nil", tt.uri, tt.data, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("validatePut(%s, %s) = %v, want error containing %q", tt.uri, tt.data, err, tt.wantErr)
+		}
+	}
+}
@@ -6,30 +6,121 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Magic key used by Bosch/Nefit protocol
 const magicHex = "58f18d70f667c9c79ef7de435bf0f9b1553bbb6e61816212ab80e5b0d351fbb1"
 
+// magicLength is the length in bytes of a decoded magic key.
+const magicLength = 32
+
 // Encryptor handles AES-256-ECB encryption/decryption for the Nefit Easy protocol.
 type Encryptor struct {
-	key []byte
+	key     []byte
+	deriver KeyDeriver
+
+	// fallbacks are previous keys tried in order when the primary key does not
+	// decrypt to plausible text, e.g. for data encrypted before a password change.
+	mu        sync.RWMutex
+	fallbacks [][]byte
+}
+
+// KeyDeriver derives the AES key from the device credentials.
+// Implementations allow supporting product lines with a different derivation.
+type KeyDeriver interface {
+	DeriveKey(serialNumber, accessKey, password string) ([]byte, error)
+}
+
+// MD5KeyDeriver implements the Nefit Easy key derivation:
+// MD5(accessKey + Magic) + MD5(Magic + password)
+type MD5KeyDeriver struct {
+	Magic []byte
+}
+
+// DeriveKey returns the 32-byte key for the given credentials.
+func (d MD5KeyDeriver) DeriveKey(serialNumber, accessKey, password string) ([]byte, error) {
+	return generateKey(d.Magic, accessKey, password), nil
+}
+
+// DefaultKeyDeriver returns the key deriver used by Nefit Easy devices.
+func DefaultKeyDeriver() (KeyDeriver, error) {
+	return magicKeyDeriver(magicHex)
+}
+
+// magicKeyDeriver returns an MD5KeyDeriver for a hex-encoded magic key.
+func magicKeyDeriver(hexKey string) (KeyDeriver, error) {
+	magic, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode magic key: %w", err)
+	}
+	if len(magic) != magicLength {
+		return nil, fmt.Errorf("magic key must be %d bytes, got %d", magicLength, len(magic))
+	}
+	return MD5KeyDeriver{Magic: magic}, nil
 }
 
 // NewEncryptor creates an encryptor initialized with a key derived from the provided credentials.
 func NewEncryptor(serialNumber, accessKey, password string) (*Encryptor, error) {
-	magic, err := hex.DecodeString(magicHex)
+	deriver, err := DefaultKeyDeriver()
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode magic key: %w", err)
+		return nil, err
+	}
+
+	return NewEncryptorWithDeriver(deriver, serialNumber, accessKey, password)
+}
+
+// NewEncryptorWithMagic creates an encryptor like NewEncryptor, but with the hex-encoded
+// magic key of another Bosch backend instead of the Nefit Easy one. The key must decode
+// to 32 bytes.
+func NewEncryptorWithMagic(magicHex, serialNumber, accessKey, password string) (*Encryptor, error) {
+	deriver, err := magicKeyDeriver(magicHex)
+	if err != nil {
+		return nil, err
 	}
 
-	key := generateKey(magic, accessKey, password)
+	return NewEncryptorWithDeriver(deriver, serialNumber, accessKey, password)
+}
+
+// NewEncryptorWithDeriver creates an encryptor using a custom key derivation.
+// The derived key must be a valid AES key length (16, 24 or 32 bytes).
+func NewEncryptorWithDeriver(deriver KeyDeriver, serialNumber, accessKey, password string) (*Encryptor, error) {
+	key, err := deriver.DeriveKey(serialNumber, accessKey, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	if _, err := aes.NewCipher(key); err != nil {
+		return nil, fmt.Errorf("invalid derived key: %w", err)
+	}
 
 	return &Encryptor{
-		key: key,
+		key:     key,
+		deriver: deriver,
 	}, nil
 }
 
+// AddFallbackKey registers the key for previous credentials, derived the same way as
+// the primary key. Decrypt tries fallback keys in the order they were added when the
+// primary key does not yield valid text. Encrypt always uses the primary key.
+func (e *Encryptor) AddFallbackKey(serialNumber, accessKey, password string) error {
+	key, err := e.deriver.DeriveKey(serialNumber, accessKey, password)
+	if err != nil {
+		return fmt.Errorf("failed to derive fallback key: %w", err)
+	}
+	if _, err := aes.NewCipher(key); err != nil {
+		return fmt.Errorf("invalid fallback key: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fallbacks = append(e.fallbacks, key)
+	return nil
+}
+
 // generateKey creates the encryption key by concatenating two MD5 hashes:
 // MD5(accessKey + MAGIC) + MD5(MAGIC + password)
 func generateKey(magic []byte, accessKey, password string) []byte {
@@ -78,8 +169,34 @@ func (e *Encryptor) Encrypt(data string) (string, error) {
 }
 
 // Decrypt decrypts base64-encoded data using AES-256-ECB.
+// If fallback keys are registered and the primary key does not yield valid UTF-8 text,
+// each fallback is tried in order; if none does, the primary key's output is returned.
 func (e *Encryptor) Decrypt(data string) (string, error) {
-	block, err := aes.NewCipher(e.key)
+	plaintext, err := decryptWithKey(e.key, data)
+	if err != nil {
+		return "", err
+	}
+
+	e.mu.RLock()
+	fallbacks := e.fallbacks
+	e.mu.RUnlock()
+
+	if len(fallbacks) == 0 || plausibleText(plaintext) {
+		return plaintext, nil
+	}
+
+	for _, key := range fallbacks {
+		candidate, err := decryptWithKey(key, data)
+		if err == nil && plausibleText(candidate) {
+			return candidate, nil
+		}
+	}
+
+	return plaintext, nil
+}
+
+func decryptWithKey(key []byte, data string) (string, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return "", fmt.Errorf("failed to create cipher: %w", err)
 	}
@@ -89,11 +206,11 @@ func (e *Encryptor) Decrypt(data string) (string, error) {
 		return "", fmt.Errorf("failed to decode base64: %w", err)
 	}
 
-	// Add zero-padding if needed (from JS implementation)
-	paddingLength := len(ciphertext) % 8
-	if paddingLength != 0 {
-		padding := make([]byte, paddingLength)
-		ciphertext = append(ciphertext, padding...)
+	// ECB works on whole blocks; anything else is not a payload we encrypted, e.g. a
+	// plain-text error body that happens to be valid base64. Empty data is the
+	// encryption of an empty string.
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("invalid ciphertext length %d (must be a multiple of %d)", len(ciphertext), aes.BlockSize)
 	}
 
 	plaintext := make([]byte, len(ciphertext))
@@ -104,6 +221,33 @@ func (e *Encryptor) Decrypt(data string) (string, error) {
 	return string(plaintext), nil
 }
 
+// plausibleText reports whether decrypted data, without its null padding, is valid
+// UTF-8 free of control characters other than whitespace. Output of a wrong key is
+// effectively random and fails this check.
+func plausibleText(s string) bool {
+	s = strings.TrimRight(s, "\x00")
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// DecryptBytes decrypts data like Decrypt and returns the plaintext as bytes, including
+// the null padding up to the block size. Use it for binary payloads, whose own trailing
+// null bytes DecryptAndStrip would remove along with the padding.
+func (e *Encryptor) DecryptBytes(data string) ([]byte, error) {
+	decrypted, err := e.Decrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decrypted), nil
+}
+
 // DecryptAndStrip decrypts data and removes trailing null byte padding.
 func (e *Encryptor) DecryptAndStrip(data string) (string, error) {
 	decrypted, err := e.Decrypt(data)
@@ -1,6 +1,8 @@
 package crypto
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -141,6 +143,27 @@ func TestDecryptWithPadding(t *testing.T) {
 	}
 }
 
+func TestDecryptBytes(t *testing.T) {
+	enc, err := NewEncryptor("123456789", "abcdefghij", "secret")
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+
+	plaintext := "\x00binary\x00\x00"
+	encrypted, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encryption failed: %v", err)
+	}
+
+	decrypted, err := enc.DecryptBytes(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptBytes failed: %v", err)
+	}
+	if len(decrypted) != 16 || string(decrypted[:len(plaintext)]) != plaintext {
+		t.Errorf("Expected the padded plaintext with its null bytes, got %q", decrypted)
+	}
+}
+
 func TestDifferentCredentialsProduceDifferentKeys(t *testing.T) {
 	enc1, _ := NewEncryptor("123456789", "key1", "pass1")
 	enc2, _ := NewEncryptor("123456789", "key2", "pass1")
@@ -157,6 +180,95 @@ func TestDifferentCredentialsProduceDifferentKeys(t *testing.T) {
 	}
 }
 
+type fixedKeyDeriver struct {
+	key []byte
+}
+
+func (d fixedKeyDeriver) DeriveKey(serialNumber, accessKey, password string) ([]byte, error) {
+	return d.key, nil
+}
+
+func TestCustomKeyDeriver(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	enc, err := NewEncryptorWithDeriver(fixedKeyDeriver{key: key}, "123456789", "abcdefghij", "secret")
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+
+	if !bytes.Equal(enc.key, key) {
+		t.Errorf("Expected deriver key to be used, got %x", enc.key)
+	}
+
+	plaintext := `{"value":21.5}`
+	encrypted, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := enc.DecryptAndStrip(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted != plaintext {
+		t.Errorf("Round trip failed: got %q", decrypted)
+	}
+
+	if _, err := NewEncryptorWithDeriver(fixedKeyDeriver{key: []byte("short")}, "", "", ""); err == nil {
+		t.Error("Expected error for invalid key length")
+	}
+}
+
+func TestDefaultKeyDeriverMatchesNewEncryptor(t *testing.T) {
+	deriver, err := DefaultKeyDeriver()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := deriver.DeriveKey("123456789", "abcdefghij", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, _ := NewEncryptor("123456789", "abcdefghij", "secret")
+	if !bytes.Equal(enc.key, key) {
+		t.Error("DefaultKeyDeriver does not match NewEncryptor key")
+	}
+}
+
+func TestNewEncryptorWithMagic(t *testing.T) {
+	magic := strings.Repeat("a5", 32)
+
+	enc, err := NewEncryptorWithMagic(magic, "123456789", "abcdefghij", "secret")
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+
+	standard, _ := NewEncryptor("123456789", "abcdefghij", "secret")
+	if bytes.Equal(enc.key, standard.key) {
+		t.Error("Custom magic produced the default key")
+	}
+
+	plaintext := `{"value":21.5}`
+	encrypted, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := enc.DecryptAndStrip(encrypted)
+	if err != nil || decrypted != plaintext {
+		t.Errorf("Round trip failed: got %q (err %v)", decrypted, err)
+	}
+
+	if def, err := NewEncryptorWithMagic(magicHex, "123456789", "abcdefghij", "secret"); err != nil || !bytes.Equal(def.key, standard.key) {
+		t.Errorf("Default magic must match NewEncryptor (err %v)", err)
+	}
+
+	for _, bad := range []string{"zz", strings.Repeat("a5", 16)} {
+		if _, err := NewEncryptorWithMagic(bad, "123456789", "abcdefghij", "secret"); err == nil {
+			t.Errorf("Expected error for magic %q", bad)
+		}
+	}
+}
+
 func BenchmarkEncrypt(b *testing.B) {
 	enc, _ := NewEncryptor("123456789", "abcdefghij", "secret")
 	plaintext := `{"temperature":21.5,"status":"on","mode":"manual"}`
@@ -183,3 +295,68 @@ func BenchmarkDecrypt(b *testing.B) {
 		}
 	}
 }
+
+func TestDecryptWithFallbackKey(t *testing.T) {
+	old, err := NewEncryptor("123456789", "abcdefghij", "oldpass")
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	plaintext := `{"id":"/ecus/rrc/uiStatus","value":{"IHT":"20.50"}}`
+	cached, err := old.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	enc, err := NewEncryptor("123456789", "abcdefghij", "newpass")
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+
+	if got, _ := enc.DecryptAndStrip(cached); got == plaintext {
+		t.Fatal("Primary key unexpectedly decrypted data of the old password")
+	}
+
+	if err := enc.AddFallbackKey("123456789", "abcdefghij", "oldpass"); err != nil {
+		t.Fatalf("AddFallbackKey failed: %v", err)
+	}
+
+	got, err := enc.DecryptAndStrip(cached)
+	if err != nil {
+		t.Fatalf("DecryptAndStrip failed: %v", err)
+	}
+	if got != plaintext {
+		t.Errorf("Expected fallback key to decrypt %q, got %q", plaintext, got)
+	}
+
+	// The primary key is still preferred and always used for encryption.
+	current, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if current == cached {
+		t.Error("Encrypt must use the primary key, not the fallback")
+	}
+	if got, err := enc.DecryptAndStrip(current); err != nil || got != plaintext {
+		t.Errorf("Expected primary key round trip, got %q (err %v)", got, err)
+	}
+}
+
+func TestDecryptRejectsPartialBlocks(t *testing.T) {
+	enc, err := NewEncryptor("123456789", "abcdefghij", "password")
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	if err := enc.AddFallbackKey("123456789", "abcdefghij", "oldpass"); err != nil {
+		t.Fatalf("AddFallbackKey failed: %v", err)
+	}
+
+	// Valid base64 that does not decode to whole AES blocks, such as plain-text error bodies.
+	for _, data := range []string{"abcd", "Unauthorized", "NotFound"} {
+		if _, err := enc.Decrypt(data); err == nil {
+			t.Errorf("Decrypt(%q): expected an error for a partial block", data)
+		}
+		if _, err := enc.DecryptAndStrip(data); err == nil {
+			t.Errorf("DecryptAndStrip(%q): expected an error for a partial block", data)
+		}
+	}
+}
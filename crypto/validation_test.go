@@ -7,6 +7,7 @@ import (
 
 // TestAgainstJSImplementation validates our encryption matches the JavaScript version
 // These test vectors would need to come from the actual JS implementation
+// or from a real device, e.g. recorded with `nefit --capture vectors.jsonl status`
 func TestAgainstJSImplementation(t *testing.T) {
 	t.Skip("TODO: Need actual test vectors from JS implementation with real credentials")
 
@@ -5,6 +5,7 @@ go 1.26
 require (
 	github.com/peterbourgon/ff/v3 v3.4.0
 	github.com/xmppo/go-xmpp v0.3.6
+	go.uber.org/goleak v1.3.0
 )
 
 require (
@@ -0,0 +1,51 @@
+// Package fixtures provides captured, anonymized backend payloads for decoder tests.
+//
+// Each fixture in testdata/ is the decrypted JSON document of one endpoint response,
+// as returned by Client.Get.
+package fixtures
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed testdata/*.json
+var files embed.FS
+
+// Raw returns the JSON document of the named fixture (file name without ".json").
+func Raw(name string) ([]byte, error) {
+	data, err := files.ReadFile("testdata/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown fixture %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// Load returns the named fixture decoded the way Client.Get decodes JSON responses.
+func Load(name string) (map[string]interface{}, error) {
+	data, err := Raw(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid fixture %q: %w", name, err)
+	}
+	return doc, nil
+}
+
+// Value returns the "value" object of the named fixture, e.g. the uiStatus fields.
+func Value(name string) (map[string]interface{}, error) {
+	doc, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := doc["value"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("fixture %q has no object value", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,119 @@
+// Package metrics renders device readings in the OpenMetrics text format, which
+// Prometheus scrapes as well, without depending on a Prometheus client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+// ContentType is the Content-Type to serve the output of WriteOpenMetrics with.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Option adds optional readings to the output of WriteOpenMetrics.
+type Option func(*options)
+
+type options struct {
+	modulation *float64
+}
+
+// WithModulation exports the burner modulation in percent, as returned by Client.Modulation.
+func WithModulation(percent float64) Option {
+	return func(o *options) {
+		o.modulation = &percent
+	}
+}
+
+// WriteOpenMetrics writes the readings of status and pressure to w as OpenMetrics gauges
+// prefixed with "nefit_", followed by the "# EOF" terminator. Either may be nil to leave
+// its metrics out. Temperatures are always exported in Celsius, whatever the unit of
+// status. The outdoor temperature is only written if status includes it.
+func WriteOpenMetrics(w io.Writer, status *types.Status, pressure *types.Pressure, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var b strings.Builder
+
+	if status != nil {
+		celsius := status
+		if status.Celsius != nil {
+			celsius = status.Celsius
+		}
+
+		gauge(&b, "nefit_indoor_temperature_celsius", "Indoor temperature measured by the thermostat.", celsius.InHouseTemp)
+		gauge(&b, "nefit_temperature_setpoint_celsius", "Room temperature setpoint in effect.", celsius.TempSetpoint)
+		gauge(&b, "nefit_manual_setpoint_celsius", "Room temperature setpoint of manual mode.", celsius.TempManualSetpoint)
+		if status.OutdoorSourceType != "" && !status.OutdoorTempSkipped {
+			gauge(&b, "nefit_outdoor_temperature_celsius", "Outdoor temperature.", celsius.OutdoorTemp)
+		}
+
+		if status.UserMode != "" {
+			labeledGauge(&b, "nefit_user_mode", "Heating user mode, 1 for the active one.", "mode", string(status.UserMode))
+		}
+		if status.BoilerIndicator != "" {
+			labeledGauge(&b, "nefit_boiler_indicator", "What the boiler is heating, 1 for the current state.", "state", status.BoilerIndicator)
+		}
+
+		gauge(&b, "nefit_hot_water_active", "Whether hot water is enabled.", boolValue(status.HotWaterActive))
+		gauge(&b, "nefit_temperature_override_active", "Whether a manual temperature override is on.", boolValue(status.TempOverride))
+		gauge(&b, "nefit_holiday_mode_active", "Whether holiday mode is on.", boolValue(status.HolidayMode))
+		gauge(&b, "nefit_fireplace_mode_active", "Whether fireplace mode is on.", boolValue(status.FireplaceMode))
+		gauge(&b, "nefit_powersave_mode_active", "Whether powersave mode is on.", boolValue(status.PowersaveMode))
+		if status.CHPumpActive != nil {
+			gauge(&b, "nefit_ch_pump_active", "Whether the central heating pump runs.", boolValue(*status.CHPumpActive))
+		}
+		if status.DHWPumpActive != nil {
+			gauge(&b, "nefit_dhw_pump_active", "Whether the hot water pump runs.", boolValue(*status.DHWPumpActive))
+		}
+		gauge(&b, "nefit_boiler_block", "Whether the boiler is blocked.", boolValue(status.BoilerBlock))
+		gauge(&b, "nefit_boiler_lock", "Whether the boiler is locked out.", boolValue(status.BoilerLock))
+		gauge(&b, "nefit_boiler_maintenance_required", "Whether the boiler asks for maintenance.", boolValue(status.BoilerMaintenance))
+	}
+
+	if pressure != nil {
+		gauge(&b, "nefit_system_pressure_bar", "Water pressure of the heating system.", pressure.Pressure)
+	}
+	if o.modulation != nil {
+		gauge(&b, "nefit_burner_modulation_percent", "Burner modulation (flame level), 0 while the burner is off.", *o.modulation)
+	}
+
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func gauge(b *strings.Builder, name, help string, value float64) {
+	writeHeader(b, name, help)
+	fmt.Fprintf(b, "%s %s\n", name, formatValue(value))
+}
+
+// labeledGauge writes a gauge with a single sample of 1 for the given label value.
+func labeledGauge(b *strings.Builder, name, help, label, value string) {
+	writeHeader(b, name, help)
+	fmt.Fprintf(b, "%s{%s=\"%s\"} 1\n", name, label, labelEscaper.Replace(value))
+}
+
+func writeHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# TYPE %s gauge\n# HELP %s %s\n", name, name, help)
+}
+
+// labelEscaper escapes label values as the exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kradalby/nefit-go/types"
+)
+
+var (
+	sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*"\})? (-?[0-9.e+-]+|NaN|[+-]Inf)$`)
+	typeLine   = regexp.MustCompile(`^# TYPE ([a-zA-Z_:][a-zA-Z0-9_:]*) (gauge|counter|info|stateset|unknown)$`)
+	helpLine   = regexp.MustCompile(`^# HELP ([a-zA-Z_:][a-zA-Z0-9_:]*) .+$`)
+)
+
+// parseExposition checks the structure of OpenMetrics text and returns its sample lines.
+func parseExposition(t *testing.T, text string) []string {
+	t.Helper()
+
+	if !strings.HasSuffix(text, "\n# EOF\n") {
+		t.Fatalf("Output does not end with # EOF:\n%s", text)
+	}
+
+	typed := map[string]bool{}
+	var samples []string
+	for i, line := range strings.Split(strings.TrimSuffix(text, "# EOF\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		if m := typeLine.FindStringSubmatch(line); m != nil {
+			if typed[m[1]] {
+				t.Errorf("Line %d: metric %s declared twice", i+1, m[1])
+			}
+			typed[m[1]] = true
+			continue
+		}
+		if m := helpLine.FindStringSubmatch(line); m != nil {
+			if !typed[m[1]] {
+				t.Errorf("Line %d: HELP for %s before its TYPE", i+1, m[1])
+			}
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("Line %d does not parse: %q", i+1, line)
+			continue
+		}
+		if !typed[m[1]] {
+			t.Errorf("Line %d: sample of %s without TYPE", i+1, m[1])
+		}
+		samples = append(samples, line)
+	}
+	return samples
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	pumpOn := true
+	status := &types.Status{
+		UserMode:          types.UserModeClock,
+		InHouseTemp:       69.8,
+		TempSetpoint:      70.7,
+		HotWaterActive:    true,
+		BoilerIndicator:   "central heating",
+		CHPumpActive:      &pumpOn,
+		OutdoorTemp:       41,
+		OutdoorSourceType: "virtual",
+		TemperatureUnit:   types.Fahrenheit,
+		Celsius: &types.Status{
+			InHouseTemp:  21,
+			TempSetpoint: 21.5,
+			OutdoorTemp:  5,
+		},
+	}
+	pressure := &types.Pressure{Pressure: 1.6, Unit: "bar"}
+
+	var b strings.Builder
+	if err := WriteOpenMetrics(&b, status, pressure); err != nil {
+		t.Fatalf("WriteOpenMetrics failed: %v", err)
+	}
+
+	samples := parseExposition(t, b.String())
+	for _, want := range []string{
+		"nefit_indoor_temperature_celsius 21",
+		"nefit_temperature_setpoint_celsius 21.5",
+		"nefit_outdoor_temperature_celsius 5",
+		`nefit_user_mode{mode="clock"} 1`,
+		`nefit_boiler_indicator{state="central heating"} 1`,
+		"nefit_hot_water_active 1",
+		"nefit_ch_pump_active 1",
+		"nefit_boiler_lock 0",
+		"nefit_system_pressure_bar 1.6",
+	} {
+		found := false
+		for _, s := range samples {
+			if s == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Missing sample %q in output:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestWriteOpenMetricsPartial(t *testing.T) {
+	var b strings.Builder
+	if err := WriteOpenMetrics(&b, nil, &types.Pressure{Pressure: 1.2}); err != nil {
+		t.Fatalf("WriteOpenMetrics failed: %v", err)
+	}
+	if samples := parseExposition(t, b.String()); len(samples) != 1 || samples[0] != "nefit_system_pressure_bar 1.2" {
+		t.Errorf("Expected only the pressure without a status, got %v", samples)
+	}
+
+	b.Reset()
+	if err := WriteOpenMetrics(&b, &types.Status{UserMode: types.UserModeManual}, nil); err != nil {
+		t.Fatalf("WriteOpenMetrics failed: %v", err)
+	}
+	out := b.String()
+	parseExposition(t, out)
+	if strings.Contains(out, "pressure") || strings.Contains(out, "outdoor") || strings.Contains(out, "pump") {
+		t.Errorf("Expected no pressure, outdoor temperature or pump metrics, got:\n%s", out)
+	}
+}
+
+func TestWriteOpenMetricsModulation(t *testing.T) {
+	var b strings.Builder
+	if err := WriteOpenMetrics(&b, nil, nil, WithModulation(42.5)); err != nil {
+		t.Fatalf("WriteOpenMetrics failed: %v", err)
+	}
+	if samples := parseExposition(t, b.String()); len(samples) != 1 || samples[0] != "nefit_burner_modulation_percent 42.5" {
+		t.Errorf("Expected only the modulation, got %v", samples)
+	}
+
+	b.Reset()
+	if err := WriteOpenMetrics(&b, nil, nil); err != nil {
+		t.Fatalf("WriteOpenMetrics failed: %v", err)
+	}
+	if strings.Contains(b.String(), "modulation") {
+		t.Errorf("Expected no modulation without WithModulation, got:\n%s", b.String())
+	}
+}
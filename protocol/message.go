@@ -20,28 +20,119 @@ type HTTPResponse struct {
 	ContentType string
 }
 
+// DefaultUserAgent is the User-Agent header value used by the official app.
+const DefaultUserAgent = "NefitEasy"
+
+// DefaultHTTPVersion is the protocol version sent in the request line.
+const DefaultHTTPVersion = "HTTP/1.1"
+
+// AcceptJSON is the Accept header value asking the device for JSON responses.
+const AcceptJSON = "application/json"
+
+// MessageBuilder constructs HTTP-over-XMPP requests with configurable headers.
+// The zero value matches the package-level BuildGetMessage and BuildPutMessage.
+type MessageBuilder struct {
+	// UserAgent is sent in the User-Agent header (default DefaultUserAgent).
+	UserAgent string
+
+	// HTTPVersion is sent in the request line (default DefaultHTTPVersion).
+	// Some devices expect "HTTP/1.0".
+	HTTPVersion string
+
+	// Accept, if set, is sent in an Accept header, e.g. AcceptJSON.
+	// No Accept header is sent by default.
+	Accept string
+}
+
+func (b MessageBuilder) userAgent() string {
+	if b.UserAgent == "" {
+		return DefaultUserAgent
+	}
+	return b.UserAgent
+}
+
+func (b MessageBuilder) httpVersion() string {
+	if b.HTTPVersion == "" {
+		return DefaultHTTPVersion
+	}
+	return b.HTTPVersion
+}
+
+func (b MessageBuilder) acceptHeader() string {
+	if b.Accept == "" {
+		return ""
+	}
+	return "Accept: " + b.Accept + "\r"
+}
+
 // BuildGetMessage constructs an HTTP GET request wrapped in an XMPP message stanza.
-func BuildGetMessage(from, to, uri string) string {
-	body := fmt.Sprintf("GET %s HTTP/1.1\rUser-Agent: NefitEasy\r\r", uri)
+func (b MessageBuilder) BuildGetMessage(from, to, uri string) string {
+	body := fmt.Sprintf("GET %s %s\r%sUser-Agent: %s\r\r", EncodeURI(uri), b.httpVersion(), b.acceptHeader(), b.userAgent())
 	return buildXMPPMessage(from, to, body)
 }
 
 // BuildPutMessage constructs an HTTP PUT request wrapped in an XMPP message stanza.
-func BuildPutMessage(from, to, uri string, encryptedData string) string {
+func (b MessageBuilder) BuildPutMessage(from, to, uri string, encryptedData string) string {
 	body := fmt.Sprintf(
-		"PUT %s HTTP/1.1\r"+
+		"PUT %s %s\r"+
+			"%s"+
 			"Content-Type: application/json\r"+
 			"Content-Length: %d\r"+
-			"User-Agent: NefitEasy\r"+
+			"User-Agent: %s\r"+
 			"\r"+
 			"%s",
-		uri,
+		EncodeURI(uri),
+		b.httpVersion(),
+		b.acceptHeader(),
 		len(encryptedData),
+		b.userAgent(),
 		encryptedData,
 	)
 	return buildXMPPMessage(from, to, body)
 }
 
+// BuildGetMessage constructs an HTTP GET request wrapped in an XMPP message stanza.
+func BuildGetMessage(from, to, uri string) string {
+	return MessageBuilder{}.BuildGetMessage(from, to, uri)
+}
+
+// BuildPutMessage constructs an HTTP PUT request wrapped in an XMPP message stanza.
+func BuildPutMessage(from, to, uri string, encryptedData string) string {
+	return MessageBuilder{}.BuildPutMessage(from, to, uri, encryptedData)
+}
+
+// EncodeURI percent-encodes the characters of uri that are not allowed in an HTTP
+// request line, such as spaces, in both the path and the query. Existing escapes
+// ("%20") are kept, so already-encoded URIs are returned unchanged.
+func EncodeURI(uri string) string {
+	var sb strings.Builder
+	for i := 0; i < len(uri); i++ {
+		ch := uri[i]
+		switch {
+		case ch == '%' && i+2 < len(uri) && isHex(uri[i+1]) && isHex(uri[i+2]):
+			sb.WriteByte(ch)
+		case isURIChar(ch):
+			sb.WriteByte(ch)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", ch)
+		}
+	}
+	return sb.String()
+}
+
+// isURIChar reports whether ch may appear unescaped in a path or query (RFC 3986).
+func isURIChar(ch byte) bool {
+	switch {
+	case 'a' <= ch && ch <= 'z', 'A' <= ch && ch <= 'Z', '0' <= ch && ch <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~!$&'()*+,;=:@/?", ch) >= 0
+}
+
+func isHex(ch byte) bool {
+	return ('0' <= ch && ch <= '9') || ('a' <= ch && ch <= 'f') || ('A' <= ch && ch <= 'F')
+}
+
 func buildXMPPMessage(from, to, body string) string {
 	// Escape XML special characters in body, but preserve \r as &#13;\n for protocol
 	escapedBody := escapeXMLBody(body)
@@ -0,0 +1,95 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessageBuilderUserAgent(t *testing.T) {
+	b := MessageBuilder{UserAgent: "nefit-go/1.0"}
+
+	get := b.BuildGetMessage("from@host", "to@host", "/ecus/rrc/uiStatus")
+	if !strings.Contains(get, "User-Agent: nefit-go/1.0") {
+		t.Errorf("Expected custom User-Agent in GET message, got %q", get)
+	}
+
+	put := b.BuildPutMessage("from@host", "to@host", "/ecus/rrc/uiStatus", "payload")
+	if !strings.Contains(put, "User-Agent: nefit-go/1.0") {
+		t.Errorf("Expected custom User-Agent in PUT message, got %q", put)
+	}
+}
+
+func TestBuildGetMessageDefaultUserAgent(t *testing.T) {
+	msg := BuildGetMessage("from@host", "to@host", "/ecus/rrc/uiStatus")
+	if !strings.Contains(msg, "User-Agent: NefitEasy") {
+		t.Errorf("Expected default User-Agent, got %q", msg)
+	}
+}
+
+func TestMessageBuilderAcceptAndVersion(t *testing.T) {
+	b := MessageBuilder{HTTPVersion: "HTTP/1.0", Accept: AcceptJSON}
+
+	get := b.BuildGetMessage("from@host", "to@host", "/ecus/rrc/uiStatus")
+	if !strings.Contains(get, "GET /ecus/rrc/uiStatus HTTP/1.0&#13;\n") {
+		t.Errorf("Expected HTTP/1.0 request line in GET message, got %q", get)
+	}
+	if !strings.Contains(get, "Accept: application/json&#13;\n") {
+		t.Errorf("Expected Accept header in GET message, got %q", get)
+	}
+
+	put := b.BuildPutMessage("from@host", "to@host", "/ecus/rrc/uiStatus", "payload")
+	if !strings.Contains(put, "PUT /ecus/rrc/uiStatus HTTP/1.0&#13;\n") {
+		t.Errorf("Expected HTTP/1.0 request line in PUT message, got %q", put)
+	}
+	if !strings.Contains(put, "Accept: application/json&#13;\n") {
+		t.Errorf("Expected Accept header in PUT message, got %q", put)
+	}
+}
+
+func TestMessageBuilderDefaults(t *testing.T) {
+	get := BuildGetMessage("from@host", "to@host", "/ecus/rrc/uiStatus")
+	if !strings.Contains(get, "GET /ecus/rrc/uiStatus HTTP/1.1&#13;\n") {
+		t.Errorf("Expected HTTP/1.1 request line by default, got %q", get)
+	}
+	if strings.Contains(get, "Accept:") {
+		t.Errorf("Expected no Accept header by default, got %q", get)
+	}
+}
+
+func TestEncodeURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"/ecus/rrc/uiStatus", "/ecus/rrc/uiStatus"},
+		{"/ecus/rrc/recordings/gas usage?page=1", "/ecus/rrc/recordings/gas%20usage?page=1"},
+		{"/a b?name=x y&z=1", "/a%20b?name=x%20y&z=1"},
+		{"/already%20encoded", "/already%20encoded"},
+		{"/100%", "/100%25"},
+		{"/bad%zz", "/bad%25zz"},
+		{"/café", "/caf%C3%A9"},
+	}
+
+	for _, tt := range tests {
+		if got := EncodeURI(tt.uri); got != tt.want {
+			t.Errorf("EncodeURI(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+		if got := EncodeURI(tt.want); got != tt.want {
+			t.Errorf("EncodeURI(%q) = %q, want it unchanged", tt.want, got)
+		}
+	}
+}
+
+func TestBuildMessageEncodesURI(t *testing.T) {
+	uri := "/ecus/rrc/recordings/gas usage?page=1"
+
+	get := BuildGetMessage("from@host", "to@host", uri)
+	if !strings.Contains(get, "GET /ecus/rrc/recordings/gas%20usage?page=1 HTTP/1.1&#13;\n") {
+		t.Errorf("Expected encoded URI in GET request line, got %q", get)
+	}
+
+	put := BuildPutMessage("from@host", "to@host", uri, "payload")
+	if !strings.Contains(put, "PUT /ecus/rrc/recordings/gas%20usage?page=1 HTTP/1.1&#13;\n") {
+		t.Errorf("Expected encoded URI in PUT request line, got %q", put)
+	}
+}
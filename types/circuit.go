@@ -0,0 +1,29 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultCircuit is the heating circuit used when none is selected; single-zone
+// installations only have this one.
+const DefaultCircuit = "hc1"
+
+// heatingCircuitPrefix is the URI prefix of the default heating circuit endpoints.
+const heatingCircuitPrefix = "/heatingCircuits/" + DefaultCircuit + "/"
+
+var circuitPattern = regexp.MustCompile(`^hc[1-9][0-9]?$`)
+
+// ValidCircuit reports whether name is a heating circuit identifier such as "hc1" or "hc2".
+func ValidCircuit(name string) bool {
+	return circuitPattern.MatchString(name)
+}
+
+// CircuitURI returns uri for the given heating circuit. The heating circuit URI
+// constants address DefaultCircuit; other URIs and an empty circuit are returned unchanged.
+func CircuitURI(uri, circuit string) string {
+	if circuit == "" || circuit == DefaultCircuit || !strings.HasPrefix(uri, heatingCircuitPrefix) {
+		return uri
+	}
+	return "/heatingCircuits/" + circuit + "/" + strings.TrimPrefix(uri, heatingCircuitPrefix)
+}
@@ -0,0 +1,33 @@
+package types
+
+import "testing"
+
+func TestValidCircuit(t *testing.T) {
+	for _, name := range []string{"hc1", "hc2", "hc12"} {
+		if !ValidCircuit(name) {
+			t.Errorf("ValidCircuit(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"", "hc0", "hc", "HC1", "dhwA", "hc1/../x", "hc123"} {
+		if ValidCircuit(name) {
+			t.Errorf("ValidCircuit(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestCircuitURI(t *testing.T) {
+	tests := []struct {
+		uri, circuit, want string
+	}{
+		{URIManualSetpoint, "hc2", "/heatingCircuits/hc2/temperatureRoomManual"},
+		{URIUserMode, "hc1", URIUserMode},
+		{URIUserMode, "", URIUserMode},
+		{URIStatus, "hc2", URIStatus},
+	}
+
+	for _, tt := range tests {
+		if got := CircuitURI(tt.uri, tt.circuit); got != tt.want {
+			t.Errorf("CircuitURI(%q, %q) = %q, want %q", tt.uri, tt.circuit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DurationMinutes converts d to the whole minutes the device uses for durations, such as
+// the override and fireplace durations, rounding up so a duration never ends early.
+// Negative and sub-minute durations are rejected.
+func DurationMinutes(d time.Duration) (int, error) {
+	if d < 0 {
+		return 0, fmt.Errorf("duration %v must not be negative", d)
+	}
+	if d < time.Minute {
+		return 0, fmt.Errorf("duration %v is shorter than the one minute resolution of the device", d)
+	}
+	return int(math.Ceil(d.Minutes())), nil
+}
+
+// MinutesDuration converts a device duration in minutes to a time.Duration.
+func MinutesDuration(minutes float64) time.Duration {
+	return time.Duration(minutes * float64(time.Minute))
+}
+
+// OverrideDuration returns TempOverrideDuration as a time.Duration.
+func (s *Status) OverrideDuration() time.Duration {
+	return MinutesDuration(float64(s.TempOverrideDuration))
+}
+
+// OverrideDurationString formats TempOverrideDuration as hours and minutes, e.g. "1h30m",
+// "45m" or "2h", and "0m" when no duration is set.
+func (s *Status) OverrideDurationString() string {
+	minutes := s.TempOverrideDuration
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+	}
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationMinutes(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     int
+		wantErr  bool
+	}{
+		{duration: time.Minute, want: 1},
+		{duration: 90 * time.Second, want: 2},
+		{duration: 2*time.Hour + time.Nanosecond, want: 121},
+		{duration: 45 * time.Minute, want: 45},
+		{duration: 59 * time.Second, wantErr: true},
+		{duration: 0, wantErr: true},
+		{duration: -time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := DurationMinutes(tt.duration)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("DurationMinutes(%v) = %d, want an error", tt.duration, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("DurationMinutes(%v) = %d, %v; want %d", tt.duration, got, err, tt.want)
+		}
+	}
+}
+
+func TestOverrideDurationString(t *testing.T) {
+	for minutes, want := range map[int]string{0: "0m", 45: "45m", 60: "1h", 90: "1h30m", 120: "2h"} {
+		s := &Status{TempOverrideDuration: minutes}
+		if got := s.OverrideDurationString(); got != want {
+			t.Errorf("OverrideDurationString(%d) = %q, want %q", minutes, got, want)
+		}
+		if got := s.OverrideDuration(); got != time.Duration(minutes)*time.Minute {
+			t.Errorf("OverrideDuration(%d) = %v", minutes, got)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Fault is one entry of the appliance fault log.
+type Fault struct {
+	// Time is when the fault occurred, in device local time; zero if the entry has no
+	// readable timestamp.
+	Time        time.Time `json:"time"`
+	DisplayCode string    `json:"display_code"`         // Code shown on the appliance, e.g. "6A"
+	CauseCode   int       `json:"cause_code,omitempty"` // Detail code qualifying the display code
+	Description string    `json:"description,omitempty"`
+}
+
+// faultTimeLayouts are the timestamp formats seen in fault log entries.
+var faultTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	RecordingDateLayout + " 15:04",
+}
+
+// faultDescriptions maps display codes (as reported by URIDisplayCode and in the fault
+// log) to a short description. Codes not listed are left undescribed.
+var faultDescriptions = map[string]string{
+	"-H":  "central heating active",
+	"=H":  "hot water active",
+	"0H":  "standby, no heat demand",
+	"0Y":  "waiting, supply temperature above setpoint",
+	"2E":  "water pressure too low",
+	"6A":  "burner did not ignite",
+	"H07": "water pressure too low",
+}
+
+// DescribeFault returns the description of a display code, or "" if it is unknown.
+func DescribeFault(displayCode string) string {
+	return faultDescriptions[displayCode]
+}
+
+// ParseFaultLog decodes a fault log response ({"value": [{"t": ..., "dcd": ..., "ccd": ...}]}),
+// keeping the order of the device. Entries without a display code are skipped; an empty
+// or missing list yields no faults.
+func ParseFaultLog(data interface{}) ([]Fault, error) {
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected fault log response type: %T", data)
+	}
+
+	values, _ := dataMap["value"].([]interface{})
+	faults := make([]Fault, 0, len(values))
+
+	for _, v := range values {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		code := lookupString(entry, "dcd")
+		if code == "" {
+			continue
+		}
+
+		fault := Fault{
+			Time:        parseFaultTime(lookupString(entry, "t")),
+			DisplayCode: code,
+			Description: DescribeFault(code),
+		}
+		fault.CauseCode, _ = LookupInt(entry, "ccd")
+		faults = append(faults, fault)
+	}
+
+	return faults, nil
+}
+
+func parseFaultTime(s string) time.Time {
+	for _, layout := range faultTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
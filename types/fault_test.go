@@ -0,0 +1,47 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFaultLog(t *testing.T) {
+	doc := map[string]interface{}{
+		"id": URIFaultLog,
+		"value": []interface{}{
+			map[string]interface{}{"t": "2024-01-14T08:12:00", "dcd": "6A", "ccd": float64(227)},
+			map[string]interface{}{"t": "03-02-2024 21:40", "dcd": "H07", "ccd": "1027"},
+			map[string]interface{}{"t": "garbage", "dcd": "9Z"},
+			map[string]interface{}{"t": "2024-02-05T10:00:00"},
+			"not an entry",
+		},
+	}
+
+	got, err := ParseFaultLog(doc)
+	if err != nil {
+		t.Fatalf("ParseFaultLog failed: %v", err)
+	}
+
+	want := []Fault{
+		{Time: time.Date(2024, time.January, 14, 8, 12, 0, 0, time.UTC), DisplayCode: "6A", CauseCode: 227, Description: "burner did not ignite"},
+		{Time: time.Date(2024, time.February, 3, 21, 40, 0, 0, time.UTC), DisplayCode: "H07", CauseCode: 1027, Description: "water pressure too low"},
+		{DisplayCode: "9Z"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFaultLog =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseFaultLogEmpty(t *testing.T) {
+	for _, doc := range []map[string]interface{}{{"value": []interface{}{}}, {"id": URIFaultLog}} {
+		faults, err := ParseFaultLog(doc)
+		if err != nil || len(faults) != 0 {
+			t.Errorf("ParseFaultLog(%v) = %v, %v; want no faults", doc, faults, err)
+		}
+	}
+
+	if _, err := ParseFaultLog("raw"); err == nil {
+		t.Error("Expected an error for a non-object response")
+	}
+}
@@ -0,0 +1,52 @@
+package types
+
+// statusKeys are uiStatus fields distinctive enough to identify the resource.
+var statusKeys = []string{"IHT", "IHS", "UMD", "TSP", "BAI", "CPM", "MMT", "TOR"}
+
+// InferURI guesses the endpoint a decoded response belongs to from its shape.
+// Push notifications do not always carry the "id" field naming their resource;
+// this recognises the common resources so they can still be labelled.
+// The id is used when present. It returns false if the shape is not recognised.
+func InferURI(data interface{}) (string, bool) {
+	doc, ok := data.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+
+	if id, ok := doc["id"].(string); ok && id != "" {
+		return id, true
+	}
+
+	switch value := doc["value"].(type) {
+	case map[string]interface{}:
+		for _, key := range statusKeys {
+			if _, ok := value[key]; ok {
+				return URIStatus, true
+			}
+		}
+	case []interface{}:
+		if len(value) > 0 {
+			if entry, ok := value[0].(map[string]interface{}); ok {
+				_, hasDay := entry["d"]
+				_, hasHotWater := entry["hw"]
+				_, hasHeating := entry["ch"]
+				if hasDay && (hasHotWater || hasHeating) {
+					return URIGasUsage, true
+				}
+			}
+		}
+	case string:
+		if value == "manual" || value == "clock" {
+			return URIUserMode, true
+		}
+	}
+
+	if unit, _ := doc["unitOfMeasure"].(string); unit == "bar" {
+		return URIPressure, true
+	}
+	if _, ok := doc["srcType"]; ok {
+		return URIOutdoorTemp, true
+	}
+
+	return "", false
+}
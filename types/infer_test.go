@@ -0,0 +1,39 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInferURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+		wantOK  bool
+	}{
+		{"id wins", `{"id":"/heatingCircuits/hc1/usermode","value":"clock"}`, URIUserMode, true},
+		{"ui status", `{"value":{"IHT":"20.50","UMD":"clock"}}`, URIStatus, true},
+		{"user mode", `{"type":"stringValue","value":"manual"}`, URIUserMode, true},
+		{"pressure", `{"value":1.6,"unitOfMeasure":"bar","minValue":0,"maxValue":25}`, URIPressure, true},
+		{"outdoor", `{"value":7.5,"unitOfMeasure":"C","srcType":"virtual"}`, URIOutdoorTemp, true},
+		{"gas usage", `{"value":[{"d":"01-01-2024","hw":1.0,"ch":5.0,"T":4.5}]}`, URIGasUsage, true},
+		{"unknown object", `{"value":{"foo":1}}`, "", false},
+		{"unknown scalar", `{"value":42}`, "", false},
+		{"not an object", `"text"`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data interface{}
+			if err := json.Unmarshal([]byte(tt.payload), &data); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := InferURI(data)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("InferURI(%s) = %q, %v; want %q, %v", tt.payload, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
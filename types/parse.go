@@ -0,0 +1,192 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseStatus maps the "value" object of a uiStatus response to a Status.
+// It performs no I/O, so it can also decode payloads captured elsewhere.
+// Absent fields are left zero; numeric fields that are present but malformed
+// are zero as well and listed in ParseWarnings.
+func ParseStatus(value map[string]interface{}) *Status {
+	fields := &fieldParser{m: value}
+	status := &Status{}
+	for _, k := range uiStatusKeys {
+		if k.parse != nil {
+			k.parse(status, fields, k.key)
+		}
+	}
+	status.ParseWarnings = fields.warnings
+
+	return status
+}
+
+// ParseQuickStatus maps only the QuickStatus fields of a uiStatus "value" object,
+// skipping the rest of the document. Malformed numbers are left zero without warnings.
+func ParseQuickStatus(value map[string]interface{}) *QuickStatus {
+	quick := &QuickStatus{
+		UserMode:     UserMode(lookupString(value, "UMD")),
+		TempOverride: parseBoolean(lookupString(value, "TOR")),
+	}
+	quick.InHouseTemp, _ = LookupFloat(value, "IHT")
+	quick.TempSetpoint, _ = LookupFloat(value, "TSP")
+
+	return quick
+}
+
+// ParsePressure maps a systemPressure response to a Pressure.
+// Absent or malformed fields are left zero.
+func ParsePressure(doc map[string]interface{}) *Pressure {
+	pressure := &Pressure{
+		Unit: lookupString(doc, "unitOfMeasure"),
+	}
+	pressure.Pressure, _ = LookupFloat(doc, "value")
+	pressure.MinValue, _ = LookupFloat(doc, "minValue")
+	pressure.MaxValue, _ = LookupFloat(doc, "maxValue")
+
+	return pressure
+}
+
+// LookupFloat returns the numeric value of key, accepting numbers and numeric strings.
+// ok is false if the key is missing or its value is not a number.
+func LookupFloat(m map[string]interface{}, key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		return parseNumber(v)
+	}
+	return 0, false
+}
+
+// LookupInt returns the integer value of key, accepting numbers and integer strings.
+// ok is false if the key is missing or its value is not an integer.
+func LookupInt(m map[string]interface{}, key string) (int, bool) {
+	switch v := m[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case float32:
+		return int(v), true
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, false
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// parseNumber parses a numeric string, reporting whether it was a valid number.
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+func lookupString(m map[string]interface{}, key string) string {
+	s, _ := LookupString(m, key)
+	return s
+}
+
+// LookupString returns the string value of key.
+// ok is false if the key is missing or its value is not a string.
+func LookupString(m map[string]interface{}, key string) (string, bool) {
+	s, ok := m[key].(string)
+	return s, ok
+}
+
+// fieldParser reads numeric fields from a response and records the ones that are
+// present but malformed, so they are reported instead of silently becoming zero.
+type fieldParser struct {
+	m        map[string]interface{}
+	warnings []string
+}
+
+func (p *fieldParser) float(key string) float64 {
+	f, ok := LookupFloat(p.m, key)
+	if !ok {
+		p.warn(key)
+	}
+	return f
+}
+
+func (p *fieldParser) int(key string) int {
+	i, ok := LookupInt(p.m, key)
+	if !ok {
+		p.warn(key)
+	}
+	return i
+}
+
+func (p *fieldParser) str(key string) string {
+	return lookupString(p.m, key)
+}
+
+// onOff reads an "on"/"off" field; anything but "on" is false.
+func (p *fieldParser) onOff(key string) bool {
+	return parseBoolean(lookupString(p.m, key))
+}
+
+// optionalOnOff reads an "on"/"off" field that not every device reports; it is nil
+// if the key is absent or empty.
+func (p *fieldParser) optionalOnOff(key string) *bool {
+	val := lookupString(p.m, key)
+	if val == "" {
+		return nil
+	}
+	on := parseBoolean(val)
+	return &on
+}
+
+// warn records key as malformed unless it is absent or empty.
+func (p *fieldParser) warn(key string) {
+	v, present := p.m[key]
+	if !present || v == nil || v == "" {
+		return
+	}
+	p.warnings = append(p.warnings, fmt.Sprintf("%s: cannot parse %v as a number", key, v))
+}
+
+func parseBoolean(val string) bool {
+	return val == "on"
+}
+
+func parseBoilerIndicator(val string) string {
+	switch val {
+	case "CH":
+		return "central heating"
+	case "HW":
+		return "hot water"
+	case "No":
+		return "off"
+	default:
+		return val
+	}
+}
+
+// parseValvePosition spells out the circuit the three-way valve is switched to.
+func parseValvePosition(val string) string {
+	switch val {
+	case "CH":
+		return "central heating"
+	case "HW", "DHW":
+		return "hot water"
+	default:
+		return val
+	}
+}
@@ -0,0 +1,182 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStatusPresentFields(t *testing.T) {
+	status := ParseStatus(map[string]interface{}{
+		"UMD": "manual",
+		"IHT": "20.85",
+		"TSP": 21.5,
+		"CSP": "32",
+		"TOD": float64(90),
+		"BAI": "CH",
+		"DHW": "on",
+		"TOR": "off",
+		"FPA": "on",
+	})
+
+	if status.UserMode != "manual" {
+		t.Errorf("UserMode = %q, want manual", status.UserMode)
+	}
+	if status.InHouseTemp != 20.85 {
+		t.Errorf("InHouseTemp = %v, want 20.85 from a numeric string", status.InHouseTemp)
+	}
+	if status.TempSetpoint != 21.5 {
+		t.Errorf("TempSetpoint = %v, want 21.5 from a number", status.TempSetpoint)
+	}
+	if status.CurrentSwitchpoint != 32 || status.TempOverrideDuration != 90 {
+		t.Errorf("CurrentSwitchpoint/TempOverrideDuration = %d/%d, want 32/90",
+			status.CurrentSwitchpoint, status.TempOverrideDuration)
+	}
+	if status.BoilerIndicator != "central heating" {
+		t.Errorf("BoilerIndicator = %q, want central heating", status.BoilerIndicator)
+	}
+	if !status.HotWaterActive || status.TempOverride || !status.FPActive || !status.FireplaceMode {
+		t.Errorf("Unexpected booleans: %+v", status)
+	}
+	if len(status.ParseWarnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", status.ParseWarnings)
+	}
+}
+
+func TestParseStatusComponentState(t *testing.T) {
+	// Pump states are "on", "off" or "-" for not reported.
+	tests := []struct {
+		value   map[string]interface{}
+		chPump  string
+		dhwPump string
+		valve   string
+	}{
+		{value: map[string]interface{}{"CHP": "on", "DHP": "off", "TWV": "CH"}, chPump: "on", dhwPump: "off", valve: "central heating"},
+		{value: map[string]interface{}{"CHP": "off", "DHP": "on", "TWV": "HW"}, chPump: "off", dhwPump: "on", valve: "hot water"},
+		{value: map[string]interface{}{"TWV": "MID"}, chPump: "-", dhwPump: "-", valve: "MID"},
+		{value: map[string]interface{}{}, chPump: "-", dhwPump: "-"},
+	}
+
+	state := func(b *bool) string {
+		switch {
+		case b == nil:
+			return "-"
+		case *b:
+			return "on"
+		}
+		return "off"
+	}
+	for _, tt := range tests {
+		status := ParseStatus(tt.value)
+		chPump, dhwPump := state(status.CHPumpActive), state(status.DHWPumpActive)
+		if chPump != tt.chPump || dhwPump != tt.dhwPump || status.ThreeWayValve != tt.valve {
+			t.Errorf("ParseStatus(%v): pumps %s/%s valve %q, want %s/%s %q", tt.value,
+				chPump, dhwPump, status.ThreeWayValve, tt.chPump, tt.dhwPump, tt.valve)
+		}
+	}
+}
+
+func TestParseStatusAbsentFields(t *testing.T) {
+	status := ParseStatus(map[string]interface{}{})
+
+	if status.UserMode != "" || status.InHouseTemp != 0 || status.HotWaterActive || status.BoilerIndicator != "" {
+		t.Errorf("Expected zero status for empty value, got %+v", status)
+	}
+	if len(status.ParseWarnings) != 0 {
+		t.Errorf("Absent fields must not produce warnings, got %v", status.ParseWarnings)
+	}
+
+	if s := ParseStatus(map[string]interface{}{"IHT": "", "TSP": nil}); len(s.ParseWarnings) != 0 {
+		t.Errorf("Empty fields must not produce warnings, got %v", s.ParseWarnings)
+	}
+}
+
+func TestParseStatusWrongTypedFields(t *testing.T) {
+	status := ParseStatus(map[string]interface{}{
+		"UMD": 1.0,
+		"IHT": "n/a",
+		"CSP": true,
+		"DHW": true,
+		"BAI": "XX",
+	})
+
+	if status.UserMode != "" {
+		t.Errorf("UserMode = %q, want empty for a non-string", status.UserMode)
+	}
+	if status.InHouseTemp != 0 || status.CurrentSwitchpoint != 0 {
+		t.Errorf("Expected zero for malformed numbers, got IHT=%v CSP=%d", status.InHouseTemp, status.CurrentSwitchpoint)
+	}
+	if status.HotWaterActive {
+		t.Error("HotWaterActive must only be true for \"on\"")
+	}
+	if status.BoilerIndicator != "XX" {
+		t.Errorf("BoilerIndicator = %q, want unknown codes passed through", status.BoilerIndicator)
+	}
+
+	warnings := strings.Join(status.ParseWarnings, "; ")
+	if len(status.ParseWarnings) != 2 || !strings.Contains(warnings, "IHT") || !strings.Contains(warnings, "CSP") {
+		t.Errorf("Expected warnings for IHT and CSP, got %v", status.ParseWarnings)
+	}
+}
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  map[string]interface{}
+		want Pressure
+	}{
+		{
+			name: "present",
+			doc:  map[string]interface{}{"value": 1.6, "unitOfMeasure": "bar", "minValue": 0.0, "maxValue": 25.0},
+			want: Pressure{Pressure: 1.6, Unit: "bar", MaxValue: 25},
+		},
+		{
+			name: "absent",
+			doc:  map[string]interface{}{},
+			want: Pressure{},
+		},
+		{
+			name: "numeric strings",
+			doc:  map[string]interface{}{"value": "1.4", "maxValue": "3"},
+			want: Pressure{Pressure: 1.4, MaxValue: 3},
+		},
+		{
+			name: "wrong types",
+			doc:  map[string]interface{}{"value": "low", "unitOfMeasure": 1.0, "maxValue": true},
+			want: Pressure{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := ParsePressure(tt.doc); *got != tt.want {
+			t.Errorf("%s: ParsePressure = %+v, want %+v", tt.name, *got, tt.want)
+		}
+	}
+}
+
+// benchmarkStatus is a complete uiStatus value as sent by the backend.
+var benchmarkStatus = map[string]interface{}{
+	"UMD": "clock", "CPM": "auto", "IHS": "ok", "IHT": "20.85", "DHW": "on", "BAI": "CH",
+	"CTR": "room", "TOD": "0", "CSP": "32", "ESI": "off", "FPA": "off", "TOR": "off",
+	"HMD": "off", "BBE": "false", "BLE": "false", "BMR": "false", "TSP": "21.0",
+	"TOT": "21.0", "MMT": "19.5", "HED_EN": "false", "HED_DEV": "false",
+}
+
+func TestParseQuickStatus(t *testing.T) {
+	got := ParseQuickStatus(benchmarkStatus)
+	want := QuickStatus{UserMode: UserModeClock, InHouseTemp: 20.85, TempSetpoint: 21}
+	if *got != want {
+		t.Errorf("ParseQuickStatus = %+v, want %+v", *got, want)
+	}
+}
+
+func BenchmarkParseStatus(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParseStatus(benchmarkStatus)
+	}
+}
+
+func BenchmarkParseQuickStatus(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParseQuickStatus(benchmarkStatus)
+	}
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Minutes returns the switchpoint time as minutes after midnight.
+func (s ProgramSwitchpoint) Minutes() (int, error) {
+	t, err := time.Parse("15:04", s.Time)
+	if err != nil || len(s.Time) != 5 {
+		return 0, fmt.Errorf("invalid time %q (must be HH:MM)", s.Time)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks that every switchpoint has a valid day of week (0-6), HH:MM time and,
+// if it refers to one, a known preset.
+func (p *Program) Validate() error {
+	for i, sp := range p.Switchpoints {
+		if sp.DayOfWeek < 0 || sp.DayOfWeek > 6 {
+			return fmt.Errorf("switchpoint %d: invalid day_of_week %d (must be 0-6, 0=Sunday)", i, sp.DayOfWeek)
+		}
+		if _, err := sp.Minutes(); err != nil {
+			return fmt.Errorf("switchpoint %d: %w", i, err)
+		}
+		if sp.Preset != "" && sp.Preset != PresetComfort && sp.Preset != PresetEco {
+			return fmt.Errorf("switchpoint %d: invalid preset %q (must be %q or %q)", i, sp.Preset, PresetComfort, PresetEco)
+		}
+	}
+	return nil
+}
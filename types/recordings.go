@@ -0,0 +1,69 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordingDateLayout is the day format of recording entries ("dd-mm-yyyy").
+// Unused slots carry an invalid date such as "255-256-65535".
+const RecordingDateLayout = "02-01-2006"
+
+// Recording is a single daily entry from a /ecus/rrc/recordings/... resource.
+// Values holds the numeric fields of the entry keyed by their backend name
+// (for gas usage: "hw" hot water, "ch" central heating, "T" outdoor temperature).
+type Recording struct {
+	Date   time.Time          `json:"date"`
+	Values map[string]float64 `json:"values"`
+}
+
+// EnergyRecord is the electrical consumption of a single day.
+type EnergyRecord struct {
+	Date time.Time `json:"date"`
+	KWh  float64   `json:"kwh"`
+}
+
+// RecordingPage is one page of a recordings resource.
+type RecordingPage struct {
+	Entries []Recording `json:"entries"`
+}
+
+// Empty reports whether the page has no valid entries, which marks the end of the recordings.
+func (p *RecordingPage) Empty() bool {
+	return len(p.Entries) == 0
+}
+
+// ParseRecordingPage decodes a recordings page response ({"value": [{"d": "dd-mm-yyyy", ...}]}).
+// Entries with an invalid date (unused slots) are skipped.
+func ParseRecordingPage(data interface{}) (*RecordingPage, error) {
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected recordings response type: %T", data)
+	}
+
+	values, _ := dataMap["value"].([]interface{})
+	page := &RecordingPage{Entries: make([]Recording, 0, len(values))}
+
+	for _, v := range values {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		day, _ := entry["d"].(string)
+		date, err := time.Parse(RecordingDateLayout, day)
+		if err != nil {
+			continue
+		}
+
+		rec := Recording{Date: date, Values: make(map[string]float64, len(entry))}
+		for key, raw := range entry {
+			if f, ok := raw.(float64); ok {
+				rec.Values[key] = f
+			}
+		}
+		page.Entries = append(page.Entries, rec)
+	}
+
+	return page, nil
+}
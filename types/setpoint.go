@@ -0,0 +1,45 @@
+package types
+
+// SetpointReason names the layer that determines the effective setpoint of a Status.
+type SetpointReason string
+
+const (
+	// SetpointFireplace means fireplace mode is active; the thermostat keeps the last
+	// setpoint but ignores the room temperature.
+	SetpointFireplace SetpointReason = "fireplace"
+	// SetpointHoliday means a holiday period replaces the program.
+	SetpointHoliday SetpointReason = "holiday"
+	// SetpointOverride means a temporary override of the program is active.
+	SetpointOverride SetpointReason = "override"
+	// SetpointManual means the circuit is in manual mode.
+	SetpointManual SetpointReason = "manual"
+	// SetpointProgram means the clock program is followed.
+	SetpointProgram SetpointReason = "program"
+)
+
+// EffectiveSetpoint returns the temperature the thermostat is currently targeting and
+// the layer it comes from, applying the precedence fireplace > holiday > override >
+// manual mode > program. The device reports the setpoint of fireplace and holiday mode
+// only as TempSetpoint; the override and manual setpoints fall back to it when absent.
+// The temperature is in the unit of s.
+func (s *Status) EffectiveSetpoint() (float64, SetpointReason) {
+	switch {
+	case s.FireplaceMode:
+		return s.TempSetpoint, SetpointFireplace
+	case s.HolidayMode:
+		return s.TempSetpoint, SetpointHoliday
+	case s.TempOverride:
+		return nonZero(s.TempOverrideTempSetpoint, s.TempSetpoint), SetpointOverride
+	case s.UserMode == UserModeManual:
+		return nonZero(s.TempManualSetpoint, s.TempSetpoint), SetpointManual
+	default:
+		return s.TempSetpoint, SetpointProgram
+	}
+}
+
+func nonZero(v, fallback float64) float64 {
+	if v == 0 {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,40 @@
+package types
+
+import "testing"
+
+func TestEffectiveSetpoint(t *testing.T) {
+	base := Status{TempSetpoint: 20, TempOverrideTempSetpoint: 22, TempManualSetpoint: 18}
+
+	tests := []struct {
+		name       string
+		modify     func(*Status)
+		wantTemp   float64
+		wantReason SetpointReason
+	}{
+		{"program", func(s *Status) { s.UserMode = UserModeClock }, 20, SetpointProgram},
+		{"no user mode", func(s *Status) {}, 20, SetpointProgram},
+		{"manual", func(s *Status) { s.UserMode = UserModeManual }, 18, SetpointManual},
+		{"manual without setpoint", func(s *Status) { s.UserMode = UserModeManual; s.TempManualSetpoint = 0 }, 20, SetpointManual},
+		{"override in clock mode", func(s *Status) { s.UserMode = UserModeClock; s.TempOverride = true }, 22, SetpointOverride},
+		{"override beats manual", func(s *Status) { s.UserMode = UserModeManual; s.TempOverride = true }, 22, SetpointOverride},
+		{"override without setpoint", func(s *Status) { s.TempOverride = true; s.TempOverrideTempSetpoint = 0 }, 20, SetpointOverride},
+		{"holiday", func(s *Status) { s.HolidayMode = true }, 20, SetpointHoliday},
+		{"holiday beats override", func(s *Status) { s.HolidayMode = true; s.TempOverride = true; s.UserMode = UserModeManual }, 20, SetpointHoliday},
+		{"fireplace", func(s *Status) { s.FireplaceMode = true }, 20, SetpointFireplace},
+		{"fireplace beats all", func(s *Status) {
+			s.FireplaceMode = true
+			s.HolidayMode = true
+			s.TempOverride = true
+			s.UserMode = UserModeManual
+		}, 20, SetpointFireplace},
+	}
+
+	for _, tt := range tests {
+		s := base
+		tt.modify(&s)
+		temp, reason := s.EffectiveSetpoint()
+		if temp != tt.wantTemp || reason != tt.wantReason {
+			t.Errorf("%s: EffectiveSetpoint = %v, %s; want %v, %s", tt.name, temp, reason, tt.wantTemp, tt.wantReason)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package types
+
+import "strings"
+
+// ApplianceType classifies the heat source.
+type ApplianceType string
+
+const (
+	// ApplianceCombi heats tap water on demand; there is no cylinder.
+	ApplianceCombi ApplianceType = "combi"
+	// ApplianceSystem heats a separate hot water cylinder, or only the heating.
+	ApplianceSystem ApplianceType = "system"
+	// ApplianceHeatPump is a heat pump rather than a boiler.
+	ApplianceHeatPump ApplianceType = "heat pump"
+	// ApplianceUnknown is used when the device reports no or an unrecognized type.
+	ApplianceUnknown ApplianceType = "unknown"
+)
+
+// SystemInfo describes the installation. It is static, so the client reads it once.
+type SystemInfo struct {
+	ApplianceType ApplianceType `json:"appliance_type"`
+	// RawApplianceType is the type as reported by the device.
+	RawApplianceType string `json:"raw_appliance_type,omitempty"`
+	FuelType         string `json:"fuel_type,omitempty"`
+
+	// Circuits lists the installed heating, hot water and solar circuits, e.g. "hc1" or "dhwA".
+	Circuits []string `json:"circuits"`
+	// HotWater is true if a hot water circuit is installed.
+	HotWater bool `json:"hot_water"`
+	// Solar is true if a solar circuit is installed.
+	Solar bool `json:"solar"`
+}
+
+// ParseApplianceType classifies an appliance type string as reported by the device,
+// such as "combi", "CombiBoiler", "system boiler", "solo" or "heatpump". Matching
+// ignores case, spaces and underscores.
+func ParseApplianceType(s string) ApplianceType {
+	normalized := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(s))
+	switch {
+	case strings.Contains(normalized, "combi"):
+		return ApplianceCombi
+	case strings.Contains(normalized, "heatpump"):
+		return ApplianceHeatPump
+	case strings.Contains(normalized, "system"), strings.Contains(normalized, "solo"),
+		strings.Contains(normalized, "regular"):
+		return ApplianceSystem
+	default:
+		return ApplianceUnknown
+	}
+}
+
+// UpdateState is the firmware update state reported by the gateway.
+type UpdateState string
+
+const (
+	// UpdateIdle means the installed firmware is current.
+	UpdateIdle UpdateState = "idle"
+	// UpdateAvailable means a newer firmware can be installed.
+	UpdateAvailable UpdateState = "available"
+	// UpdateDownloading and UpdateInstalling mean an update is in progress.
+	UpdateDownloading UpdateState = "downloading"
+	UpdateInstalling  UpdateState = "installing"
+)
+
+// UpdateStatus reports the installed firmware and whether a newer one is available.
+type UpdateStatus struct {
+	CurrentVersion string `json:"current_version,omitempty"`
+	// AvailableVersion is the version offered for installation, empty if none is.
+	AvailableVersion string      `json:"available_version,omitempty"`
+	State            UpdateState `json:"state"`
+	// Available is set when the gateway reports an update ready to install.
+	Available bool `json:"available"`
+}
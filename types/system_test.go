@@ -0,0 +1,27 @@
+package types
+
+import "testing"
+
+func TestParseApplianceType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ApplianceType
+	}{
+		{"combi", ApplianceCombi},
+		{"CombiBoiler", ApplianceCombi},
+		{"combi_boiler", ApplianceCombi},
+		{"system boiler", ApplianceSystem},
+		{"Solo", ApplianceSystem},
+		{"regular", ApplianceSystem},
+		{"heatpump", ApplianceHeatPump},
+		{"Heat Pump", ApplianceHeatPump},
+		{"", ApplianceUnknown},
+		{"fuel cell", ApplianceUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseApplianceType(tt.input); got != tt.want {
+			t.Errorf("ParseApplianceType(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// TemperatureUnit selects the unit used for temperatures exposed by the client.
+// The device itself always reports and accepts Celsius.
+type TemperatureUnit string
+
+const (
+	Celsius    TemperatureUnit = "C"
+	Fahrenheit TemperatureUnit = "F"
+)
+
+// Valid reports whether u is a supported temperature unit.
+func (u TemperatureUnit) Valid() bool {
+	return u == Celsius || u == Fahrenheit
+}
+
+// FromCelsius converts a device (Celsius) temperature to unit u, rounded to one decimal.
+func (u TemperatureUnit) FromCelsius(c float64) float64 {
+	if u == Fahrenheit {
+		return roundTenth(c*9/5 + 32)
+	}
+	return c
+}
+
+// ToCelsius converts a temperature in unit u to Celsius for the device, rounded to one decimal.
+func (u TemperatureUnit) ToCelsius(v float64) float64 {
+	if u == Fahrenheit {
+		return roundTenth((v - 32) * 5 / 9)
+	}
+	return v
+}
+
+// DeltaFromCelsius converts a temperature difference in Kelvin to unit u, rounded to one decimal.
+func (u TemperatureUnit) DeltaFromCelsius(d float64) float64 {
+	if u == Fahrenheit {
+		return roundTenth(d * 9 / 5)
+	}
+	return d
+}
+
+// ParseTemperatureUnit parses "C"/"celsius" or "F"/"fahrenheit".
+func ParseTemperatureUnit(s string) (TemperatureUnit, error) {
+	switch s {
+	case "C", "c", "celsius":
+		return Celsius, nil
+	case "F", "f", "fahrenheit":
+		return Fahrenheit, nil
+	default:
+		return "", fmt.Errorf("invalid temperature unit %q (must be C or F)", s)
+	}
+}
+
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// InUnit returns a copy of the status with temperature fields converted from Celsius to unit u.
+// The original device values remain available via the Celsius field of the copy.
+func (s *Status) InUnit(u TemperatureUnit) *Status {
+	if u == "" || u == Celsius {
+		return s
+	}
+
+	converted := *s
+	converted.InHouseTemp = u.FromCelsius(s.InHouseTemp)
+	converted.TempSetpoint = u.FromCelsius(s.TempSetpoint)
+	converted.TempOverrideTempSetpoint = u.FromCelsius(s.TempOverrideTempSetpoint)
+	converted.TempManualSetpoint = u.FromCelsius(s.TempManualSetpoint)
+	converted.OutdoorTemp = u.FromCelsius(s.OutdoorTemp)
+	converted.TemperatureUnit = u
+	converted.Celsius = s
+
+	return &converted
+}
+
+// InUnit returns a copy of p with its switchpoint temperatures converted from Celsius to u.
+// Switchpoints that refer to a preset are left as they are.
+func (p *Program) InUnit(u TemperatureUnit) *Program {
+	if u == "" || u == Celsius {
+		return p
+	}
+
+	converted := *p
+	converted.Switchpoints = make([]ProgramSwitchpoint, len(p.Switchpoints))
+	for i, sp := range p.Switchpoints {
+		if sp.Preset == "" {
+			sp.Temperature = u.FromCelsius(sp.Temperature)
+		}
+		converted.Switchpoints[i] = sp
+	}
+	converted.TemperatureUnit = u
+
+	return &converted
+}
+
+// InUnit returns a copy of q with its temperatures converted from Celsius to u.
+func (q *QuickStatus) InUnit(u TemperatureUnit) *QuickStatus {
+	if u == "" || u == Celsius {
+		return q
+	}
+
+	converted := *q
+	converted.InHouseTemp = u.FromCelsius(q.InHouseTemp)
+	converted.TempSetpoint = u.FromCelsius(q.TempSetpoint)
+	converted.TemperatureUnit = u
+
+	return &converted
+}
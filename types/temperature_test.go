@@ -0,0 +1,67 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTemperatureUnitConversion(t *testing.T) {
+	tests := []struct {
+		celsius    float64
+		fahrenheit float64
+	}{
+		{0, 32},
+		{100, 212},
+		{21.5, 70.7},
+		{-40, -40},
+		{5, 41},
+	}
+
+	for _, tt := range tests {
+		if got := Fahrenheit.FromCelsius(tt.celsius); got != tt.fahrenheit {
+			t.Errorf("FromCelsius(%v) = %v, want %v", tt.celsius, got, tt.fahrenheit)
+		}
+		if got := Celsius.FromCelsius(tt.celsius); got != tt.celsius {
+			t.Errorf("Celsius.FromCelsius(%v) = %v, want unchanged", tt.celsius, got)
+		}
+	}
+}
+
+func TestTemperatureUnitDelta(t *testing.T) {
+	if got := Fahrenheit.DeltaFromCelsius(0.5); got != 0.9 {
+		t.Errorf("DeltaFromCelsius(0.5) = %v, want 0.9 without the 32° offset", got)
+	}
+	if got := Celsius.DeltaFromCelsius(0.5); got != 0.5 {
+		t.Errorf("Celsius.DeltaFromCelsius(0.5) = %v, want unchanged", got)
+	}
+}
+
+func TestTemperatureUnitRoundTrip(t *testing.T) {
+	// Celsius is rounded to one decimal (0.18°F), so a round trip may drift
+	// by at most one tenth of a degree Fahrenheit.
+	for _, f := range []float64{41, 60, 68, 70, 72.5, 86} {
+		c := Fahrenheit.ToCelsius(f)
+		if back := Fahrenheit.FromCelsius(c); math.Abs(back-f) > 0.1+1e-9 {
+			t.Errorf("Round trip %v°F -> %v°C -> %v°F", f, c, back)
+		}
+	}
+
+	if got := Fahrenheit.ToCelsius(70); got != 21.1 {
+		t.Errorf("ToCelsius(70) = %v, want 21.1", got)
+	}
+}
+
+func TestStatusInUnit(t *testing.T) {
+	s := &Status{InHouseTemp: 20, TempSetpoint: 21.5}
+
+	f := s.InUnit(Fahrenheit)
+	if f.InHouseTemp != 68 || f.TempSetpoint != 70.7 {
+		t.Errorf("Unexpected converted temperatures: %v, %v", f.InHouseTemp, f.TempSetpoint)
+	}
+	if f.Celsius != s || f.Celsius.InHouseTemp != 20 {
+		t.Error("Expected raw Celsius values to remain available")
+	}
+	if s.InUnit(Celsius) != s {
+		t.Error("Expected Celsius conversion to return the status unchanged")
+	}
+}
@@ -1,32 +1,126 @@
 package types
 
+import "time"
+
 // Status contains comprehensive heating system state including temperatures, modes, and diagnostics.
 type Status struct {
-	UserMode                 string  `json:"user_mode"`                     // "manual" or "clock"
-	ClockProgram             string  `json:"clock_program"`                 // Current program mode
-	InHouseStatus            string  `json:"in_house_status"`               // Status of in-house sensor
-	InHouseTemp              float64 `json:"in_house_temp"`                 // Current indoor temperature
-	HotWaterActive           bool    `json:"hot_water_active"`              // Hot water system status
-	BoilerIndicator          string  `json:"boiler_indicator"`              // "CH" (central heating), "HW" (hot water), "No" (off)
-	Control                  string  `json:"control"`                       // Control mode
-	TempOverrideDuration     int     `json:"temp_override_duration"`        // Minutes
-	CurrentSwitchpoint       int     `json:"current_switchpoint"`           // Current program switchpoint
-	PSActive                 bool    `json:"ps_active"`                     // Power save active
-	PowersaveMode            bool    `json:"powersave_mode"`                // Powersave mode enabled
-	FPActive                 bool    `json:"fp_active"`                     // Fireplace mode active
-	FireplaceMode            bool    `json:"fireplace_mode"`                // Fireplace mode enabled
-	TempOverride             bool    `json:"temp_override"`                 // Temperature override active
-	HolidayMode              bool    `json:"holiday_mode"`                  // Holiday mode active
-	BoilerBlock              bool    `json:"boiler_block"`                  // Boiler blocked
-	BoilerLock               bool    `json:"boiler_lock"`                   // Boiler locked
-	BoilerMaintenance        bool    `json:"boiler_maintenance"`            // Maintenance required
-	TempSetpoint             float64 `json:"temp_setpoint"`                 // Current temperature setpoint
-	TempOverrideTempSetpoint float64 `json:"temp_override_temp_setpoint"`   // Override temperature setpoint
-	TempManualSetpoint       float64 `json:"temp_manual_setpoint"`          // Manual mode setpoint
-	HEDEnabled               bool    `json:"hed_enabled"`                   // Home/Away detection enabled
-	HEDDeviceAtHome          bool    `json:"hed_device_at_home"`            // Device detected at home
-	OutdoorTemp              float64 `json:"outdoor_temp,omitempty"`        // Outdoor temperature (if requested)
-	OutdoorSourceType        string  `json:"outdoor_source_type,omitempty"` // Source of outdoor temp data
+	UserMode                 UserMode `json:"user_mode"`                     // "manual" or "clock"
+	ClockProgram             string   `json:"clock_program"`                 // Current program mode
+	InHouseStatus            string   `json:"in_house_status"`               // Status of in-house sensor
+	InHouseTemp              float64  `json:"in_house_temp"`                 // Current indoor temperature
+	HotWaterActive           bool     `json:"hot_water_active"`              // Hot water system status
+	BoilerIndicator          string   `json:"boiler_indicator"`              // "CH" (central heating), "HW" (hot water), "No" (off)
+	Control                  string   `json:"control"`                       // Control mode
+	TempOverrideDuration     int      `json:"temp_override_duration"`        // Minutes
+	CurrentSwitchpoint       int      `json:"current_switchpoint"`           // Current program switchpoint
+	PSActive                 bool     `json:"ps_active"`                     // Power save active
+	PowersaveMode            bool     `json:"powersave_mode"`                // Powersave mode enabled
+	FPActive                 bool     `json:"fp_active"`                     // Fireplace mode active
+	FireplaceMode            bool     `json:"fireplace_mode"`                // Fireplace mode enabled
+	TempOverride             bool     `json:"temp_override"`                 // Temperature override active
+	HolidayMode              bool     `json:"holiday_mode"`                  // Holiday mode active
+	BoilerBlock              bool     `json:"boiler_block"`                  // Boiler blocked
+	BoilerLock               bool     `json:"boiler_lock"`                   // Boiler locked
+	BoilerMaintenance        bool     `json:"boiler_maintenance"`            // Maintenance required
+	TempSetpoint             float64  `json:"temp_setpoint"`                 // Current temperature setpoint
+	TempOverrideTempSetpoint float64  `json:"temp_override_temp_setpoint"`   // Override temperature setpoint
+	TempManualSetpoint       float64  `json:"temp_manual_setpoint"`          // Manual mode setpoint
+	HEDEnabled               bool     `json:"hed_enabled"`                   // Home/Away detection enabled
+	HEDDeviceAtHome          bool     `json:"hed_device_at_home"`            // Device detected at home
+	CHPumpActive             *bool    `json:"ch_pump_active,omitempty"`      // Central heating pump running; nil if not reported
+	DHWPumpActive            *bool    `json:"dhw_pump_active,omitempty"`     // Hot water pump running; nil if not reported
+	ThreeWayValve            string   `json:"three_way_valve,omitempty"`     // "central heating" or "hot water"; other codes as reported
+	OutdoorTemp              float64  `json:"outdoor_temp,omitempty"`        // Outdoor temperature (if requested)
+	OutdoorSourceType        string   `json:"outdoor_source_type,omitempty"` // Source of outdoor temp data
+	// OutdoorTempSkipped is set when the outdoor temperature was requested but not
+	// fetched in time; the other fields are still valid.
+	OutdoorTempSkipped bool `json:"outdoor_temp_skipped,omitempty"`
+	// ParseWarnings lists fields that were present but could not be parsed; their
+	// values above are zero rather than a real reading.
+	ParseWarnings []string `json:"parse_warnings,omitempty"`
+
+	// TemperatureUnit is the unit of the temperature fields above (empty means Celsius).
+	TemperatureUnit TemperatureUnit `json:"temperature_unit,omitempty"`
+	// Celsius holds the unconverted device values when TemperatureUnit is not Celsius.
+	Celsius *Status `json:"-"`
+}
+
+// QuickStatus holds the uiStatus fields most often polled: the indoor temperature,
+// the active setpoint and the user mode. See Status for the full state.
+type QuickStatus struct {
+	UserMode     UserMode `json:"user_mode"`
+	InHouseTemp  float64  `json:"in_house_temp"`
+	TempSetpoint float64  `json:"temp_setpoint"`
+	TempOverride bool     `json:"temp_override"`
+
+	// TemperatureUnit is the unit of the temperature fields above (empty means Celsius).
+	TemperatureUnit TemperatureUnit `json:"temperature_unit,omitempty"`
+}
+
+// EfficiencySnapshot combines the boiler water temperatures with the burner modulation
+// to judge how efficiently the appliance runs.
+type EfficiencySnapshot struct {
+	Supply     float64 `json:"supply"`     // Actual supply (flow) temperature
+	Return     float64 `json:"return"`     // Return temperature
+	DeltaT     float64 `json:"delta_t"`    // Supply minus return, 0 if either is unavailable
+	Modulation float64 `json:"modulation"` // Burner modulation in percent
+	// Condensing is set when the burner is on and the return temperature is below
+	// CondensingReturnTemp, so the flue gas condenses and its latent heat is recovered.
+	Condensing bool `json:"condensing"`
+	// Efficiency is a rough estimate of the efficiency in percent from the return
+	// temperature, 0 if the return temperature is unavailable.
+	Efficiency float64 `json:"efficiency,omitempty"`
+	// Unavailable lists the readings the device did not provide.
+	Unavailable []string `json:"unavailable,omitempty"`
+
+	// TemperatureUnit is the unit of Supply, Return and DeltaT (empty means Celsius).
+	TemperatureUnit TemperatureUnit `json:"temperature_unit,omitempty"`
+}
+
+// CondensingReturnTemp is the return temperature in Celsius below which a condensing
+// boiler condenses the water vapour in its flue gas.
+const CondensingReturnTemp = 55.0
+
+// ThermostatState combines the measured room temperature with the setpoint the
+// thermostat is targeting, for external controllers that need to know when the boiler
+// will fire. Temperatures are in TemperatureUnit.
+type ThermostatState struct {
+	Measured float64        `json:"measured"`
+	Setpoint float64        `json:"setpoint"`
+	Reason   SetpointReason `json:"reason"`
+	// Hysteresis is the switching differential around the setpoint, 0 if the device
+	// does not report one. SwitchOn and SwitchOff are Setpoint minus and plus
+	// Hysteresis; the boiler fires below SwitchOn and stops above SwitchOff.
+	Hysteresis float64 `json:"hysteresis,omitempty"`
+	SwitchOn   float64 `json:"switch_on,omitempty"`
+	SwitchOff  float64 `json:"switch_off,omitempty"`
+
+	// TemperatureUnit is the unit of the temperature fields above (empty means Celsius).
+	TemperatureUnit TemperatureUnit `json:"temperature_unit,omitempty"`
+	// Unavailable lists the readings the device did not provide.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// FireplaceState reports fireplace mode, in which the thermostat keeps heating without
+// regard to the room temperature (e.g. while a stove heats the living room).
+type FireplaceState struct {
+	Active bool `json:"active"`
+	// Timed is set when the firmware supports a fireplace duration.
+	Timed bool `json:"timed"`
+	// Remaining is the time left while a timed fireplace mode is active.
+	Remaining time.Duration `json:"remaining,omitempty"`
+}
+
+// Pressure names, the keys of the map returned by Client.Pressures.
+const (
+	PressureSystem   = "system"
+	PressureHotWater = "dhw"
+)
+
+// PressureURIs maps each pressure name to the endpoint it is read from.
+var PressureURIs = map[string]string{
+	PressureSystem:   URIPressure,
+	PressureHotWater: URIHotWaterPressure,
 }
 
 // Pressure contains system pressure readings and valid operating ranges.
@@ -37,6 +131,29 @@ type Pressure struct {
 	MaxValue float64 `json:"max_value"`
 }
 
+// HeatingTemps contains the boiler water temperatures of the heating circuit.
+type HeatingTemps struct {
+	Supply         float64 `json:"supply"`          // Actual supply (flow) temperature
+	SupplySetpoint float64 `json:"supply_setpoint"` // Target supply temperature
+	Return         float64 `json:"return"`          // Return temperature
+	Delta          float64 `json:"delta"`           // Supply minus return, 0 if either is unavailable
+	// Unavailable lists the readings the device reported as not available.
+	Unavailable []string `json:"unavailable,omitempty"`
+
+	// TemperatureUnit is the unit of the temperature fields above (empty means Celsius).
+	TemperatureUnit TemperatureUnit `json:"temperature_unit,omitempty"`
+}
+
+// MaintenanceInfo contains the service schedule and usage counters of the appliance.
+type MaintenanceInfo struct {
+	// NextServiceDate is nil when no service date is set.
+	NextServiceDate *time.Time `json:"next_service_date,omitempty"`
+	BurnerStarts    int        `json:"burner_starts"`
+	OperatingHours  float64    `json:"operating_hours"`
+	// Unavailable lists the readings the appliance does not provide.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
 // HotWaterSupply contains hot water system operational status.
 type HotWaterSupply struct {
 	Active bool   `json:"active"`
@@ -55,12 +172,27 @@ type ProgramSwitchpoint struct {
 	DayOfWeek   int     `json:"day_of_week"` // 0=Sunday, 1=Monday, etc.
 	Time        string  `json:"time"`        // HH:MM format
 	Temperature float64 `json:"temperature"`
+	// Preset, if set, is the temperature level (PresetComfort or PresetEco) the switchpoint
+	// refers to instead of an absolute Temperature; see Client.ResolvedSchedule.
+	Preset string `json:"preset,omitempty"`
+}
+
+// ResolvedSwitchpoint is a switchpoint of the active program with its temperature in
+// effect, taken from the preset it refers to if any.
+type ResolvedSwitchpoint struct {
+	DayOfWeek   int     `json:"day_of_week"` // 0=Sunday, 1=Monday, etc.
+	Time        string  `json:"time"`        // HH:MM format
+	Temperature float64 `json:"temperature"`
+	Preset      string  `json:"preset,omitempty"` // The preset the temperature was resolved from
 }
 
 // Program defines a heating schedule with multiple temperature switchpoints.
 type Program struct {
 	Active       bool                 `json:"active"`
 	Switchpoints []ProgramSwitchpoint `json:"switchpoints"`
+
+	// TemperatureUnit is the unit of the switchpoint temperatures (empty means Celsius).
+	TemperatureUnit TemperatureUnit `json:"temperature_unit,omitempty"`
 }
 
 // GasUsage contains cumulative gas consumption readings.
@@ -71,6 +203,68 @@ type GasUsage struct {
 	Unit  string  `json:"unit"` // e.g., "m³"
 }
 
+// TempSample is a temperature reading at a point in time.
+type TempSample struct {
+	Time        time.Time `json:"time"`
+	Temperature float64   `json:"temperature"`
+}
+
+// Outdoor temperature sources accepted by SetOutdoorSource and reported in Status.OutdoorSourceType.
+const (
+	// OutdoorSourcePhysical is a sensor wired to the appliance.
+	OutdoorSourcePhysical = "physical"
+	// OutdoorSourceVirtual is internet weather data for the configured location.
+	OutdoorSourceVirtual = "virtual"
+)
+
+// Preset names accepted by SetTemperaturePreset.
+const (
+	PresetComfort = "comfort"
+	PresetEco     = "eco"
+	PresetManual  = "manual"
+)
+
+// Setpoint range accepted by the thermostat, in Celsius.
+const (
+	MinSetpoint = 5.0
+	MaxSetpoint = 30.0
+)
+
+// Hot water setpoint range assumed when the device does not report one, in Celsius.
+const (
+	MinHotWaterSetpoint = 30.0
+	MaxHotWaterSetpoint = 60.0
+)
+
+// Display brightness range accepted by the thermostat.
+const (
+	MinBrightness = 1
+	MaxBrightness = 10
+)
+
+// DisplaySettings holds the thermostat display configuration.
+type DisplaySettings struct {
+	Brightness int  `json:"brightness"` // MinBrightness-MaxBrightness
+	Standby    bool `json:"standby"`    // Display dims when idle
+}
+
+// Presets holds the named temperature levels of the thermostat.
+type Presets struct {
+	Comfort float64 `json:"comfort"`
+	Eco     float64 `json:"eco"`
+	Manual  float64 `json:"manual"`
+}
+
+// AwayState records the heating state saved by SetAway so ClearAway can restore it.
+// Temperatures are in Celsius as reported by the device.
+type AwayState struct {
+	UserMode         UserMode  `json:"user_mode"`
+	ManualSetpoint   float64   `json:"manual_setpoint"`
+	TempOverride     bool      `json:"temp_override"`
+	OverrideSetpoint float64   `json:"override_setpoint"`
+	Since            time.Time `json:"since"`
+}
+
 // SetTemperatureResult contains the outcome of a temperature setpoint change.
 type SetTemperatureResult struct {
 	Status             string  `json:"status"` // "ok" or error message
@@ -87,4 +281,22 @@ type RawResponse struct {
 	MinValue      interface{} `json:"minValue,omitempty"`
 	MaxValue      interface{} `json:"maxValue,omitempty"`
 	SrcType       string      `json:"srcType,omitempty"`
+	// ContentType is set when the response was not JSON; Value then holds the body as a string.
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// DeviceConfig is a snapshot of the writable settings of a device, for backup and restore
+// with ExportConfig and ImportConfig. Temperatures are in Celsius as reported by the device.
+// Nil and empty fields were not provided by the appliance and are not written on import.
+type DeviceConfig struct {
+	UserMode       UserMode         `json:"user_mode,omitempty"`
+	HotWaterSupply *bool            `json:"hot_water_supply,omitempty"`
+	OutdoorSource  string           `json:"outdoor_source,omitempty"`
+	Presets        *Presets         `json:"presets,omitempty"`
+	ActiveProgram  int              `json:"active_program,omitempty"`
+	Program1       *Program         `json:"program1,omitempty"`
+	Program2       *Program         `json:"program2,omitempty"`
+	Display        *DisplaySettings `json:"display,omitempty"`
+	// Location holds the latitude and longitude; the timezone is not stored.
+	Location *Location `json:"location,omitempty"`
 }
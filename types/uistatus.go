@@ -0,0 +1,222 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// uiStatusKey describes a documented uiStatus key: its readable name (the JSON name of
+// the matching Status field), how its value is decoded, the UIStatus field holding its
+// raw text and how it is parsed into Status. This table is the one place the keys are
+// listed; DecodeUIStatusKeys, UIStatus and ParseStatus are all driven by it.
+type uiStatusKey struct {
+	key    string
+	name   string
+	decode func(interface{}) interface{}
+	raw    func(*UIStatus) *string
+	parse  func(s *Status, p *fieldParser, key string)
+}
+
+// uiStatusKeys lists the uiStatus keys with a known meaning.
+var uiStatusKeys = []uiStatusKey{
+	{"UMD", "user_mode", keep, func(u *UIStatus) *string { return &u.UMD },
+		func(s *Status, p *fieldParser, key string) { s.UserMode = UserMode(p.str(key)) }},
+	{"CPM", "clock_program", keep, func(u *UIStatus) *string { return &u.CPM },
+		func(s *Status, p *fieldParser, key string) { s.ClockProgram = p.str(key) }},
+	{"IHS", "in_house_status", keep, func(u *UIStatus) *string { return &u.IHS },
+		func(s *Status, p *fieldParser, key string) { s.InHouseStatus = p.str(key) }},
+	{"IHT", "in_house_temp", decodeNumber, func(u *UIStatus) *string { return &u.IHT },
+		func(s *Status, p *fieldParser, key string) { s.InHouseTemp = p.float(key) }},
+	{"DHW", "hot_water_active", decodeSwitch, func(u *UIStatus) *string { return &u.DHW },
+		func(s *Status, p *fieldParser, key string) { s.HotWaterActive = p.onOff(key) }},
+	{"BAI", "boiler_indicator", decodeBoilerIndicator, func(u *UIStatus) *string { return &u.BAI },
+		func(s *Status, p *fieldParser, key string) { s.BoilerIndicator = parseBoilerIndicator(p.str(key)) }},
+	{"CTR", "control", keep, func(u *UIStatus) *string { return &u.CTR },
+		func(s *Status, p *fieldParser, key string) { s.Control = p.str(key) }},
+	{"CTD", "clock_time", keep, func(u *UIStatus) *string { return &u.CTD }, nil},
+	{"TOD", "temp_override_duration", decodeNumber, func(u *UIStatus) *string { return &u.TOD },
+		func(s *Status, p *fieldParser, key string) { s.TempOverrideDuration = p.int(key) }},
+	{"CSP", "current_switchpoint", decodeNumber, func(u *UIStatus) *string { return &u.CSP },
+		func(s *Status, p *fieldParser, key string) { s.CurrentSwitchpoint = p.int(key) }},
+	{"ESI", "powersave_mode", decodeSwitch, func(u *UIStatus) *string { return &u.ESI },
+		func(s *Status, p *fieldParser, key string) {
+			s.PowersaveMode = p.onOff(key)
+			s.PSActive = s.PowersaveMode
+		}},
+	{"FPA", "fireplace_mode", decodeSwitch, func(u *UIStatus) *string { return &u.FPA },
+		func(s *Status, p *fieldParser, key string) {
+			s.FireplaceMode = p.onOff(key)
+			s.FPActive = s.FireplaceMode
+		}},
+	{"TOR", "temp_override", decodeSwitch, func(u *UIStatus) *string { return &u.TOR },
+		func(s *Status, p *fieldParser, key string) { s.TempOverride = p.onOff(key) }},
+	{"HMD", "holiday_mode", decodeSwitch, func(u *UIStatus) *string { return &u.HMD },
+		func(s *Status, p *fieldParser, key string) { s.HolidayMode = p.onOff(key) }},
+	{"BBE", "boiler_block", decodeSwitch, func(u *UIStatus) *string { return &u.BBE },
+		func(s *Status, p *fieldParser, key string) { s.BoilerBlock = p.onOff(key) }},
+	{"BLE", "boiler_lock", decodeSwitch, func(u *UIStatus) *string { return &u.BLE },
+		func(s *Status, p *fieldParser, key string) { s.BoilerLock = p.onOff(key) }},
+	{"BMR", "boiler_maintenance", decodeSwitch, func(u *UIStatus) *string { return &u.BMR },
+		func(s *Status, p *fieldParser, key string) { s.BoilerMaintenance = p.onOff(key) }},
+	{"TSP", "temp_setpoint", decodeNumber, func(u *UIStatus) *string { return &u.TSP },
+		func(s *Status, p *fieldParser, key string) { s.TempSetpoint = p.float(key) }},
+	{"TOT", "temp_override_temp_setpoint", decodeNumber, func(u *UIStatus) *string { return &u.TOT },
+		func(s *Status, p *fieldParser, key string) { s.TempOverrideTempSetpoint = p.float(key) }},
+	{"MMT", "temp_manual_setpoint", decodeNumber, func(u *UIStatus) *string { return &u.MMT },
+		func(s *Status, p *fieldParser, key string) { s.TempManualSetpoint = p.float(key) }},
+	{"HED_EN", "hed_enabled", decodeSwitch, func(u *UIStatus) *string { return &u.HEDEn },
+		func(s *Status, p *fieldParser, key string) { s.HEDEnabled = p.onOff(key) }},
+	{"HED_DEV", "hed_device_at_home", decodeSwitch, func(u *UIStatus) *string { return &u.HEDDev },
+		func(s *Status, p *fieldParser, key string) { s.HEDDeviceAtHome = p.onOff(key) }},
+	{"CHP", "ch_pump_active", decodeSwitch, func(u *UIStatus) *string { return &u.CHP },
+		func(s *Status, p *fieldParser, key string) { s.CHPumpActive = p.optionalOnOff(key) }},
+	{"DHP", "dhw_pump_active", decodeSwitch, func(u *UIStatus) *string { return &u.DHP },
+		func(s *Status, p *fieldParser, key string) { s.DHWPumpActive = p.optionalOnOff(key) }},
+	{"TWV", "three_way_valve", decodeValvePosition, func(u *UIStatus) *string { return &u.TWV },
+		func(s *Status, p *fieldParser, key string) { s.ThreeWayValve = parseValvePosition(p.str(key)) }},
+}
+
+// uiStatusKeyIndex maps the uiStatus keys to their entry in uiStatusKeys.
+var uiStatusKeyIndex = func() map[string]*uiStatusKey {
+	index := make(map[string]*uiStatusKey, len(uiStatusKeys))
+	for i := range uiStatusKeys {
+		index[uiStatusKeys[i].key] = &uiStatusKeys[i]
+	}
+	return index
+}()
+
+// DecodeUIStatusKeys returns a copy of a uiStatus "value" object with the documented keys
+// renamed to readable names (those used by Status' JSON encoding) and their values decoded:
+// numeric strings become numbers, "on"/"off" and "true"/"false" become booleans and the
+// boiler indicator is spelled out. Unknown keys, and values that do not decode, are kept as they are.
+func DecodeUIStatusKeys(value map[string]interface{}) map[string]interface{} {
+	decoded := make(map[string]interface{}, len(value))
+	for key, v := range value {
+		k, ok := uiStatusKeyIndex[key]
+		if !ok {
+			decoded[key] = v
+			continue
+		}
+		decoded[k.name] = k.decode(v)
+	}
+	return decoded
+}
+
+func keep(v interface{}) interface{} {
+	return v
+}
+
+func decodeNumber(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		if f, ok := parseNumber(s); ok {
+			return f
+		}
+	}
+	return v
+}
+
+func decodeSwitch(v interface{}) interface{} {
+	switch v {
+	case "on", "true":
+		return true
+	case "off", "false":
+		return false
+	}
+	return v
+}
+
+func decodeBoilerIndicator(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return parseBoilerIndicator(s)
+	}
+	return v
+}
+
+func decodeValvePosition(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return parseValvePosition(s)
+	}
+	return v
+}
+
+// UIStatus is the uiStatus "value" object as reported by the device, without any
+// interpretation: every documented key is kept as its reported text, and keys the
+// library does not know are collected in Unknown, so fields added by new firmware are
+// not lost. See Status for the decoded form.
+type UIStatus struct {
+	UMD    string
+	CPM    string
+	IHS    string
+	IHT    string
+	DHW    string
+	BAI    string
+	CTR    string
+	CTD    string
+	TOD    string
+	CSP    string
+	ESI    string
+	FPA    string
+	TOR    string
+	HMD    string
+	BBE    string
+	BLE    string
+	BMR    string
+	TSP    string
+	TOT    string
+	MMT    string
+	HEDEn  string // HED_EN
+	HEDDev string // HED_DEV
+	CHP    string
+	DHP    string
+	TWV    string
+
+	// Unknown holds the keys not listed above with their raw JSON values.
+	Unknown map[string]json.RawMessage
+}
+
+// MarshalJSON encodes s as a uiStatus value object: the documented keys that are set,
+// as strings, and the keys in Unknown with their raw values.
+func (s UIStatus) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]json.RawMessage, len(uiStatusKeys)+len(s.Unknown))
+	for key, value := range s.Unknown {
+		doc[key] = value
+	}
+	for _, k := range uiStatusKeys {
+		value := *k.raw(&s)
+		if value == "" {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		doc[k.key] = encoded
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a uiStatus value object. String values are stored as they are;
+// other values of documented keys, such as numbers, are stored as their JSON text.
+func (s *UIStatus) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid uiStatus value: %w", err)
+	}
+
+	*s = UIStatus{}
+	for key, value := range raw {
+		k, ok := uiStatusKeyIndex[key]
+		if !ok {
+			if s.Unknown == nil {
+				s.Unknown = make(map[string]json.RawMessage)
+			}
+			s.Unknown[key] = value
+			continue
+		}
+		field := k.raw(s)
+		if err := json.Unmarshal(value, field); err != nil {
+			*field = string(value)
+		}
+	}
+	return nil
+}
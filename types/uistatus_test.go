@@ -0,0 +1,96 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeUIStatusKeys(t *testing.T) {
+	got := DecodeUIStatusKeys(map[string]interface{}{
+		"UMD":    "manual",
+		"IHT":    "20.85",
+		"TSP":    21.5,
+		"CSP":    "n/a",
+		"BAI":    "HW",
+		"DHW":    "on",
+		"TOR":    "off",
+		"BLE":    "true",
+		"HED_EN": "false",
+		"ARS":    "init",
+		"DAS":    "off",
+	})
+
+	want := map[string]interface{}{
+		"user_mode":           "manual",
+		"in_house_temp":       20.85,
+		"temp_setpoint":       21.5,
+		"current_switchpoint": "n/a",
+		"boiler_indicator":    "hot water",
+		"hot_water_active":    true,
+		"temp_override":       false,
+		"boiler_lock":         true,
+		"hed_enabled":         false,
+		"ARS":                 "init",
+		"DAS":                 "off",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeUIStatusKeys =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestUIStatusUnknownKeys(t *testing.T) {
+	var status UIStatus
+	err := json.Unmarshal([]byte(`{"UMD":"clock","IHT":"20.85","TSP":21.5,"ARS":"init","XYZ":{"a":1}}`), &status)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if status.UMD != "clock" || status.IHT != "20.85" || status.TSP != "21.5" {
+		t.Errorf("Unexpected known fields: %+v", status)
+	}
+	want := map[string]json.RawMessage{"ARS": json.RawMessage(`"init"`), "XYZ": json.RawMessage(`{"a":1}`)}
+	if !reflect.DeepEqual(status.Unknown, want) {
+		t.Errorf("Unknown = %s, want %s", status.Unknown, want)
+	}
+
+	var known UIStatus
+	if err := json.Unmarshal([]byte(`{"UMD":"manual"}`), &known); err != nil || known.Unknown != nil {
+		t.Errorf("Expected no unknown keys, got %v (err %v)", known.Unknown, err)
+	}
+}
+
+func TestUIStatusRoundTrip(t *testing.T) {
+	in := `{"ARS":"init","HED_EN":"on","IHT":"20.85","TSP":"21.5","UMD":"clock","XYZ":{"a":1}}`
+	var status UIStatus
+	if err := json.Unmarshal([]byte(in), &status); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	out, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(out) != in {
+		t.Errorf("Marshal = %s, want %s", out, in)
+	}
+}
+
+func TestUIStatusKeysMatchStatus(t *testing.T) {
+	// Every documented key is parsed by ParseStatus (except the clock time, which
+	// Status does not carry) under the JSON name DecodeUIStatusKeys uses.
+	names := map[string]bool{}
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(Status{})) {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		names[name] = true
+	}
+	for _, k := range uiStatusKeys {
+		if k.parse == nil && k.key != "CTD" {
+			t.Errorf("%s is not parsed into Status", k.key)
+		}
+		if k.parse != nil && !names[k.name] {
+			t.Errorf("%s: Status has no field named %q", k.key, k.name)
+		}
+	}
+}
@@ -5,13 +5,25 @@ const (
 	URIStatus      = "/ecus/rrc/uiStatus"
 	URIOutdoorTemp = "/system/sensors/temperatures/outdoor_t1"
 
+	// URIOutdoorSource selects where the outdoor temperature comes from; the outdoor
+	// temperature reports the active source in its "srcType" field.
+	URIOutdoorSource = "/system/sensors/outdoorTemperatureSource"
+
 	// Pressure endpoints
 	URIPressure = "/system/appliance/systemPressure"
+	// URIHotWaterPressure is the water pressure of the hot water circuit. Only systems
+	// with a separate hot water circuit pressure sensor expose it.
+	URIHotWaterPressure = "/dhwCircuits/dhwA/waterPressure"
 
 	// Hot water endpoints
 	URIHotWaterClockMode  = "/dhwCircuits/dhwA/dhwOperationClockMode"
 	URIHotWaterManualMode = "/dhwCircuits/dhwA/dhwOperationManualMode"
 
+	// Hot water temperature setpoints, one per user mode like the on/off endpoints.
+	// Responses carry the accepted range in minValue and maxValue.
+	URIHotWaterClockTemp  = "/dhwCircuits/dhwA/dhwTemperatureClockMode"
+	URIHotWaterManualTemp = "/dhwCircuits/dhwA/dhwTemperatureManualMode"
+
 	// User mode endpoints
 	// URIUserMode controls the heating operation mode.
 	// Valid values for PUT requests:
@@ -25,6 +37,13 @@ const (
 	URIManualSetpoint           = "/heatingCircuits/hc1/temperatureRoomManual"
 	URIManualTempOverrideStatus = "/heatingCircuits/hc1/manualTempOverride/status"
 	URIManualTempOverrideTemp   = "/heatingCircuits/hc1/manualTempOverride/temperature"
+	// URIManualTempOverrideDuration limits how long an override lasts, in minutes.
+	URIManualTempOverrideDuration = "/heatingCircuits/hc1/manualTempOverride/duration"
+
+	// Temperature preset endpoints
+	// The clock program refers to these levels instead of storing temperatures per switchpoint.
+	URIPresetComfort = "/heatingCircuits/hc1/temperatureLevels/comfort2"
+	URIPresetEco     = "/heatingCircuits/hc1/temperatureLevels/eco"
 
 	// Program endpoints
 	URIActiveProgram = "/ecus/rrc/userprogram/activeprogram"
@@ -35,16 +54,85 @@ const (
 	URILocationLatitude  = "/system/location/latitude"
 	URILocationLongitude = "/system/location/longitude"
 
+	// Clock endpoint
+	// The device wall-clock time as "2006-01-02T15:04:05" (local time, no zone); writable.
+	URIDateTime = "/gateway/DateTime"
+
+	// Firmware update endpoints, on appliances that report update availability.
+	// The state is one of the UpdateState values. They are read-only here: no capture
+	// confirms how an installation is started.
+	URIFirmwareVersion = "/gateway/versionFirmware"
+	URIUpdateVersion   = "/gateway/update/version"
+	URIUpdateState     = "/gateway/update/state"
+
+	// Display settings endpoints
+	// Brightness is a level within MinBrightness and MaxBrightness; standby ("on"/"off")
+	// controls whether the display dims after a period without interaction.
+	URIDisplayBrightness = "/ecus/rrc/display/brightness"
+	URIDisplayStandby    = "/ecus/rrc/display/standby"
+
 	// Display code endpoints
 	URIDisplayCode = "/system/appliance/displaycode"
 	URICauseCode   = "/system/appliance/causecode"
 
+	// URIFaultLog lists past faults with their timestamp ("t"), display code ("dcd") and
+	// cause code ("ccd"). Not every firmware exposes it.
+	URIFaultLog = "/ecus/rrc/errorlog"
+
+	// Maintenance endpoints
+	// The service date is "dd-mm-yyyy", or "not set" when no service interval is configured.
+	// The operating time is reported in minutes.
+	URINextServiceDate = "/system/appliance/nextServiceDate"
+	URIBurnerStarts    = "/heatSources/numberOfStarts"
+	URIOperatingTime   = "/heatSources/workingTime/totalSystem"
+
+	// System information endpoints
+	// The appliance type distinguishes combi boilers (hot water on demand) from system
+	// boilers and heat pumps; see ParseApplianceType. The circuit collections are refEnums
+	// listing the installed circuits.
+	URIApplianceType   = "/system/appliance/type"
+	URIFuelType        = "/system/appliance/fuelType"
+	URIHeatingCircuits = "/heatingCircuits"
+	URIDHWCircuits     = "/dhwCircuits"
+	URISolarCircuits   = "/solarCircuits"
+
+	// Boiler reset endpoint
+	// Writing "on" clears a resettable lockout (Status.BoilerLock), like the reset button on the appliance.
+	URIBoilerReset = "/system/appliance/reset"
+
 	// Gas usage endpoint
+	// Recordings are paged via a "?page=N" suffix (1-based). Each daily entry
+	// also carries the average outdoor temperature for that day.
 	URIGasUsage = "/ecus/rrc/recordings/gasusage"
 
-	// Fireplace mode endpoint
-	URIFireplaceMode = "/ecus/rrc/userprogram/fireplacefunction"
+	// Electricity usage endpoint
+	// Paged like URIGasUsage; daily entries carry the consumption in kWh under "el".
+	// Only newer appliances provide it, others answer 404.
+	URIElectricityUsage = "/ecus/rrc/recordings/electricityusage"
+
+	// URIPresence is the occupancy flag, "on" when someone is home and "off" when the
+	// house is empty. Not every firmware exposes it.
+	URIPresence = "/ecus/rrc/presence"
+
+	// Fireplace mode endpoints
+	// The mode is "on" or "off". On firmware with timed fireplace mode, the duration
+	// endpoint holds the run time in minutes and counts down while the mode is active.
+	URIFireplaceMode     = "/ecus/rrc/userprogram/fireplacefunction"
+	URIFireplaceDuration = "/ecus/rrc/userprogram/fireplacefunctionduration"
+
+	// Supply and return temperature endpoints
+	URISupplyTemp         = "/heatingCircuits/hc1/actualSupplyTemperature"
+	URISupplyTempSetpoint = "/heatingCircuits/hc1/supplyTemperatureSetpoint"
+	URIReturnTemp         = "/system/sensors/temperatures/return"
+
+	// URIRoomHysteresis is the switching differential of the room thermostat in Kelvin.
+	// Not every firmware exposes it.
+	URIRoomHysteresis = "/heatingCircuits/hc1/roomTemperatureHysteresis"
+
+	// URISensorTemperatures lists all temperature sensors as a refEnum.
+	URISensorTemperatures = "/system/sensors/temperatures"
 
-	// Supply temperature endpoint
-	URISupplyTemp = "/heatingCircuits/hc1/actualSupplyTemperature"
+	// Burner modulation endpoint
+	// Reports the current flame modulation in percent of the maximum burner power.
+	URIModulation = "/system/appliance/actualPower"
 )
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UserMode is the heating operation mode of a circuit.
+type UserMode string
+
+const (
+	// UserModeManual holds the temperature set by the user.
+	UserModeManual UserMode = "manual"
+	// UserModeClock follows the programmed heating schedule.
+	UserModeClock UserMode = "clock"
+)
+
+// Valid reports whether m is a mode accepted by the device. "off" is not one.
+func (m UserMode) Valid() bool {
+	return m == UserModeManual || m == UserModeClock
+}
+
+// ParseUserMode parses "manual" or "clock", ignoring case and surrounding whitespace.
+func ParseUserMode(s string) (UserMode, error) {
+	m := UserMode(strings.ToLower(strings.TrimSpace(s)))
+	if !m.Valid() {
+		return "", fmt.Errorf("invalid user mode %q (must be 'manual' or 'clock')", s)
+	}
+	return m, nil
+}
@@ -0,0 +1,44 @@
+package types
+
+import "testing"
+
+func TestParseUserMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    UserMode
+		wantErr bool
+	}{
+		{input: "manual", want: UserModeManual},
+		{input: "clock", want: UserModeClock},
+		{input: "Manual", want: UserModeManual},
+		{input: "  CLOCK\n", want: UserModeClock},
+		{input: "off", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "auto", wantErr: true},
+		{input: "man ual", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUserMode(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseUserMode(%q) = %q, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseUserMode(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestUserModeValid(t *testing.T) {
+	if !UserModeManual.Valid() || !UserModeClock.Valid() {
+		t.Error("Expected manual and clock to be valid")
+	}
+	for _, m := range []UserMode{"", "off", "Manual"} {
+		if m.Valid() {
+			t.Errorf("Expected %q to be invalid", m)
+		}
+	}
+}